@@ -459,6 +459,299 @@ func getMigrations() []Migration {
 				DROP TABLE IF EXISTS naval_units;
 			`,
 		},
+		{
+			Version:     "003_convoy_vp_boxes",
+			Description: "Create secret convoy VP marker boxes",
+			SQL: `
+				-- Convoy VP boxes table (секретная коробка VP конвоя немецкого игрока)
+				CREATE TABLE IF NOT EXISTS convoy_vp_boxes (
+					game_id UUID PRIMARY KEY REFERENCES games(id) ON DELETE CASCADE,
+					pool JSONB DEFAULT '[]', -- Array of undrawn marker values
+					markers JSONB DEFAULT '[]', -- Array of drawn markers
+					merchants_sunk INTEGER DEFAULT 0,
+					merchant_value NUMERIC(4, 2) DEFAULT 0.5,
+					created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+					updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+				);
+			`,
+			RollbackSQL: `
+				DROP TABLE IF EXISTS convoy_vp_boxes;
+			`,
+		},
+		{
+			Version:     "004_naval_units_sunk_by",
+			Description: "Track which unit sank a ship",
+			SQL: `
+				ALTER TABLE naval_units ADD COLUMN IF NOT EXISTS sunk_by VARCHAR(50);
+			`,
+			RollbackSQL: `
+				ALTER TABLE naval_units DROP COLUMN IF EXISTS sunk_by;
+			`,
+		},
+		{
+			Version:     "005_game_end_tracking",
+			Description: "Track emergency fuel and store victory breakdown",
+			SQL: `
+				ALTER TABLE naval_units ADD COLUMN IF NOT EXISTS emergency_fuel_turn INTEGER;
+				ALTER TABLE games ADD COLUMN IF NOT EXISTS victory_breakdown JSONB;
+			`,
+			RollbackSQL: `
+				ALTER TABLE games DROP COLUMN IF EXISTS victory_breakdown;
+				ALTER TABLE naval_units DROP COLUMN IF EXISTS emergency_fuel_turn;
+			`,
+		},
+		{
+			Version:     "006_weather_track",
+			Description: "Add weather track status to games",
+			SQL: `
+				ALTER TABLE games ADD COLUMN IF NOT EXISTS weather INTEGER DEFAULT 0;
+			`,
+			RollbackSQL: `
+				ALTER TABLE games DROP COLUMN IF EXISTS weather;
+			`,
+		},
+		{
+			Version:     "007_patrol_search_factors",
+			Description: "Record patrol-derived search factors",
+			SQL: `
+				ALTER TABLE unit_searches ADD COLUMN IF NOT EXISTS patrol_factors INTEGER DEFAULT 0;
+			`,
+			RollbackSQL: `
+				ALTER TABLE unit_searches DROP COLUMN IF EXISTS patrol_factors;
+			`,
+		},
+		{
+			Version:     "008_admin_phase",
+			Description: "Add No Movement markers and reinforcement arrival turns",
+			SQL: `
+				ALTER TABLE naval_units ADD COLUMN IF NOT EXISTS no_movement INTEGER DEFAULT 0;
+				ALTER TABLE naval_units ADD COLUMN IF NOT EXISTS arrival_turn INTEGER;
+			`,
+			RollbackSQL: `
+				ALTER TABLE naval_units DROP COLUMN IF EXISTS arrival_turn;
+				ALTER TABLE naval_units DROP COLUMN IF EXISTS no_movement;
+			`,
+		},
+		{
+			Version:     "009_game_markers",
+			Description: "Create generic game markers table",
+			SQL: `
+				CREATE TABLE IF NOT EXISTS game_markers (
+					id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+					game_id UUID REFERENCES games(id) ON DELETE CASCADE,
+					type VARCHAR(30) NOT NULL,
+					owner VARCHAR(20) NOT NULL,
+					unit_id UUID,
+					hex VARCHAR(10),
+					value INTEGER DEFAULT 0,
+					visible_to JSONB DEFAULT '[]',
+					expiry VARCHAR(20) NOT NULL,
+					created_turn INTEGER NOT NULL,
+					created_phase VARCHAR(20) NOT NULL,
+					created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+					updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+				);
+
+				CREATE INDEX IF NOT EXISTS idx_game_markers_game_id ON game_markers(game_id);
+				CREATE INDEX IF NOT EXISTS idx_game_markers_unit_id ON game_markers(unit_id);
+			`,
+			RollbackSQL: `
+				DROP TABLE IF EXISTS game_markers;
+			`,
+		},
+		{
+			Version:     "010_movement_orders",
+			Description: "Create table for secret movement orders",
+			SQL: `
+				CREATE TABLE IF NOT EXISTS movement_orders (
+					id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+					game_id UUID REFERENCES games(id) ON DELETE CASCADE,
+					player_id UUID NOT NULL,
+					side VARCHAR(20) NOT NULL,
+					turn INTEGER NOT NULL,
+					orders JSONB DEFAULT '[]',
+					locked BOOLEAN DEFAULT false,
+					locked_at TIMESTAMP WITH TIME ZONE,
+					resolved_at TIMESTAMP WITH TIME ZONE,
+					created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+					updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+					UNIQUE(game_id, turn, player_id)
+				);
+
+				CREATE INDEX IF NOT EXISTS idx_movement_orders_game_turn ON movement_orders(game_id, turn);
+			`,
+			RollbackSQL: `
+				DROP TABLE IF EXISTS movement_orders;
+			`,
+		},
+		{
+			Version:     "011_contact_reports",
+			Description: "Create contact log of enemy units for each side",
+			SQL: `
+				CREATE TABLE IF NOT EXISTS contact_reports (
+					id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+					game_id UUID REFERENCES games(id) ON DELETE CASCADE,
+					side VARCHAR(20) NOT NULL,
+					unit_id UUID,
+					task_force_id UUID,
+					turn INTEGER NOT NULL,
+					phase VARCHAR(20) NOT NULL,
+					hex VARCHAR(10) NOT NULL,
+					class VARCHAR(50),
+					count INTEGER DEFAULT 1,
+					source VARCHAR(20) NOT NULL,
+					detection_level VARCHAR(20) NOT NULL,
+					created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+				);
+
+				CREATE INDEX IF NOT EXISTS idx_contact_reports_game_side ON contact_reports(game_id, side);
+				CREATE INDEX IF NOT EXISTS idx_contact_reports_unit ON contact_reports(unit_id);
+			`,
+			RollbackSQL: `
+				DROP TABLE IF EXISTS contact_reports;
+			`,
+		},
+		{
+			Version:     "012_optional_units",
+			Description: "Create table for purchased optional units",
+			SQL: `
+				CREATE TABLE IF NOT EXISTS optional_unit_purchases (
+					id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+					game_id UUID REFERENCES games(id) ON DELETE CASCADE,
+					side VARCHAR(20) NOT NULL,
+					ship_id VARCHAR(50) NOT NULL,
+					unit_id UUID REFERENCES naval_units(id) ON DELETE SET NULL,
+					vp INTEGER NOT NULL,
+					reveal_roll INTEGER NOT NULL,
+					revealed BOOLEAN DEFAULT false,
+					created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+					UNIQUE(game_id, ship_id)
+				);
+			`,
+			RollbackSQL: `
+				DROP TABLE IF EXISTS optional_unit_purchases;
+			`,
+		},
+		{
+			Version:     "013_combat_participation",
+			Description: "Track naval unit combat participation by day",
+			SQL: `
+				CREATE TABLE IF NOT EXISTS combat_participation (
+					id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+					game_id UUID REFERENCES games(id) ON DELETE CASCADE,
+					unit_id UUID REFERENCES naval_units(id) ON DELETE CASCADE,
+					turn INTEGER NOT NULL,
+					day INTEGER NOT NULL,
+					type VARCHAR(20) NOT NULL,
+					created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+				);
+
+				CREATE INDEX IF NOT EXISTS idx_combat_participation_unit_day ON combat_participation(unit_id, day);
+			`,
+			RollbackSQL: `
+				DROP TABLE IF EXISTS combat_participation;
+			`,
+		},
+		{
+			Version:     "014_unit_special_rules",
+			Description: "Create naval unit special rules table",
+			SQL: `
+				CREATE TABLE IF NOT EXISTS naval_unit_special_rules (
+					unit_id UUID PRIMARY KEY REFERENCES naval_units(id) ON DELETE CASCADE,
+					game_id UUID NOT NULL REFERENCES games(id) ON DELETE CASCADE,
+					rules JSONB NOT NULL DEFAULT '[]',
+					rule_states JSONB NOT NULL DEFAULT '{}',
+					updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+				);
+
+				CREATE INDEX IF NOT EXISTS idx_naval_unit_special_rules_game_id ON naval_unit_special_rules(game_id);
+			`,
+			RollbackSQL: `
+				DROP TABLE IF EXISTS naval_unit_special_rules;
+			`,
+		},
+		{
+			Version:     "015_deployment_orders",
+			Description: "Create table for secret pre-game deployment",
+			SQL: `
+				CREATE TABLE IF NOT EXISTS deployment_orders (
+					id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+					game_id UUID REFERENCES games(id) ON DELETE CASCADE,
+					player_id UUID NOT NULL,
+					side VARCHAR(20) NOT NULL,
+					placements JSONB DEFAULT '[]',
+					locked BOOLEAN DEFAULT false,
+					locked_at TIMESTAMP WITH TIME ZONE,
+					resolved_at TIMESTAMP WITH TIME ZONE,
+					created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+					updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+					UNIQUE(game_id, player_id)
+				);
+			`,
+			RollbackSQL: `
+				DROP TABLE IF EXISTS deployment_orders;
+			`,
+		},
+		{
+			Version:     "016_ship_catalogs",
+			Description: "Create table for ship catalogue versions used by games",
+			SQL: `
+				CREATE TABLE IF NOT EXISTS ship_catalogs (
+					version VARCHAR(64) PRIMARY KEY,
+					data TEXT NOT NULL,
+					created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+				);
+			`,
+			RollbackSQL: `
+				DROP TABLE IF EXISTS ship_catalogs;
+			`,
+		},
+		{
+			Version:     "017_combat_state",
+			Description: "Deduplicate combat participation and persist crew exhaustion",
+			SQL: `
+				DELETE FROM combat_participation a
+					USING combat_participation b
+					WHERE a.game_id = b.game_id AND a.unit_id = b.unit_id
+						AND a.turn = b.turn AND a.type = b.type AND a.id > b.id;
+				CREATE UNIQUE INDEX IF NOT EXISTS idx_combat_participation_unique
+					ON combat_participation(game_id, unit_id, turn, type);
+				ALTER TABLE naval_units ADD COLUMN IF NOT EXISTS crew_exhausted BOOLEAN DEFAULT false;
+			`,
+			RollbackSQL: `
+				ALTER TABLE naval_units DROP COLUMN IF EXISTS crew_exhausted;
+				DROP INDEX IF EXISTS idx_combat_participation_unique;
+			`,
+		},
+		{
+			Version:     "018_phase_readiness",
+			Description: "Track which players have ended the current phase",
+			SQL: `
+				CREATE TABLE IF NOT EXISTS phase_readiness (
+					game_id UUID REFERENCES games(id) ON DELETE CASCADE,
+					turn INTEGER NOT NULL,
+					phase VARCHAR(20) NOT NULL,
+					side VARCHAR(20) NOT NULL,
+					created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+					PRIMARY KEY (game_id, turn, phase, side)
+				);
+			`,
+			RollbackSQL: `
+				DROP TABLE IF EXISTS phase_readiness;
+			`,
+		},
+		{
+			Version:     "019_unit_state_markers",
+			Description: "Remove ship state markers now derived from naval unit fields",
+			SQL: `
+				DELETE FROM game_markers
+				WHERE type IN ('patrol', 'in_port', 'refuel_at_sea', 'repair_at_sea', 'no_movement', 'sighted', 'shadowed');
+			`,
+			RollbackSQL: `
+				-- Маркеры состояния кораблей строятся из полей naval_units, восстанавливать нечего
+				SELECT 1;
+			`,
+		},
 	}
 }
 
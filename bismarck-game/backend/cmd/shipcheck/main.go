@@ -0,0 +1,52 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"bismarck-game/backend/internal/config"
+	"bismarck-game/backend/internal/game/services"
+)
+
+func main() {
+	var (
+		shipsPath  = flag.String("ships", "config/ships.json", "Path to ship catalogue")
+		schemaPath = flag.String("schema", "", "Write JSON Schema of the catalogue to this path")
+	)
+	flag.Parse()
+
+	// Записываем схему каталога
+	if *schemaPath != "" {
+		data, err := json.MarshalIndent(services.ShipCatalogSchema(), "", "  ")
+		if err != nil {
+			log.Fatalf("Failed to build schema: %v", err)
+		}
+		if err := os.WriteFile(*schemaPath, append(data, '\n'), 0644); err != nil {
+			log.Fatalf("Failed to write schema: %v", err)
+		}
+		fmt.Printf("Schema written to %s\n", *schemaPath)
+	}
+
+	// Загружаем и проверяем каталог
+	manager := config.NewShipConfigManager()
+	if err := manager.LoadConfig(*shipsPath); err != nil {
+		log.Fatalf("Failed to load ship catalogue: %v", err)
+	}
+	ships, err := manager.GetAllShips()
+	if err != nil {
+		log.Fatalf("Failed to get ships: %v", err)
+	}
+
+	issues := services.CheckShipCatalog(ships)
+	for _, issue := range issues {
+		fmt.Printf("%s: %s\n", *shipsPath, issue)
+	}
+	if len(issues) > 0 {
+		fmt.Printf("❌ %d issue(s) in %d ships\n", len(issues), len(ships))
+		os.Exit(1)
+	}
+	fmt.Printf("✅ %d ships OK\n", len(ships))
+}
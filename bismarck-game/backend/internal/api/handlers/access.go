@@ -0,0 +1,50 @@
+package handlers
+
+import (
+	"net/http"
+
+	"bismarck-game/backend/internal/api/middleware"
+	"bismarck-game/backend/internal/game/models"
+	"bismarck-game/backend/internal/game/services"
+	"bismarck-game/backend/pkg/utils"
+)
+
+// getGameAccess возвращает доступ пользователя к игре, определенный GameAccessMiddleware,
+// или записывает ошибку в ответ
+func getGameAccess(w http.ResponseWriter, r *http.Request) (*services.GameAccess, bool) {
+	value, _ := middleware.GetGameAccessFromContext(r.Context())
+	access, ok := value.(*services.GameAccess)
+	if !ok {
+		utils.WriteErrorResponse(w, http.StatusForbidden, services.ErrNotGamePlayer.Error())
+		return nil, false
+	}
+	return access, true
+}
+
+// writeAccessError записывает отказ в доступе (403) или, если объект не найден, ответ 404
+func writeAccessError(w http.ResponseWriter, err error, notFound string) {
+	if services.IsAccessError(err) {
+		utils.WriteErrorResponse(w, http.StatusForbidden, err.Error())
+		return
+	}
+	utils.WriteErrorResponse(w, http.StatusNotFound, notFound)
+}
+
+// getPlayerGame возвращает активную игру и ID текущего пользователя, если он в ней играет,
+// или записывает ошибку в ответ
+func getPlayerGame(w http.ResponseWriter, r *http.Request) (*models.Game, string, bool) {
+	access, ok := getGameAccess(w, r)
+	if !ok {
+		return nil, "", false
+	}
+	if err := access.RequirePlayer(); err != nil {
+		writeAccessError(w, err, "Game not found")
+		return nil, "", false
+	}
+	if !access.Game.IsActive() {
+		utils.WriteErrorResponse(w, http.StatusBadRequest, "Game is not active")
+		return nil, "", false
+	}
+
+	return access.Game, access.UserID, true
+}
@@ -0,0 +1,253 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"bismarck-game/backend/internal/api/middleware"
+	"bismarck-game/backend/internal/game/models"
+	"bismarck-game/backend/internal/game/services"
+	"bismarck-game/backend/pkg/logger"
+	"bismarck-game/backend/pkg/utils"
+
+	"github.com/gorilla/mux"
+)
+
+// CombatHandler обрабатывает запросы воздушных атак и боев кораблей
+type CombatHandler struct {
+	combatService *services.CombatService
+	gameAccess    middleware.GameAccessResolver
+	logger        *logger.Logger
+}
+
+// NewCombatHandler создает новый обработчик боя
+func NewCombatHandler(combatService *services.CombatService, gameAccess middleware.GameAccessResolver, logger *logger.Logger) *CombatHandler {
+	return &CombatHandler{
+		combatService: combatService,
+		gameAccess:    gameAccess,
+		logger:        logger,
+	}
+}
+
+// AirAttackRequest представляет запрос на воздушную атаку кораблей противника
+type AirAttackRequest struct {
+	TargetIDs []string `json:"target_ids"`
+}
+
+// NavalCombatRequest представляет запрос на морской бой кораблей одного гекса
+type NavalCombatRequest struct {
+	Zone    string   `json:"zone"`
+	UnitIDs []string `json:"unit_ids"`
+}
+
+// NavalFireRequest представляет запрос на стрельбу корабля в раунде морского боя
+type NavalFireRequest struct {
+	UnitID       string               `json:"unit_id"`
+	MainArmament bool                 `json:"main_armament"`
+	Context      models.CombatContext `json:"context"`
+}
+
+// NavalRoundRequest представляет запрос на завершение раунда морского боя
+type NavalRoundRequest struct {
+	Context models.CombatContext `json:"context"`
+}
+
+// ConvoySunkRequest представляет запрос на запись потопленного конвоя
+type ConvoySunkRequest struct {
+	ConvoyID string `json:"convoy_id"`
+}
+
+// BeginAirAttack начинает воздушную атаку и возвращает модификаторы броска атаки против целей
+func (h *CombatHandler) BeginAirAttack(w http.ResponseWriter, r *http.Request) {
+	game, userID, ok := getPlayerGame(w, r)
+	if !ok {
+		return
+	}
+
+	var req AirAttackRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.WriteErrorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	targets, err := h.combatService.BeginAirAttack(game, game.GetPlayerRole(userID), req.TargetIDs)
+	if err != nil {
+		h.logger.Error("Failed to begin air attack", "game_id", game.ID, "error", err)
+		utils.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	response := map[string]interface{}{
+		"targets": targets,
+	}
+
+	utils.WriteSuccessResponse(w, response)
+}
+
+// BeginNavalCombat начинает морской бой и возвращает уклоняемость и модификаторы стрельбы участников
+func (h *CombatHandler) BeginNavalCombat(w http.ResponseWriter, r *http.Request) {
+	game, userID, ok := getPlayerGame(w, r)
+	if !ok {
+		return
+	}
+
+	var req NavalCombatRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.WriteErrorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	combatants, err := h.combatService.BeginNavalCombat(game, game.GetPlayerRole(userID), req.Zone, req.UnitIDs)
+	if err != nil {
+		h.logger.Error("Failed to begin naval combat", "game_id", game.ID, "error", err)
+		utils.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	response := map[string]interface{}{
+		"combatants": combatants,
+	}
+
+	utils.WriteSuccessResponse(w, response)
+}
+
+// FireNaval готовит стрельбу корабля с учетом специальных правил и возвращает фактор и модификатор броска
+func (h *CombatHandler) FireNaval(w http.ResponseWriter, r *http.Request) {
+	game, userID, ok := getPlayerGame(w, r)
+	if !ok {
+		return
+	}
+
+	var req NavalFireRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.WriteErrorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	roll, err := h.combatService.FireNaval(game, game.GetPlayerRole(userID), req.UnitID, req.Context, req.MainArmament)
+	if err != nil {
+		h.writeNavalRoundError(w, game.ID, err)
+		return
+	}
+
+	response := map[string]interface{}{
+		"fire": roll,
+	}
+
+	utils.WriteSuccessResponse(w, response)
+}
+
+// EndNavalRound завершает раунд морского боя и возвращает своих участников боя
+func (h *CombatHandler) EndNavalRound(w http.ResponseWriter, r *http.Request) {
+	game, userID, ok := getPlayerGame(w, r)
+	if !ok {
+		return
+	}
+
+	var req NavalRoundRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.WriteErrorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	combatants, err := h.combatService.EndNavalRound(game, game.GetPlayerRole(userID), req.Context)
+	if err != nil {
+		h.writeNavalRoundError(w, game.ID, err)
+		return
+	}
+
+	response := map[string]interface{}{
+		"combatants": combatants,
+	}
+
+	utils.WriteSuccessResponse(w, response)
+}
+
+// writeNavalRoundError записывает ошибку стрельбы или завершения раунда морского боя
+func (h *CombatHandler) writeNavalRoundError(w http.ResponseWriter, gameID string, err error) {
+	switch {
+	case errors.Is(err, services.ErrNotNavalCombatPhase),
+		errors.Is(err, services.ErrNotNavalCombatant),
+		errors.Is(err, services.ErrNoNavalCombatUnits):
+		utils.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+	case errors.Is(err, services.ErrShipAlreadyFired):
+		utils.WriteErrorResponse(w, http.StatusConflict, err.Error())
+	default:
+		h.logger.Error("Failed to resolve naval combat round", "game_id", gameID, "error", err)
+		utils.WriteInternalError(w, "Failed to resolve naval combat round")
+	}
+}
+
+// SinkConvoy записывает потопленный конвой и возвращает немецкому игроку вытянутый маркер VP
+func (h *CombatHandler) SinkConvoy(w http.ResponseWriter, r *http.Request) {
+	game, userID, ok := getPlayerGame(w, r)
+	if !ok {
+		return
+	}
+
+	var req ConvoySunkRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.WriteErrorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if req.ConvoyID == "" {
+		utils.WriteErrorResponse(w, http.StatusBadRequest, "convoy_id is required")
+		return
+	}
+
+	marker, err := h.combatService.SinkConvoy(game, game.GetPlayerRole(userID), req.ConvoyID)
+	if err != nil {
+		h.writeConvoyError(w, game.ID, err)
+		return
+	}
+
+	response := map[string]interface{}{
+		"marker": marker,
+	}
+
+	utils.WriteSuccessResponse(w, response)
+}
+
+// SinkMerchant записывает потопленное одиночное торговое судно
+func (h *CombatHandler) SinkMerchant(w http.ResponseWriter, r *http.Request) {
+	game, userID, ok := getPlayerGame(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.combatService.SinkMerchant(game, game.GetPlayerRole(userID)); err != nil {
+		h.writeConvoyError(w, game.ID, err)
+		return
+	}
+
+	utils.WriteSuccessResponse(w, map[string]interface{}{})
+}
+
+// writeConvoyError записывает ошибку записи потопленного конвоя или торгового судна
+func (h *CombatHandler) writeConvoyError(w http.ResponseWriter, gameID string, err error) {
+	switch {
+	case errors.Is(err, services.ErrNotConvoyRaider):
+		utils.WriteErrorResponse(w, http.StatusForbidden, err.Error())
+	case errors.Is(err, services.ErrNotNavalCombatPhase):
+		utils.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+	case errors.Is(err, services.ErrConvoyPoolExhausted):
+		utils.WriteErrorResponse(w, http.StatusConflict, err.Error())
+	default:
+		h.logger.Error("Failed to record convoy attack", "game_id", gameID, "error", err)
+		utils.WriteInternalError(w, "Failed to record convoy attack")
+	}
+}
+
+// RegisterRoutes регистрирует маршруты боя
+func (h *CombatHandler) RegisterRoutes(router *mux.Router, jwtSecret string) {
+	combatRouter := router.PathPrefix("/api/games/{gameId}").Subrouter()
+	combatRouter.Use(middleware.AuthMiddleware(jwtSecret), middleware.GameAccessMiddleware(h.gameAccess))
+
+	combatRouter.HandleFunc("/combat/air-attack", h.BeginAirAttack).Methods("POST")
+	combatRouter.HandleFunc("/combat/naval", h.BeginNavalCombat).Methods("POST")
+	combatRouter.HandleFunc("/combat/naval/fire", h.FireNaval).Methods("POST")
+	combatRouter.HandleFunc("/combat/naval/end-round", h.EndNavalRound).Methods("POST")
+	combatRouter.HandleFunc("/combat/convoy", h.SinkConvoy).Methods("POST")
+	combatRouter.HandleFunc("/combat/merchant", h.SinkMerchant).Methods("POST")
+}
@@ -0,0 +1,155 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"bismarck-game/backend/internal/api/middleware"
+	"bismarck-game/backend/internal/game/models"
+	"bismarck-game/backend/internal/game/services"
+	"bismarck-game/backend/pkg/logger"
+	"bismarck-game/backend/pkg/utils"
+
+	"github.com/gorilla/mux"
+)
+
+// DeploymentHandler обрабатывает запросы секретной расстановки в фазе развертывания
+type DeploymentHandler struct {
+	deploymentService *services.DeploymentService
+	gameAccess        middleware.GameAccessResolver
+	logger            *logger.Logger
+}
+
+// NewDeploymentHandler создает новый обработчик фазы развертывания
+func NewDeploymentHandler(deploymentService *services.DeploymentService, gameAccess middleware.GameAccessResolver, logger *logger.Logger) *DeploymentHandler {
+	return &DeploymentHandler{
+		deploymentService: deploymentService,
+		gameAccess:        gameAccess,
+		logger:            logger,
+	}
+}
+
+// SubmitDeploymentRequest представляет запрос на сохранение расстановки
+type SubmitDeploymentRequest struct {
+	Placements []models.DeploymentPlacement `json:"placements"`
+}
+
+// GetDeployment возвращает развертываемые юниты игрока, их зоны и собственную расстановку
+func (h *DeploymentHandler) GetDeployment(w http.ResponseWriter, r *http.Request) {
+	game, userID, ok := getPlayerGame(w, r)
+	if !ok {
+		return
+	}
+
+	options, err := h.deploymentService.GetDeploymentOptions(game, userID)
+	if err != nil {
+		h.logger.Error("Failed to get deployment options", "game_id", game.ID, "error", err)
+		utils.WriteInternalError(w, "Failed to get deployment")
+		return
+	}
+	batch, err := h.deploymentService.GetDeployment(game, userID)
+	if err != nil {
+		h.logger.Error("Failed to get deployment", "game_id", game.ID, "error", err)
+		utils.WriteInternalError(w, "Failed to get deployment")
+		return
+	}
+
+	response := map[string]interface{}{
+		"units":      options,
+		"deployment": batch,
+	}
+
+	utils.WriteSuccessResponse(w, response)
+}
+
+// SubmitDeployment сохраняет расстановку игрока, пока она не зафиксирована
+func (h *DeploymentHandler) SubmitDeployment(w http.ResponseWriter, r *http.Request) {
+	game, userID, ok := getPlayerGame(w, r)
+	if !ok {
+		return
+	}
+
+	var req SubmitDeploymentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.WriteErrorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	batch, err := h.deploymentService.SubmitDeployment(game, userID, req.Placements)
+	if err != nil {
+		h.logger.Error("Failed to submit deployment", "game_id", game.ID, "error", err)
+		utils.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	response := map[string]interface{}{
+		"deployment": batch,
+		"message":    "Deployment saved",
+	}
+
+	utils.WriteSuccessResponse(w, response)
+}
+
+// LockDeployment фиксирует расстановку игрока
+func (h *DeploymentHandler) LockDeployment(w http.ResponseWriter, r *http.Request) {
+	game, userID, ok := getPlayerGame(w, r)
+	if !ok {
+		return
+	}
+
+	batch, err := h.deploymentService.LockDeployment(game, userID)
+	if err != nil {
+		h.logger.Error("Failed to lock deployment", "game_id", game.ID, "error", err)
+		utils.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	response := map[string]interface{}{
+		"deployment": batch,
+		"message":    "Deployment locked",
+	}
+
+	utils.WriteSuccessResponse(w, response)
+}
+
+// GetDeploymentStatus возвращает, какие игроки зафиксировали расстановку, без ее содержания.
+// Если истекло время хода, расстановка применяется
+func (h *DeploymentHandler) GetDeploymentStatus(w http.ResponseWriter, r *http.Request) {
+	game, _, ok := getPlayerGame(w, r)
+	if !ok {
+		return
+	}
+
+	resolved := false
+	if game.IsDeployment() {
+		var err error
+		if resolved, err = h.deploymentService.ResolveIfReady(game); err != nil {
+			h.logger.Error("Failed to resolve deployment", "game_id", game.ID, "error", err)
+		}
+	}
+
+	statuses, err := h.deploymentService.GetDeploymentStatus(game)
+	if err != nil {
+		h.logger.Error("Failed to get deployment status", "game_id", game.ID, "error", err)
+		utils.WriteInternalError(w, "Failed to get deployment status")
+		return
+	}
+
+	response := map[string]interface{}{
+		"players":  statuses,
+		"resolved": resolved,
+	}
+
+	utils.WriteSuccessResponse(w, response)
+}
+
+// RegisterRoutes регистрирует маршруты фазы развертывания
+func (h *DeploymentHandler) RegisterRoutes(router *mux.Router, jwtSecret string) {
+	deploymentRouter := router.PathPrefix("/api/games/{gameId}").Subrouter()
+	deploymentRouter.Use(middleware.AuthMiddleware(jwtSecret), middleware.GameAccessMiddleware(h.gameAccess))
+
+	deploymentRouter.HandleFunc("/deployment", h.GetDeployment).Methods("GET")
+	deploymentRouter.HandleFunc("/deployment", h.SubmitDeployment).Methods("POST")
+	deploymentRouter.HandleFunc("/deployment/lock", h.LockDeployment).Methods("POST")
+	deploymentRouter.HandleFunc("/deployment/status", h.GetDeploymentStatus).Methods("GET")
+}
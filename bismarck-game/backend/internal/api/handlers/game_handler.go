@@ -3,6 +3,7 @@ package handlers
 import (
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
@@ -11,6 +12,7 @@ import (
 
 	"bismarck-game/backend/internal/api/middleware"
 	"bismarck-game/backend/internal/game/models"
+	"bismarck-game/backend/internal/game/services"
 	"bismarck-game/backend/pkg/database"
 	"bismarck-game/backend/pkg/utils"
 
@@ -19,13 +21,15 @@ import (
 
 // GameHandler представляет обработчик игр
 type GameHandler struct {
-	db *database.Database
+	db           *database.Database
+	setupService *services.GameSetupService
 }
 
 // NewGameHandler создает новый обработчик игр
-func NewGameHandler(db *database.Database) *GameHandler {
+func NewGameHandler(db *database.Database, setupService *services.GameSetupService) *GameHandler {
 	return &GameHandler{
-		db: db,
+		db:           db,
+		setupService: setupService,
 	}
 }
 
@@ -124,6 +128,14 @@ func (h *GameHandler) CreateGame(w http.ResponseWriter, r *http.Request) {
 		game.Settings = models.GetDefaultGameSettings()
 	}
 
+	// Сценарий должен существовать: его расстановка создается при начале игры
+	if _, err := h.setupService.GetScenario(game.Settings.Scenario); err != nil {
+		utils.WriteValidationError(w, "Unknown scenario", map[string]string{
+			"scenario": "Scenario is not available",
+		})
+		return
+	}
+
 	// Если указан пароль, устанавливаем приватность
 	if req.Password != "" {
 		game.Settings.PrivateLobby = true
@@ -378,7 +390,7 @@ func (h *GameHandler) JoinGame(w http.ResponseWriter, r *http.Request) {
 	// Получаем игру
 	var game models.Game
 	var settingsJSON []byte
-	var player2ID sql.NullString
+	var player1ID, player2ID sql.NullString
 	var completedAt sql.NullTime
 	var player1Username sql.NullString
 	query := `
@@ -391,7 +403,7 @@ func (h *GameHandler) JoinGame(w http.ResponseWriter, r *http.Request) {
 	`
 
 	err = h.db.GetConnection().QueryRowContext(r.Context(), query, gameID).Scan(
-		&game.ID, &game.Name, &game.Player1ID, &player2ID,
+		&game.ID, &game.Name, &player1ID, &player2ID,
 		&game.CurrentTurn, &game.CurrentPhase, &game.Status,
 		&settingsJSON, &game.CreatedAt, &game.UpdatedAt,
 		&completedAt, &player1Username,
@@ -407,6 +419,7 @@ func (h *GameHandler) JoinGame(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Обрабатываем nullable поля
+	game.Player1ID = player1ID.String
 	if player2ID.Valid {
 		game.Player2ID = player2ID.String
 	}
@@ -452,29 +465,15 @@ func (h *GameHandler) JoinGame(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	// Определяем, к какой стороне присоединяется игрок
-	var updateQuery string
-	var updateArgs []interface{}
-
-	if game.Player1ID == "" {
-		// Свободна немецкая сторона (Player1)
-		updateQuery = `UPDATE games SET player1_id = $1, status = 'active', started_at = $2, updated_at = $2 WHERE id = $3`
-		updateArgs = []interface{}{userID, time.Now(), gameID}
-	} else if game.Player2ID == "" {
-		// Свободна союзническая сторона (Player2)
-		updateQuery = `UPDATE games SET player2_id = $1, status = 'active', started_at = $2, updated_at = $2 WHERE id = $3`
-		updateArgs = []interface{}{userID, time.Now(), gameID}
-	} else {
-		utils.WriteValidationError(w, "Game is full", map[string]string{
-			"game": "Game already has two players",
-		})
-		return
-	}
-
-	// Присоединяем игрока
-	_, err = h.db.GetConnection().ExecContext(r.Context(), updateQuery, updateArgs...)
-
-	if err != nil {
+	// Присоединяем игрока и начинаем игру: расстановка сценария, Трек хода и первая фаза
+	if err := h.setupService.JoinGame(&game, userID); err != nil {
+		if errors.Is(err, services.ErrGameNotJoinable) {
+			utils.WriteValidationError(w, "Cannot join this game", map[string]string{
+				"game": "Game is not available for joining",
+			})
+			return
+		}
+		log.Printf("JoinGame: Failed to start game %s: %v", gameID, err)
 		utils.WriteInternalError(w, "Failed to join game")
 		return
 	}
@@ -487,24 +486,12 @@ func (h *GameHandler) JoinGame(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Обновляем игровое состояние
-	if game.Player1ID == "" {
-		game.Player1ID = userID // Присоединился как немец
-	} else if game.Player2ID == "" {
-		game.Player2ID = userID // Присоединился как союзник
-	}
-
-	game.Status = models.GameStatusActive
-	now := time.Now()
-	game.StartedAt = &now
-	game.UpdatedAt = now
-
 	// Формируем username для ответа
 	var player2UsernameStr string
 	if game.Player2ID == userID {
 		player2UsernameStr = currentPlayerUsername
 	} else {
-		player2UsernameStr = ""
+		player1UsernameStr = currentPlayerUsername
 	}
 
 	utils.WriteSuccess(w, game.ToResponseWithUsernames(player1UsernameStr, player2UsernameStr))
@@ -0,0 +1,58 @@
+package handlers
+
+import (
+	"net/http"
+
+	"bismarck-game/backend/internal/api/middleware"
+	"bismarck-game/backend/internal/game/services"
+	"bismarck-game/backend/pkg/logger"
+	"bismarck-game/backend/pkg/utils"
+
+	"github.com/gorilla/mux"
+)
+
+// IntelligenceHandler обрабатывает запросы сведений о противнике
+type IntelligenceHandler struct {
+	intelligenceService *services.IntelligenceService
+	gameAccess          middleware.GameAccessResolver
+	logger              *logger.Logger
+}
+
+// NewIntelligenceHandler создает новый обработчик разведки
+func NewIntelligenceHandler(intelligenceService *services.IntelligenceService, gameAccess middleware.GameAccessResolver, logger *logger.Logger) *IntelligenceHandler {
+	return &IntelligenceHandler{
+		intelligenceService: intelligenceService,
+		gameAccess:          gameAccess,
+		logger:              logger,
+	}
+}
+
+// GetIntelligence возвращает журнал контактов игрока с кораблями противника для его карты
+func (h *IntelligenceHandler) GetIntelligence(w http.ResponseWriter, r *http.Request) {
+	game, userID, ok := getPlayerGame(w, r)
+	if !ok {
+		return
+	}
+
+	contacts, err := h.intelligenceService.GetIntelligence(game, userID)
+	if err != nil {
+		h.logger.Error("Failed to get intelligence", "game_id", game.ID, "error", err)
+		utils.WriteInternalError(w, "Failed to get intelligence")
+		return
+	}
+
+	response := map[string]interface{}{
+		"turn":     game.CurrentTurn,
+		"contacts": contacts,
+	}
+
+	utils.WriteSuccessResponse(w, response)
+}
+
+// RegisterRoutes регистрирует маршруты сведений о противнике
+func (h *IntelligenceHandler) RegisterRoutes(router *mux.Router, jwtSecret string) {
+	intelligenceRouter := router.PathPrefix("/api/games/{gameId}").Subrouter()
+	intelligenceRouter.Use(middleware.AuthMiddleware(jwtSecret), middleware.GameAccessMiddleware(h.gameAccess))
+
+	intelligenceRouter.HandleFunc("/intelligence", h.GetIntelligence).Methods("GET")
+}
@@ -0,0 +1,100 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"bismarck-game/backend/internal/api/middleware"
+	"bismarck-game/backend/internal/game/services"
+	"bismarck-game/backend/pkg/logger"
+	"bismarck-game/backend/pkg/utils"
+
+	"github.com/gorilla/mux"
+)
+
+// OptionalUnitHandler обрабатывает запросы покупки гипотетических юнитов
+type OptionalUnitHandler struct {
+	optionalUnitService *services.OptionalUnitService
+	gameAccess          middleware.GameAccessResolver
+	logger              *logger.Logger
+}
+
+// NewOptionalUnitHandler создает новый обработчик гипотетических юнитов
+func NewOptionalUnitHandler(optionalUnitService *services.OptionalUnitService, gameAccess middleware.GameAccessResolver, logger *logger.Logger) *OptionalUnitHandler {
+	return &OptionalUnitHandler{
+		optionalUnitService: optionalUnitService,
+		gameAccess:          gameAccess,
+		logger:              logger,
+	}
+}
+
+// PurchaseOptionalUnitsRequest представляет запрос на покупку гипотетических юнитов
+type PurchaseOptionalUnitsRequest struct {
+	ShipIDs []string `json:"ship_ids"`
+}
+
+// GetOptionalUnits возвращает гипотетические юниты стороны игрока и его покупки
+func (h *OptionalUnitHandler) GetOptionalUnits(w http.ResponseWriter, r *http.Request) {
+	game, userID, ok := getPlayerGame(w, r)
+	if !ok {
+		return
+	}
+
+	options, err := h.optionalUnitService.GetOptions(game, userID)
+	if err != nil {
+		h.logger.Error("Failed to get optional units", "game_id", game.ID, "error", err)
+		utils.WriteInternalError(w, "Failed to get optional units")
+		return
+	}
+	purchases, err := h.optionalUnitService.GetPurchases(game, userID)
+	if err != nil {
+		h.logger.Error("Failed to get optional unit purchases", "game_id", game.ID, "error", err)
+		utils.WriteInternalError(w, "Failed to get optional unit purchases")
+		return
+	}
+
+	response := map[string]interface{}{
+		"enabled":   game.Settings.UseOptionalUnits,
+		"options":   options,
+		"purchases": purchases,
+	}
+
+	utils.WriteSuccessResponse(w, response)
+}
+
+// PurchaseOptionalUnits покупает гипотетические юниты до начала игры
+func (h *OptionalUnitHandler) PurchaseOptionalUnits(w http.ResponseWriter, r *http.Request) {
+	game, userID, ok := getPlayerGame(w, r)
+	if !ok {
+		return
+	}
+
+	var req PurchaseOptionalUnitsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.WriteErrorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	purchases, err := h.optionalUnitService.PurchaseOptionalUnits(game, userID, req.ShipIDs)
+	if err != nil {
+		h.logger.Error("Failed to purchase optional units", "game_id", game.ID, "error", err)
+		utils.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	response := map[string]interface{}{
+		"purchases": purchases,
+		"message":   "Optional units purchased",
+	}
+
+	utils.WriteSuccessResponse(w, response)
+}
+
+// RegisterRoutes регистрирует маршруты гипотетических юнитов
+func (h *OptionalUnitHandler) RegisterRoutes(router *mux.Router, jwtSecret string) {
+	optionalRouter := router.PathPrefix("/api/games/{gameId}").Subrouter()
+	optionalRouter.Use(middleware.AuthMiddleware(jwtSecret), middleware.GameAccessMiddleware(h.gameAccess))
+
+	optionalRouter.HandleFunc("/optional-units", h.GetOptionalUnits).Methods("GET")
+	optionalRouter.HandleFunc("/optional-units", h.PurchaseOptionalUnits).Methods("POST")
+}
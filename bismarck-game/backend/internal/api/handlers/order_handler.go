@@ -0,0 +1,145 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"bismarck-game/backend/internal/api/middleware"
+	"bismarck-game/backend/internal/game/models"
+	"bismarck-game/backend/internal/game/services"
+	"bismarck-game/backend/pkg/logger"
+	"bismarck-game/backend/pkg/utils"
+
+	"github.com/gorilla/mux"
+)
+
+// OrderHandler обрабатывает запросы секретных приказов на движение
+type OrderHandler struct {
+	orderService *services.OrderService
+	gameAccess   middleware.GameAccessResolver
+	logger       *logger.Logger
+}
+
+// NewOrderHandler создает новый обработчик приказов
+func NewOrderHandler(orderService *services.OrderService, gameAccess middleware.GameAccessResolver, logger *logger.Logger) *OrderHandler {
+	return &OrderHandler{
+		orderService: orderService,
+		gameAccess:   gameAccess,
+		logger:       logger,
+	}
+}
+
+// SubmitOrdersRequest представляет запрос на сохранение приказов хода
+type SubmitOrdersRequest struct {
+	Orders []models.MovementOrder `json:"orders"`
+}
+
+// GetOrders возвращает собственные приказы игрока на текущий ход
+func (h *OrderHandler) GetOrders(w http.ResponseWriter, r *http.Request) {
+	game, userID, ok := getPlayerGame(w, r)
+	if !ok {
+		return
+	}
+
+	batch, err := h.orderService.GetOrders(game, userID)
+	if err != nil {
+		h.logger.Error("Failed to get orders", "game_id", game.ID, "error", err)
+		utils.WriteInternalError(w, "Failed to get orders")
+		return
+	}
+
+	utils.WriteSuccessResponse(w, batch)
+}
+
+// SubmitOrders сохраняет приказы игрока, пока они не зафиксированы
+func (h *OrderHandler) SubmitOrders(w http.ResponseWriter, r *http.Request) {
+	game, userID, ok := getPlayerGame(w, r)
+	if !ok {
+		return
+	}
+
+	var req SubmitOrdersRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.WriteErrorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	batch, err := h.orderService.SubmitOrders(game, userID, req.Orders)
+	if err != nil {
+		h.logger.Error("Failed to submit orders", "game_id", game.ID, "error", err)
+		utils.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	response := map[string]interface{}{
+		"orders":  batch,
+		"message": "Orders saved",
+	}
+
+	utils.WriteSuccessResponse(w, response)
+}
+
+// LockOrders фиксирует приказы игрока на ход
+func (h *OrderHandler) LockOrders(w http.ResponseWriter, r *http.Request) {
+	game, userID, ok := getPlayerGame(w, r)
+	if !ok {
+		return
+	}
+
+	batch, err := h.orderService.LockOrders(game, userID)
+	if err != nil {
+		h.logger.Error("Failed to lock orders", "game_id", game.ID, "error", err)
+		utils.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	response := map[string]interface{}{
+		"orders":  batch,
+		"message": "Orders locked",
+	}
+
+	utils.WriteSuccessResponse(w, response)
+}
+
+// GetOrderStatus возвращает, какие игроки зафиксировали приказы, без их содержания.
+// Если истекло время хода, приказы выполняются
+func (h *OrderHandler) GetOrderStatus(w http.ResponseWriter, r *http.Request) {
+	game, _, ok := getPlayerGame(w, r)
+	if !ok {
+		return
+	}
+
+	resolved := false
+	if game.CurrentPhase == models.PhaseMovement {
+		var err error
+		if resolved, err = h.orderService.ResolveIfReady(game); err != nil {
+			h.logger.Error("Failed to resolve orders", "game_id", game.ID, "error", err)
+		}
+	}
+
+	statuses, err := h.orderService.GetOrderStatus(game)
+	if err != nil {
+		h.logger.Error("Failed to get order status", "game_id", game.ID, "error", err)
+		utils.WriteInternalError(w, "Failed to get order status")
+		return
+	}
+
+	response := map[string]interface{}{
+		"turn":     game.CurrentTurn,
+		"players":  statuses,
+		"resolved": resolved,
+	}
+
+	utils.WriteSuccessResponse(w, response)
+}
+
+// RegisterRoutes регистрирует маршруты приказов на движение
+func (h *OrderHandler) RegisterRoutes(router *mux.Router, jwtSecret string) {
+	orderRouter := router.PathPrefix("/api/games/{gameId}").Subrouter()
+	orderRouter.Use(middleware.AuthMiddleware(jwtSecret), middleware.GameAccessMiddleware(h.gameAccess))
+
+	orderRouter.HandleFunc("/orders", h.GetOrders).Methods("GET")
+	orderRouter.HandleFunc("/orders", h.SubmitOrders).Methods("POST")
+	orderRouter.HandleFunc("/orders/lock", h.LockOrders).Methods("POST")
+	orderRouter.HandleFunc("/orders/status", h.GetOrderStatus).Methods("GET")
+}
@@ -0,0 +1,75 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"bismarck-game/backend/internal/api/middleware"
+	"bismarck-game/backend/internal/game/services"
+	"bismarck-game/backend/pkg/logger"
+	"bismarck-game/backend/pkg/utils"
+
+	"github.com/gorilla/mux"
+)
+
+// PhaseHandler обрабатывает запросы смены фаз хода
+type PhaseHandler struct {
+	phaseService *services.PhaseService
+	gameAccess   middleware.GameAccessResolver
+	logger       *logger.Logger
+}
+
+// NewPhaseHandler создает новый обработчик фаз
+func NewPhaseHandler(phaseService *services.PhaseService, gameAccess middleware.GameAccessResolver, logger *logger.Logger) *PhaseHandler {
+	return &PhaseHandler{
+		phaseService: phaseService,
+		gameAccess:   gameAccess,
+		logger:       logger,
+	}
+}
+
+// AdvancePhase завершает текущую фазу за игрока и переводит игру в следующую,
+// когда фазу закончили оба игрока.
+// При выходе из Фазы администрирования игрок получает ее итог для своей стороны;
+// после Фазы администрирования последнего хода игра завершается
+func (h *PhaseHandler) AdvancePhase(w http.ResponseWriter, r *http.Request) {
+	game, userID, ok := getPlayerGame(w, r)
+	if !ok {
+		return
+	}
+
+	updated, summary, err := h.phaseService.AdvancePhase(game.ID, game.GetPlayerRole(userID))
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrOrdersNotReady),
+			errors.Is(err, services.ErrDeploymentNotReady),
+			errors.Is(err, services.ErrShadowedOrdersPending),
+			errors.Is(err, services.ErrPhaseNotReady),
+			errors.Is(err, services.ErrPhaseChanged):
+			utils.WriteErrorResponse(w, http.StatusConflict, err.Error())
+		default:
+			h.logger.Error("Failed to advance phase", "game_id", game.ID, "error", err)
+			utils.WriteInternalError(w, "Failed to advance phase")
+		}
+		return
+	}
+
+	response := map[string]interface{}{
+		"turn":   updated.CurrentTurn,
+		"phase":  updated.CurrentPhase,
+		"status": updated.Status,
+	}
+	if summary != nil {
+		response["admin_summary"] = summary.ForSide(game.GetPlayerRole(userID))
+	}
+
+	utils.WriteSuccessResponse(w, response)
+}
+
+// RegisterRoutes регистрирует маршруты фаз хода
+func (h *PhaseHandler) RegisterRoutes(router *mux.Router, jwtSecret string) {
+	phaseRouter := router.PathPrefix("/api/games/{gameId}").Subrouter()
+	phaseRouter.Use(middleware.AuthMiddleware(jwtSecret), middleware.GameAccessMiddleware(h.gameAccess))
+
+	phaseRouter.HandleFunc("/phase/advance", h.AdvancePhase).Methods("POST")
+}
@@ -1,9 +1,12 @@
 package handlers
 
 import (
+	"bismarck-game/backend/internal/api/middleware"
+	"bismarck-game/backend/internal/config"
 	"bismarck-game/backend/internal/game/services"
 	"bismarck-game/backend/pkg/utils"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"strconv"
 
@@ -125,9 +128,17 @@ func (sch *ShipConfigHandler) GetShipConfig(w http.ResponseWriter, r *http.Reque
 		return
 	}
 
-	// Получаем конфигурацию через сервис
-	// Для этого нужно добавить метод в сервис
-	utils.WriteErrorResponse(w, http.StatusNotImplemented, "метод не реализован")
+	ship, err := sch.shipConfigService.GetShipConfig(shipID)
+	if err != nil {
+		if errors.Is(err, config.ErrShipNotFound) {
+			utils.WriteErrorResponse(w, http.StatusNotFound, "корабль не найден")
+			return
+		}
+		utils.WriteErrorResponse(w, http.StatusInternalServerError, "ошибка получения корабля")
+		return
+	}
+
+	utils.WriteSuccessResponse(w, ship)
 }
 
 // SearchShips выполняет поиск кораблей по критериям
@@ -219,3 +230,17 @@ func (sch *ShipConfigHandler) SearchShips(w http.ResponseWriter, r *http.Request
 
 	utils.WriteSuccessResponse(w, filteredShips)
 }
+
+// RegisterRoutes регистрирует маршруты каталога кораблей. Создание юнитов из каталога
+// доступно только при начале игры, поэтому CreateUnitFromConfig не публикуется
+func (sch *ShipConfigHandler) RegisterRoutes(router *mux.Router, jwtSecret string) {
+	shipRouter := router.PathPrefix("/api/ships").Subrouter()
+	shipRouter.Use(middleware.AuthMiddleware(jwtSecret))
+
+	shipRouter.HandleFunc("", sch.SearchShips).Methods("GET")
+	shipRouter.HandleFunc("/types", sch.GetShipTypes).Methods("GET")
+	shipRouter.HandleFunc("/stats", sch.GetConfigStats).Methods("GET")
+	shipRouter.HandleFunc("/side/{side}", sch.GetAvailableShips).Methods("GET")
+	shipRouter.HandleFunc("/type/{type}", sch.GetShipsByType).Methods("GET")
+	shipRouter.HandleFunc("/{id}", sch.GetShipConfig).Methods("GET")
+}
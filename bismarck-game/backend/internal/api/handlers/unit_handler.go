@@ -2,8 +2,11 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
+	"strconv"
 
+	"bismarck-game/backend/internal/api/middleware"
 	"bismarck-game/backend/internal/game/models"
 	"bismarck-game/backend/internal/game/services"
 	"bismarck-game/backend/pkg/logger"
@@ -16,18 +19,112 @@ import (
 type UnitHandler struct {
 	unitService      *services.UnitService
 	taskForceService *services.TaskForceService
+	gameService      *services.GameService
+	gameAccess       middleware.GameAccessResolver
+	movementService  *services.MovementPhaseService
+	viewService      *services.ViewService
 	logger           *logger.Logger
 }
 
 // NewUnitHandler создает новый обработчик юнитов
-func NewUnitHandler(unitService *services.UnitService, taskForceService *services.TaskForceService, logger *logger.Logger) *UnitHandler {
+func NewUnitHandler(unitService *services.UnitService, taskForceService *services.TaskForceService, gameService *services.GameService, gameAccess middleware.GameAccessResolver, movementService *services.MovementPhaseService, viewService *services.ViewService, logger *logger.Logger) *UnitHandler {
 	return &UnitHandler{
 		unitService:      unitService,
 		taskForceService: taskForceService,
+		gameService:      gameService,
+		gameAccess:       gameAccess,
+		movementService:  movementService,
+		viewService:      viewService,
 		logger:           logger,
 	}
 }
 
+// getPlayerView возвращает состояние игры, видимое игроку или зрителю, или записывает ошибку в ответ
+func (h *UnitHandler) getPlayerView(w http.ResponseWriter, r *http.Request) (*models.PlayerView, bool) {
+	access, ok := getGameAccess(w, r)
+	if !ok {
+		return nil, false
+	}
+
+	view, err := h.viewService.GetView(access)
+	if err != nil {
+		h.logger.Error("Failed to get player view", "game_id", access.Game.ID, "error", err)
+		utils.WriteInternalError(w, "Failed to get game state")
+		return nil, false
+	}
+
+	return view, true
+}
+
+// checkGameEnd проверяет условия немедленного окончания игры после изменения состояния
+func (h *UnitHandler) checkGameEnd(gameID string) {
+	if h.gameService == nil {
+		return
+	}
+	if _, err := h.gameService.CheckGameEnd(gameID); err != nil {
+		h.logger.Error("Failed to check game end", "game_id", gameID, "error", err)
+	}
+}
+
+// checkMoveOrder проверяет очередность движения в Фазе движения и записывает ошибку в ответ
+func (h *UnitHandler) checkMoveOrder(w http.ResponseWriter, game *models.Game, units []models.NavalUnit) bool {
+	if h.movementService == nil || game == nil {
+		return true
+	}
+	if err := h.movementService.CheckMoveOrder(game, units); err != nil {
+		utils.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+		return false
+	}
+	return true
+}
+
+// revealShadowedMoves раскрывает позиции преследуемых юнитов, если все они отдали приказы
+func (h *UnitHandler) revealShadowedMoves(game *models.Game) {
+	if h.movementService == nil || game == nil || game.CurrentPhase != models.PhaseMovement {
+		return
+	}
+	if _, err := h.movementService.RevealShadowedMoves(game); err != nil {
+		h.logger.Error("Failed to reveal shadowed units", "game_id", game.ID, "error", err)
+	}
+}
+
+// getPlayerUnit возвращает корабль игры, принадлежащий стороне игрока, или записывает ошибку в ответ
+func (h *UnitHandler) getPlayerUnit(w http.ResponseWriter, r *http.Request, unitID string) (*models.NavalUnit, bool) {
+	access, ok := getGameAccess(w, r)
+	if !ok {
+		return nil, false
+	}
+	unit, err := h.unitService.GetOwnNavalUnit(access, unitID)
+	if err != nil {
+		writeAccessError(w, err, "Unit not found")
+		return nil, false
+	}
+	return unit, true
+}
+
+// getPlayerTaskForce возвращает соединение игры, принадлежащее стороне игрока, или записывает ошибку в ответ
+func (h *UnitHandler) getPlayerTaskForce(w http.ResponseWriter, r *http.Request, taskForceID string) (*models.TaskForce, bool) {
+	access, ok := getGameAccess(w, r)
+	if !ok {
+		return nil, false
+	}
+	taskForce, err := h.taskForceService.GetOwnTaskForce(access, taskForceID)
+	if err != nil {
+		writeAccessError(w, err, "Task force not found")
+		return nil, false
+	}
+	return taskForce, true
+}
+
+// checkPhase проверяет, что идет нужная фаза, или записывает ошибку в ответ
+func checkPhase(w http.ResponseWriter, game *models.Game, phase models.GamePhase, phaseErr error) bool {
+	if game.CurrentPhase != phase {
+		utils.WriteErrorResponse(w, http.StatusBadRequest, phaseErr.Error())
+		return false
+	}
+	return true
+}
+
 // MoveUnitRequest представляет запрос на движение юнита
 type MoveUnitRequest struct {
 	UnitID string   `json:"unit_id" validate:"required"`
@@ -62,43 +159,44 @@ type RemoveUnitFromTaskForceRequest struct {
 	UnitID      string `json:"unit_id" validate:"required"`
 }
 
+// SplitTaskForceRequest представляет запрос на разделение Task Force
+type SplitTaskForceRequest struct {
+	UnitIDs []string `json:"unit_ids" validate:"required,min=1"`
+	Name    string   `json:"name,omitempty"`
+}
+
 // GetUnits возвращает все юниты игры
 func (h *UnitHandler) GetUnits(w http.ResponseWriter, r *http.Request) {
-	vars := mux.Vars(r)
-	gameID := vars["gameId"]
-
-	// Получаем морские юниты
-	navalUnits, err := h.unitService.GetNavalUnitsByGameID(gameID)
-	if err != nil {
-		h.logger.Error("Failed to get naval units", "game_id", gameID, "error", err)
-		utils.WriteInternalError(w, "Failed to get naval units")
-		return
-	}
-
-	// Получаем воздушные юниты
-	airUnits, err := h.unitService.GetAirUnitsByGameID(gameID)
-	if err != nil {
-		h.logger.Error("Failed to get air units", "game_id", gameID, "error", err)
-		utils.WriteErrorResponse(w, http.StatusInternalServerError, "Failed to get air units")
+	view, ok := h.getPlayerView(w, r)
+	if !ok {
 		return
 	}
 
 	response := map[string]interface{}{
-		"naval_units": navalUnits,
-		"air_units":   airUnits,
+		"naval_units": view.NavalUnits,
+		"air_units":   view.AirUnits,
+		"contacts":    view.Contacts,
 	}
 
 	utils.WriteSuccessResponse(w, response)
 }
 
-// GetUnit возвращает информацию о конкретном юните
+// GetUnit возвращает информацию о конкретном юните.
+// О корабле противника возвращается только контакт
 func (h *UnitHandler) GetUnit(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	unitID := vars["unitId"]
 
-	// Пытаемся получить как морской юнит
-	navalUnit, err := h.unitService.GetNavalUnitByID(unitID)
-	if err == nil {
+	view, ok := h.getPlayerView(w, r)
+	if !ok {
+		return
+	}
+
+	for i := range view.NavalUnits {
+		navalUnit := &view.NavalUnits[i]
+		if navalUnit.ID != unitID {
+			continue
+		}
 		response := map[string]interface{}{
 			"unit":       navalUnit,
 			"type":       "naval",
@@ -110,15 +208,22 @@ func (h *UnitHandler) GetUnit(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	for _, contact := range view.Contacts {
+		if contact.UnitID == unitID {
+			utils.WriteSuccessResponse(w, map[string]interface{}{
+				"contact": contact,
+				"type":    "contact",
+			})
+			return
+		}
+	}
+
 	// TODO: Добавить получение воздушного юнита
 	utils.WriteErrorResponse(w, http.StatusNotFound, "Unit not found")
 }
 
-// MoveUnit перемещает юнит
+// MoveUnit перемещает юнит стороны игрока в Фазе движения
 func (h *UnitHandler) MoveUnit(w http.ResponseWriter, r *http.Request) {
-	vars := mux.Vars(r)
-	gameID := vars["gameId"]
-
 	var req MoveUnitRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		utils.WriteErrorResponse(w, http.StatusBadRequest, "Invalid request body")
@@ -131,30 +236,38 @@ func (h *UnitHandler) MoveUnit(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Получаем юнит для проверки
-	unit, err := h.unitService.GetNavalUnitByID(req.UnitID)
-	if err != nil {
-		utils.WriteErrorResponse(w, http.StatusNotFound, "Unit not found")
+	game, _, ok := getPlayerGame(w, r)
+	if !ok {
 		return
 	}
-
-	// Проверяем, что юнит принадлежит игре
-	if unit.GameID != gameID {
-		utils.WriteErrorResponse(w, http.StatusForbidden, "Unit does not belong to this game")
+	if !checkPhase(w, game, models.PhaseMovement, services.ErrNotMovementPhase) {
+		return
+	}
+	unit, ok := h.getPlayerUnit(w, r, req.UnitID)
+	if !ok {
 		return
 	}
 
 	// Вычисляем расход топлива (упрощенно)
 	fuelCost := req.Speed // 1 топливо за 1 скорость
 
+	// Преследуемые юниты двигаются первыми
+	if !h.checkMoveOrder(w, game, []models.NavalUnit{*unit}) {
+		return
+	}
+
 	// Перемещаем юнит
-	err = h.unitService.MoveUnit(req.UnitID, req.To, req.Speed, fuelCost, req.Path, 1, models.PhaseMovement)
+	err := h.unitService.MoveUnit(req.UnitID, req.To, req.Speed, fuelCost, req.Path, game.CurrentTurn, game.CurrentPhase)
 	if err != nil {
 		h.logger.Error("Failed to move unit", "unit_id", req.UnitID, "error", err)
 		utils.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
 		return
 	}
 
+	h.revealShadowedMoves(game)
+
+	h.checkGameEnd(game.ID)
+
 	// Получаем обновленный юнит
 	updatedUnit, err := h.unitService.GetNavalUnitByID(req.UnitID)
 	if err != nil {
@@ -171,11 +284,8 @@ func (h *UnitHandler) MoveUnit(w http.ResponseWriter, r *http.Request) {
 	utils.WriteSuccessResponse(w, response)
 }
 
-// SearchUnit выполняет поиск юнитом
+// SearchUnit выполняет поиск юнитом стороны игрока в Фазе поиска
 func (h *UnitHandler) SearchUnit(w http.ResponseWriter, r *http.Request) {
-	vars := mux.Vars(r)
-	gameID := vars["gameId"]
-
 	var req SearchRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		utils.WriteErrorResponse(w, http.StatusBadRequest, "Invalid request body")
@@ -188,21 +298,19 @@ func (h *UnitHandler) SearchUnit(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Получаем юнит для проверки
-	unit, err := h.unitService.GetNavalUnitByID(req.UnitID)
-	if err != nil {
-		utils.WriteErrorResponse(w, http.StatusNotFound, "Unit not found")
+	game, _, ok := getPlayerGame(w, r)
+	if !ok {
 		return
 	}
-
-	// Проверяем, что юнит принадлежит игре
-	if unit.GameID != gameID {
-		utils.WriteErrorResponse(w, http.StatusForbidden, "Unit does not belong to this game")
+	if !checkPhase(w, game, models.PhaseSearch, services.ErrNotSearchPhase) {
+		return
+	}
+	if _, ok := h.getPlayerUnit(w, r, req.UnitID); !ok {
 		return
 	}
 
 	// Выполняем поиск
-	search, err := h.unitService.SearchUnit(req.UnitID, req.TargetHex, req.SearchType, 1, models.PhaseSearch)
+	search, err := h.unitService.SearchUnit(game, req.UnitID, req.TargetHex, req.SearchType)
 	if err != nil {
 		h.logger.Error("Failed to search unit", "unit_id", req.UnitID, "error", err)
 		utils.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
@@ -217,41 +325,239 @@ func (h *UnitHandler) SearchUnit(w http.ResponseWriter, r *http.Request) {
 	utils.WriteSuccessResponse(w, response)
 }
 
+// RepairAtSea выполняет попытку ремонта в море в Фазе движения
+func (h *UnitHandler) RepairAtSea(w http.ResponseWriter, r *http.Request) {
+	unitID := mux.Vars(r)["unitId"]
+
+	game, _, ok := getPlayerGame(w, r)
+	if !ok {
+		return
+	}
+	if _, ok := h.getPlayerUnit(w, r, unitID); !ok {
+		return
+	}
+
+	result, err := h.movementService.RepairAtSea(game, unitID)
+	if err != nil {
+		h.logger.Error("Failed to repair at sea", "unit_id", unitID, "error", err)
+		utils.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	h.revealShadowedMoves(game)
+
+	response := map[string]interface{}{
+		"result":  result,
+		"message": "Repair at sea resolved",
+	}
+
+	utils.WriteSuccessResponse(w, response)
+}
+
+// Patrol отмечает корабль или его ТФ маркером Морского патруля в Фазе движения
+func (h *UnitHandler) Patrol(w http.ResponseWriter, r *http.Request) {
+	unitID := mux.Vars(r)["unitId"]
+
+	game, _, ok := getPlayerGame(w, r)
+	if !ok {
+		return
+	}
+	if _, ok := h.getPlayerUnit(w, r, unitID); !ok {
+		return
+	}
+
+	units, err := h.movementService.Patrol(game, unitID)
+	if err != nil {
+		h.logger.Error("Failed to place patrol marker", "unit_id", unitID, "error", err)
+		utils.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	h.revealShadowedMoves(game)
+
+	response := map[string]interface{}{
+		"units":          units,
+		"search_factors": models.PatrolSearchFactors,
+		"message":        "Patrol marker placed",
+	}
+
+	utils.WriteSuccessResponse(w, response)
+}
+
+// HoldPosition записывает приказ преследуемого юнита остаться в гексе
+func (h *UnitHandler) HoldPosition(w http.ResponseWriter, r *http.Request) {
+	unitID := mux.Vars(r)["unitId"]
+
+	game, _, ok := getPlayerGame(w, r)
+	if !ok {
+		return
+	}
+	if _, ok := h.getPlayerUnit(w, r, unitID); !ok {
+		return
+	}
+
+	reports, err := h.movementService.HoldPosition(game, unitID)
+	if err != nil {
+		h.logger.Error("Failed to hold position", "unit_id", unitID, "error", err)
+		utils.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	response := map[string]interface{}{
+		"revealed": reports,
+		"message":  "Hold order recorded",
+	}
+
+	utils.WriteSuccessResponse(w, response)
+}
+
+// GetMovementStep возвращает текущий шаг Фазы движения
+func (h *UnitHandler) GetMovementStep(w http.ResponseWriter, r *http.Request) {
+	game, userID, ok := getPlayerGame(w, r)
+	if !ok {
+		return
+	}
+	if !checkPhase(w, game, models.PhaseMovement, services.ErrNotMovementPhase) {
+		return
+	}
+
+	step, pending, err := h.movementService.GetMovementStep(game)
+	if err != nil {
+		h.logger.Error("Failed to get movement step", "game_id", game.ID, "error", err)
+		utils.WriteInternalError(w, "Failed to get movement step")
+		return
+	}
+
+	// Игрок видит только свои юниты, которые должны отдать приказ
+	side := game.GetPlayerRole(userID)
+	ownPending := []string{}
+	for _, unit := range pending {
+		if game.GetOwnerSide(unit.Owner) == side {
+			ownPending = append(ownPending, unit.ID)
+		}
+	}
+
+	response := map[string]interface{}{
+		"step":           step,
+		"pending_units":  ownPending,
+		"pending_orders": len(pending),
+	}
+
+	utils.WriteSuccessResponse(w, response)
+}
+
+// RefuelAtSeaRequest представляет запрос на заправку в море
+type RefuelAtSeaRequest struct {
+	TankerID string `json:"tanker_id" validate:"required"`
+}
+
+// RefuelInPort заправляет корабль в порту в Фазе движения
+func (h *UnitHandler) RefuelInPort(w http.ResponseWriter, r *http.Request) {
+	unitID := mux.Vars(r)["unitId"]
+
+	game, _, ok := getPlayerGame(w, r)
+	if !ok {
+		return
+	}
+	if _, ok := h.getPlayerUnit(w, r, unitID); !ok {
+		return
+	}
+
+	fuel, err := h.movementService.RefuelInPort(game, unitID)
+	if err != nil {
+		h.logger.Error("Failed to refuel in port", "unit_id", unitID, "error", err)
+		utils.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	h.checkGameEnd(game.ID)
+
+	h.revealShadowedMoves(game)
+
+	response := map[string]interface{}{
+		"fuel_added": fuel,
+		"message":    "Unit refuelled in port",
+	}
+
+	utils.WriteSuccessResponse(w, response)
+}
+
+// RefuelAtSea заправляет немецкий корабль от танкера в Фазе движения
+func (h *UnitHandler) RefuelAtSea(w http.ResponseWriter, r *http.Request) {
+	unitID := mux.Vars(r)["unitId"]
+
+	var req RefuelAtSeaRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.TankerID == "" {
+		utils.WriteErrorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	game, _, ok := getPlayerGame(w, r)
+	if !ok {
+		return
+	}
+	if _, ok := h.getPlayerUnit(w, r, unitID); !ok {
+		return
+	}
+
+	fuel, err := h.movementService.RefuelAtSea(game, unitID, req.TankerID)
+	if err != nil {
+		h.logger.Error("Failed to refuel at sea", "unit_id", unitID, "error", err)
+		utils.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	h.revealShadowedMoves(game)
+
+	response := map[string]interface{}{
+		"fuel_added": fuel,
+		"message":    "Unit refuelled at sea",
+	}
+
+	utils.WriteSuccessResponse(w, response)
+}
+
 // GetUnitsByPosition возвращает все юниты в указанной позиции
 func (h *UnitHandler) GetUnitsByPosition(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
-	gameID := vars["gameId"]
 	position := vars["position"]
 
-	navalUnits, airUnits, err := h.unitService.GetUnitsByPosition(gameID, position)
-	if err != nil {
-		h.logger.Error("Failed to get units by position", "game_id", gameID, "position", position, "error", err)
-		utils.WriteErrorResponse(w, http.StatusInternalServerError, "Failed to get units by position")
+	view, ok := h.getPlayerView(w, r)
+	if !ok {
 		return
 	}
 
+	navalUnits := []models.NavalUnit{}
+	for _, unit := range view.NavalUnits {
+		if unit.Position == position {
+			navalUnits = append(navalUnits, unit)
+		}
+	}
+	airUnits := []models.AirUnit{}
+	for _, unit := range view.AirUnits {
+		if unit.Position == position {
+			airUnits = append(airUnits, unit)
+		}
+	}
+
 	response := map[string]interface{}{
 		"naval_units": navalUnits,
 		"air_units":   airUnits,
+		"contacts":    view.ContactsAt(position),
 		"position":    position,
 	}
 
 	utils.WriteSuccessResponse(w, response)
 }
 
-// GetTaskForces возвращает все Task Forces игры
+// GetTaskForces возвращает Task Forces игрока
 func (h *UnitHandler) GetTaskForces(w http.ResponseWriter, r *http.Request) {
-	vars := mux.Vars(r)
-	gameID := vars["gameId"]
-
-	taskForces, err := h.taskForceService.GetTaskForcesByGameID(gameID)
-	if err != nil {
-		h.logger.Error("Failed to get task forces", "game_id", gameID, "error", err)
-		utils.WriteErrorResponse(w, http.StatusInternalServerError, "Failed to get task forces")
+	view, ok := h.getPlayerView(w, r)
+	if !ok {
 		return
 	}
 
-	utils.WriteSuccessResponse(w, taskForces)
+	utils.WriteSuccessResponse(w, view.TaskForces)
 }
 
 // GetTaskForce возвращает информацию о конкретном Task Force
@@ -259,8 +565,19 @@ func (h *UnitHandler) GetTaskForce(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	taskForceID := vars["taskForceId"]
 
-	taskForce, err := h.taskForceService.GetTaskForceByID(taskForceID)
-	if err != nil {
+	view, ok := h.getPlayerView(w, r)
+	if !ok {
+		return
+	}
+
+	// Task Forces противника не видны: их корабли известны только как контакты
+	var taskForce *models.TaskForce
+	for i := range view.TaskForces {
+		if view.TaskForces[i].ID == taskForceID {
+			taskForce = &view.TaskForces[i]
+		}
+	}
+	if taskForce == nil {
 		utils.WriteErrorResponse(w, http.StatusNotFound, "Task force not found")
 		return
 	}
@@ -289,6 +606,7 @@ func (h *UnitHandler) GetTaskForce(w http.ResponseWriter, r *http.Request) {
 		"task_force":           taskForce,
 		"units":                units,
 		"effective_speed":      effectiveSpeed,
+		"speed_rating":         models.SlowestSpeedRating(units),
 		"total_search_factors": totalSearchFactors,
 		"can_form":             len(units) > 1,
 		"can_split":            len(units) > 1,
@@ -297,11 +615,8 @@ func (h *UnitHandler) GetTaskForce(w http.ResponseWriter, r *http.Request) {
 	utils.WriteSuccessResponse(w, response)
 }
 
-// CreateTaskForce создает новый Task Force
+// CreateTaskForce создает новый Task Force из кораблей стороны игрока
 func (h *UnitHandler) CreateTaskForce(w http.ResponseWriter, r *http.Request) {
-	vars := mux.Vars(r)
-	gameID := vars["gameId"]
-
 	var req CreateTaskForceRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		utils.WriteErrorResponse(w, http.StatusBadRequest, "Invalid request body")
@@ -314,30 +629,25 @@ func (h *UnitHandler) CreateTaskForce(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Получаем первый юнит для определения владельца и позиции
-	firstUnit, err := h.unitService.GetNavalUnitByID(req.UnitIDs[0])
-	if err != nil {
-		utils.WriteErrorResponse(w, http.StatusNotFound, "First unit not found")
+	game, _, ok := getPlayerGame(w, r)
+	if !ok {
 		return
 	}
-
-	// Проверяем, что юнит принадлежит игре
-	if firstUnit.GameID != gameID {
-		utils.WriteErrorResponse(w, http.StatusForbidden, "Unit does not belong to this game")
-		return
+	for _, unitID := range req.UnitIDs {
+		if _, ok := h.getPlayerUnit(w, r, unitID); !ok {
+			return
+		}
 	}
 
-	// Создаем Task Force
+	// Владелец и позиция определяются по юнитам соединения
 	taskForce := &models.TaskForce{
-		GameID:    gameID,
+		GameID:    game.ID,
 		Name:      req.Name,
-		Owner:     firstUnit.Owner,
-		Position:  firstUnit.Position,
 		Units:     req.UnitIDs,
 		IsVisible: true,
 	}
 
-	err = h.taskForceService.CreateTaskForce(taskForce)
+	err := h.taskForceService.CreateTaskForce(game, taskForce)
 	if err != nil {
 		h.logger.Error("Failed to create task force", "error", err)
 		utils.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
@@ -354,9 +664,6 @@ func (h *UnitHandler) CreateTaskForce(w http.ResponseWriter, r *http.Request) {
 
 // AddUnitToTaskForce добавляет юнит в Task Force
 func (h *UnitHandler) AddUnitToTaskForce(w http.ResponseWriter, r *http.Request) {
-	vars := mux.Vars(r)
-	gameID := vars["gameId"]
-
 	var req AddUnitToTaskForceRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		utils.WriteErrorResponse(w, http.StatusBadRequest, "Invalid request body")
@@ -369,20 +676,20 @@ func (h *UnitHandler) AddUnitToTaskForce(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	// Проверяем, что юнит принадлежит игре
-	unit, err := h.unitService.GetNavalUnitByID(req.UnitID)
-	if err != nil {
-		utils.WriteErrorResponse(w, http.StatusNotFound, "Unit not found")
+	// Соединение и юнит должны принадлежать стороне игрока
+	game, _, ok := getPlayerGame(w, r)
+	if !ok {
 		return
 	}
-
-	if unit.GameID != gameID {
-		utils.WriteErrorResponse(w, http.StatusForbidden, "Unit does not belong to this game")
+	if _, ok := h.getPlayerTaskForce(w, r, req.TaskForceID); !ok {
+		return
+	}
+	if _, ok := h.getPlayerUnit(w, r, req.UnitID); !ok {
 		return
 	}
 
 	// Добавляем юнит в Task Force
-	err = h.taskForceService.AddUnitToTaskForce(req.TaskForceID, req.UnitID)
+	err := h.taskForceService.AddUnitToTaskForce(game, req.TaskForceID, req.UnitID)
 	if err != nil {
 		h.logger.Error("Failed to add unit to task force", "error", err)
 		utils.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
@@ -398,9 +705,6 @@ func (h *UnitHandler) AddUnitToTaskForce(w http.ResponseWriter, r *http.Request)
 
 // RemoveUnitFromTaskForce удаляет юнит из Task Force
 func (h *UnitHandler) RemoveUnitFromTaskForce(w http.ResponseWriter, r *http.Request) {
-	vars := mux.Vars(r)
-	gameID := vars["gameId"]
-
 	var req RemoveUnitFromTaskForceRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		utils.WriteErrorResponse(w, http.StatusBadRequest, "Invalid request body")
@@ -413,20 +717,20 @@ func (h *UnitHandler) RemoveUnitFromTaskForce(w http.ResponseWriter, r *http.Req
 		return
 	}
 
-	// Проверяем, что юнит принадлежит игре
-	unit, err := h.unitService.GetNavalUnitByID(req.UnitID)
-	if err != nil {
-		utils.WriteErrorResponse(w, http.StatusNotFound, "Unit not found")
+	// Соединение и юнит должны принадлежать стороне игрока
+	game, _, ok := getPlayerGame(w, r)
+	if !ok {
 		return
 	}
-
-	if unit.GameID != gameID {
-		utils.WriteErrorResponse(w, http.StatusForbidden, "Unit does not belong to this game")
+	if _, ok := h.getPlayerTaskForce(w, r, req.TaskForceID); !ok {
+		return
+	}
+	if _, ok := h.getPlayerUnit(w, r, req.UnitID); !ok {
 		return
 	}
 
 	// Удаляем юнит из Task Force
-	err = h.taskForceService.RemoveUnitFromTaskForce(req.TaskForceID, req.UnitID)
+	err := h.taskForceService.RemoveUnitFromTaskForce(game, req.TaskForceID, req.UnitID)
 	if err != nil {
 		h.logger.Error("Failed to remove unit from task force", "error", err)
 		utils.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
@@ -442,13 +746,12 @@ func (h *UnitHandler) RemoveUnitFromTaskForce(w http.ResponseWriter, r *http.Req
 
 // MoveTaskForce перемещает Task Force
 func (h *UnitHandler) MoveTaskForce(w http.ResponseWriter, r *http.Request) {
-	vars := mux.Vars(r)
-	gameID := vars["gameId"]
-	taskForceID := vars["taskForceId"]
+	taskForceID := mux.Vars(r)["taskForceId"]
 
 	var req struct {
-		To    string `json:"to" validate:"required"`
-		Speed int    `json:"speed" validate:"required,min=1,max=6"`
+		To    string   `json:"to" validate:"required"`
+		Speed int      `json:"speed" validate:"required,min=1,max=6"`
+		Path  []string `json:"path,omitempty"`
 	}
 
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
@@ -462,26 +765,37 @@ func (h *UnitHandler) MoveTaskForce(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Проверяем, что Task Force принадлежит игре
-	taskForce, err := h.taskForceService.GetTaskForceByID(taskForceID)
-	if err != nil {
-		utils.WriteErrorResponse(w, http.StatusNotFound, "Task force not found")
+	// Проверяем, что Task Force принадлежит стороне игрока
+	game, _, ok := getPlayerGame(w, r)
+	if !ok {
+		return
+	}
+	if _, ok := h.getPlayerTaskForce(w, r, taskForceID); !ok {
 		return
 	}
 
-	if taskForce.GameID != gameID {
-		utils.WriteErrorResponse(w, http.StatusForbidden, "Task force does not belong to this game")
+	// Преследуемые соединения двигаются первыми
+	units, err := h.taskForceService.GetTaskForceUnits(taskForceID)
+	if err != nil {
+		utils.WriteErrorResponse(w, http.StatusInternalServerError, "Failed to get task force units")
+		return
+	}
+	if !h.checkMoveOrder(w, game, units) {
 		return
 	}
 
 	// Перемещаем Task Force
-	err = h.taskForceService.MoveTaskForce(taskForceID, req.To, req.Speed)
+	err = h.taskForceService.MoveTaskForce(game, taskForceID, req.To, req.Speed, req.Path)
 	if err != nil {
 		h.logger.Error("Failed to move task force", "error", err)
 		utils.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
 		return
 	}
 
+	h.revealShadowedMoves(game)
+
+	h.checkGameEnd(game.ID)
+
 	response := map[string]interface{}{
 		"message": "Task force moved successfully",
 	}
@@ -489,29 +803,63 @@ func (h *UnitHandler) MoveTaskForce(w http.ResponseWriter, r *http.Request) {
 	utils.WriteSuccessResponse(w, response)
 }
 
-// DeleteTaskForce удаляет Task Force
-func (h *UnitHandler) DeleteTaskForce(w http.ResponseWriter, r *http.Request) {
-	vars := mux.Vars(r)
-	gameID := vars["gameId"]
-	taskForceID := vars["taskForceId"]
+// SplitTaskForce разделяет Task Force в Фазе движения
+func (h *UnitHandler) SplitTaskForce(w http.ResponseWriter, r *http.Request) {
+	taskForceID := mux.Vars(r)["taskForceId"]
+
+	var req SplitTaskForceRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.WriteErrorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	// Валидация
+	if len(req.UnitIDs) == 0 {
+		utils.WriteErrorResponse(w, http.StatusBadRequest, "Invalid request parameters")
+		return
+	}
+
+	game, _, ok := getPlayerGame(w, r)
+	if !ok {
+		return
+	}
+	if _, ok := h.getPlayerTaskForce(w, r, taskForceID); !ok {
+		return
+	}
 
-	// Проверяем, что Task Force принадлежит игре
-	taskForce, err := h.taskForceService.GetTaskForceByID(taskForceID)
+	split, err := h.taskForceService.SplitTaskForce(game, taskForceID, req.UnitIDs, req.Name)
 	if err != nil {
-		utils.WriteErrorResponse(w, http.StatusNotFound, "Task force not found")
+		h.logger.Error("Failed to split task force", "task_force_id", taskForceID, "error", err)
+		utils.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	if taskForce.GameID != gameID {
-		utils.WriteErrorResponse(w, http.StatusForbidden, "Task force does not belong to this game")
+	response := map[string]interface{}{
+		"task_force": split,
+		"message":    "Task force split successfully",
+	}
+
+	utils.WriteSuccessResponse(w, response)
+}
+
+// DeleteTaskForce удаляет Task Force
+func (h *UnitHandler) DeleteTaskForce(w http.ResponseWriter, r *http.Request) {
+	taskForceID := mux.Vars(r)["taskForceId"]
+
+	// Проверяем, что Task Force принадлежит стороне игрока
+	game, _, ok := getPlayerGame(w, r)
+	if !ok {
+		return
+	}
+	if _, ok := h.getPlayerTaskForce(w, r, taskForceID); !ok {
 		return
 	}
 
 	// Удаляем Task Force
-	err = h.taskForceService.DeleteTaskForce(taskForceID)
+	err := h.taskForceService.DeleteTaskForce(game, taskForceID)
 	if err != nil {
 		h.logger.Error("Failed to delete task force", "error", err)
-		utils.WriteErrorResponse(w, http.StatusInternalServerError, "Failed to delete task force")
+		utils.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
 		return
 	}
 
@@ -522,47 +870,108 @@ func (h *UnitHandler) DeleteTaskForce(w http.ResponseWriter, r *http.Request) {
 	utils.WriteSuccessResponse(w, response)
 }
 
-// GetUnitHistory возвращает историю действий юнита
-func (h *UnitHandler) GetUnitHistory(w http.ResponseWriter, r *http.Request) {
-	vars := mux.Vars(r)
-	unitID := vars["unitId"]
+// unitHistoryQuery возвращает страницу истории юнита
+type unitHistoryQuery func(access *services.GameAccess, unitID string, filter models.HistoryFilter) ([]models.UnitHistoryEntry, int, error)
 
-	// TODO: Реализовать получение истории действий юнита
-	// Пока возвращаем пустой ответ
-	response := map[string]interface{}{
-		"unit_id": unitID,
-		"history": []interface{}{},
+// parseHistoryFilter читает фильтр истории из параметров turn, phase, type, page и per_page
+func parseHistoryFilter(r *http.Request) (models.HistoryFilter, error) {
+	query := r.URL.Query()
+	var filter models.HistoryFilter
+
+	if turnStr := query.Get("turn"); turnStr != "" {
+		turn, err := strconv.Atoi(turnStr)
+		if err != nil || turn < 1 {
+			return filter, errors.New("invalid turn")
+		}
+		filter.Turn = &turn
+	}
+	if phase := query.Get("phase"); phase != "" {
+		if !models.IsValidPhase(phase) {
+			return filter, errors.New("invalid phase")
+		}
+		filter.Phase = models.GamePhase(phase)
 	}
+	filter.Type = query.Get("type")
+	filter.Page, _ = strconv.Atoi(query.Get("page"))
+	filter.PerPage, _ = strconv.Atoi(query.Get("per_page"))
+	filter.Normalize()
 
-	utils.WriteSuccessResponse(w, response)
+	return filter, nil
 }
 
-// GetUnitMovements возвращает историю движений юнита
-func (h *UnitHandler) GetUnitMovements(w http.ResponseWriter, r *http.Request) {
-	vars := mux.Vars(r)
-	unitID := vars["unitId"]
+// writeUnitHistory выполняет запрос истории юнита и записывает страницу в ответ
+func (h *UnitHandler) writeUnitHistory(w http.ResponseWriter, r *http.Request, query unitHistoryQuery) {
+	unitID := mux.Vars(r)["unitId"]
 
-	// TODO: Реализовать получение истории движений юнита
-	// Пока возвращаем пустой ответ
-	response := map[string]interface{}{
-		"unit_id":   unitID,
-		"movements": []interface{}{},
+	access, ok := getGameAccess(w, r)
+	if !ok {
+		return
+	}
+	filter, err := parseHistoryFilter(r)
+	if err != nil {
+		utils.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
 	}
 
-	utils.WriteSuccessResponse(w, response)
+	entries, total, err := query(access, unitID, filter)
+	if err != nil {
+		switch {
+		case services.IsAccessError(err):
+			utils.WriteErrorResponse(w, http.StatusForbidden, err.Error())
+		case errors.Is(err, services.ErrUnitNotFound):
+			utils.WriteErrorResponse(w, http.StatusNotFound, "Unit not found")
+		default:
+			h.logger.Error("Failed to get unit history", "unit_id", unitID, "error", err)
+			utils.WriteInternalError(w, "Failed to get unit history")
+		}
+		return
+	}
+
+	utils.WritePaginatedResponse(w, entries, filter.Page, filter.PerPage, total)
+}
+
+// GetUnitHistory возвращает журнал действий юнита: движения и поиски своего корабля
+// или донесения о корабле противника
+func (h *UnitHandler) GetUnitHistory(w http.ResponseWriter, r *http.Request) {
+	h.writeUnitHistory(w, r, h.unitService.GetUnitHistory)
+}
+
+// GetUnitMovements возвращает историю движений юнита
+func (h *UnitHandler) GetUnitMovements(w http.ResponseWriter, r *http.Request) {
+	h.writeUnitHistory(w, r, h.unitService.GetUnitMovements)
 }
 
 // GetUnitSearches возвращает историю поисков юнита
 func (h *UnitHandler) GetUnitSearches(w http.ResponseWriter, r *http.Request) {
-	vars := mux.Vars(r)
-	unitID := vars["unitId"]
-
-	// TODO: Реализовать получение истории поисков юнита
-	// Пока возвращаем пустой ответ
-	response := map[string]interface{}{
-		"unit_id":  unitID,
-		"searches": []interface{}{},
-	}
+	h.writeUnitHistory(w, r, h.unitService.GetUnitSearches)
+}
 
-	utils.WriteSuccessResponse(w, response)
+// RegisterRoutes регистрирует маршруты юнитов и соединений игры
+func (h *UnitHandler) RegisterRoutes(router *mux.Router, jwtSecret string) {
+	unitRouter := router.PathPrefix("/api/games/{gameId}").Subrouter()
+	unitRouter.Use(middleware.AuthMiddleware(jwtSecret), middleware.GameAccessMiddleware(h.gameAccess))
+
+	unitRouter.HandleFunc("/units", h.GetUnits).Methods("GET")
+	unitRouter.HandleFunc("/units/move", h.MoveUnit).Methods("POST")
+	unitRouter.HandleFunc("/units/search", h.SearchUnit).Methods("POST")
+	unitRouter.HandleFunc("/units/position/{position}", h.GetUnitsByPosition).Methods("GET")
+	unitRouter.HandleFunc("/units/{unitId}", h.GetUnit).Methods("GET")
+	unitRouter.HandleFunc("/units/{unitId}/history", h.GetUnitHistory).Methods("GET")
+	unitRouter.HandleFunc("/units/{unitId}/movements", h.GetUnitMovements).Methods("GET")
+	unitRouter.HandleFunc("/units/{unitId}/searches", h.GetUnitSearches).Methods("GET")
+	unitRouter.HandleFunc("/units/{unitId}/repair", h.RepairAtSea).Methods("POST")
+	unitRouter.HandleFunc("/units/{unitId}/patrol", h.Patrol).Methods("POST")
+	unitRouter.HandleFunc("/units/{unitId}/hold", h.HoldPosition).Methods("POST")
+	unitRouter.HandleFunc("/units/{unitId}/refuel/port", h.RefuelInPort).Methods("POST")
+	unitRouter.HandleFunc("/units/{unitId}/refuel/sea", h.RefuelAtSea).Methods("POST")
+	unitRouter.HandleFunc("/movement/step", h.GetMovementStep).Methods("GET")
+
+	unitRouter.HandleFunc("/taskforces", h.GetTaskForces).Methods("GET")
+	unitRouter.HandleFunc("/taskforces", h.CreateTaskForce).Methods("POST")
+	unitRouter.HandleFunc("/taskforces/add-unit", h.AddUnitToTaskForce).Methods("POST")
+	unitRouter.HandleFunc("/taskforces/remove-unit", h.RemoveUnitFromTaskForce).Methods("POST")
+	unitRouter.HandleFunc("/taskforces/{taskForceId}", h.GetTaskForce).Methods("GET")
+	unitRouter.HandleFunc("/taskforces/{taskForceId}", h.DeleteTaskForce).Methods("DELETE")
+	unitRouter.HandleFunc("/taskforces/{taskForceId}/move", h.MoveTaskForce).Methods("POST")
+	unitRouter.HandleFunc("/taskforces/{taskForceId}/split", h.SplitTaskForce).Methods("POST")
 }
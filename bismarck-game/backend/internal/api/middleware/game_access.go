@@ -0,0 +1,52 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"bismarck-game/backend/pkg/utils"
+
+	"github.com/gorilla/mux"
+)
+
+// ErrGameNotFound возвращается GameAccessResolver, если игры нет
+var ErrGameNotFound = errors.New("game not found")
+
+// GameAccessResolver определяет доступ пользователя к игре. Реализацию задает сервер.
+// write означает, что запрос изменяет игру. Если игры нет, возвращается ErrGameNotFound,
+// остальные ошибки считаются отказом в доступе
+type GameAccessResolver interface {
+	ResolveGameAccess(gameID, userID string, write bool) (interface{}, error)
+}
+
+// GameAccessMiddleware создает middleware, которое определяет доступ пользователя к игре
+// из пути {gameId} и кладет его в контекст. Должно идти после AuthMiddleware.
+// Зрители могут только читать (GET), изменять игру могут только ее игроки
+func GameAccessMiddleware(resolver GameAccessResolver) mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			userID, _ := GetUserIDFromContext(r.Context())
+			write := r.Method != http.MethodGet && r.Method != http.MethodHead
+
+			access, err := resolver.ResolveGameAccess(mux.Vars(r)["gameId"], userID, write)
+			if errors.Is(err, ErrGameNotFound) {
+				utils.WriteErrorResponse(w, http.StatusNotFound, "Game not found")
+				return
+			}
+			if err != nil {
+				utils.WriteErrorResponse(w, http.StatusForbidden, err.Error())
+				return
+			}
+
+			ctx := context.WithValue(r.Context(), "game_access", access)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// GetGameAccessFromContext извлекает доступ пользователя к игре, определенный GameAccessResolver, из контекста
+func GetGameAccessFromContext(ctx context.Context) (interface{}, bool) {
+	access := ctx.Value("game_access")
+	return access, access != nil
+}
@@ -86,6 +86,9 @@ type GameConfig struct {
 	GameStartDelay  Duration `json:"game_start_delay"`
 	MaxGames        int      `json:"max_games"`
 	CleanupInterval Duration `json:"cleanup_interval"`
+	ShipsConfig     string   `json:"ships_config"`  // Путь к каталогу кораблей
+	ShipsReload     Duration `json:"ships_reload"`  // Интервал проверки изменений каталога; 0 - только по SIGHUP
+	ScenariosDir    string   `json:"scenarios_dir"` // Директория файлов сценариев
 }
 
 // LogConfig настройки логирования
@@ -239,6 +242,12 @@ func validateConfig(config *Config) error {
 	if config.Game.TurnDuration == 0 {
 		config.Game.TurnDuration = Duration(30 * time.Second) // default
 	}
+	if config.Game.ShipsConfig == "" {
+		config.Game.ShipsConfig = "config/ships.json" // default
+	}
+	if config.Game.ScenariosDir == "" {
+		config.Game.ScenariosDir = "config/scenarios" // default
+	}
 
 	if len(errors) > 0 {
 		return fmt.Errorf("validation errors: %s", strings.Join(errors, "; "))
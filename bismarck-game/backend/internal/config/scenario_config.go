@@ -0,0 +1,364 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// ScenarioFormatVersion текущая версия формата файла сценария
+const ScenarioFormatVersion = 1
+
+// DefaultScenarioID сценарий по умолчанию - историческая операция "Рейнюбунг"
+const DefaultScenarioID = "rheinubung"
+
+// ScenarioConfig представляет сценарий: расстановку сторон, Трек хода и изменения условий победы
+type ScenarioConfig struct {
+	Version     int               `json:"version"`
+	ID          string            `json:"id"`
+	Name        string            `json:"name"`
+	Description string            `json:"description,omitempty"`
+	Notes       string            `json:"notes,omitempty"`
+	TurnTrack   ScenarioTurnTrack `json:"turnTrack"`
+	PortHexes   []string          `json:"portHexes,omitempty"`
+	FogHexes    []string          `json:"fogHexes,omitempty"`
+	German      ScenarioSide      `json:"german"`
+	Allied      ScenarioSide      `json:"allied"`
+	Victory     *ScenarioVictory  `json:"victory,omitempty"`
+}
+
+// ScenarioTurnTrack представляет Трек хода сценария
+type ScenarioTurnTrack struct {
+	FirstTurn    int `json:"firstTurn"`
+	LastTurn     int `json:"lastTurn"`
+	StartWeather int `json:"startWeather"` // Статус погоды на первом ходу
+}
+
+// ScenarioSide представляет расстановку одной стороны
+type ScenarioSide struct {
+	Ships           []ScenarioShip           `json:"ships"`
+	TaskForces      []ScenarioTaskForce      `json:"taskForces,omitempty"`
+	AirBases        []ScenarioAirBase        `json:"airBases,omitempty"`
+	AirUnits        []ScenarioAirUnit        `json:"airUnits,omitempty"`
+	OptionalUnits   []ScenarioOptionalUnit   `json:"optionalUnits,omitempty"`
+	DeploymentZones []ScenarioDeploymentZone `json:"deploymentZones,omitempty"`
+}
+
+// ScenarioShip представляет корабль из каталога в расстановке
+type ScenarioShip struct {
+	ShipID      string           `json:"shipId"`
+	Hex         string           `json:"hex"`
+	TaskForce   string           `json:"taskForce,omitempty"`   // ID соединения сценария
+	Nationality string           `json:"nationality,omitempty"` // Национальность, если отличается от стороны
+	Fuel        *int             `json:"fuel,omitempty"`        // Начальное топливо; по умолчанию полный бак
+	ArrivalTurn int              `json:"arrivalTurn,omitempty"` // Ход входа подкрепления; 0 - на карте с начала игры
+	DeployZone  string           `json:"deployZone,omitempty"`  // Зона фазы развертывания; пусто - корабль стоит в своем гексе
+	Markers     []ScenarioMarker `json:"markers,omitempty"`
+}
+
+// ScenarioTaskForce представляет оперативное соединение в расстановке
+type ScenarioTaskForce struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// ScenarioDeploymentZone представляет зону, в которой сторона секретно расставляет корабли перед первым ходом
+type ScenarioDeploymentZone struct {
+	ID    string   `json:"id"`
+	Name  string   `json:"name,omitempty"`
+	Hexes []string `json:"hexes"`
+}
+
+// ScenarioMarker представляет маркер, с которым корабль начинает игру
+type ScenarioMarker struct {
+	Type  string `json:"type"`
+	Value int    `json:"value,omitempty"`
+}
+
+// ScenarioAirBase представляет авиабазу стороны
+type ScenarioAirBase struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	Hex  string `json:"hex"`
+}
+
+// ScenarioAirUnit представляет воздушные юниты на авиабазе или авианосце
+type ScenarioAirUnit struct {
+	Type      string `json:"type"` // B или R
+	Base      string `json:"base"` // ID авиабазы или корабля-авианосца сценария
+	MaxSpeed  int    `json:"maxSpeed"`
+	Endurance int    `json:"endurance"`
+	Count     int    `json:"count,omitempty"` // По умолчанию 1
+}
+
+// ScenarioOptionalUnit представляет гипотетический юнит сценария (13.1)
+type ScenarioOptionalUnit struct {
+	ShipID      string `json:"shipId"`
+	VP          int    `json:"vp"`
+	SetupHex    string `json:"setupHex"`
+	RevealBelow int    `json:"revealBelow"`          // Раскрывается при броске 1d10 (0-9) меньше значения
+	DeployZone  string `json:"deployZone,omitempty"` // Зона фазы развертывания купленного юнита
+}
+
+// ScenarioVictory представляет изменения условий победы; пустые поля не меняют настройки
+type ScenarioVictory struct {
+	BismarckSunkVP           *int     `json:"bismarckSunkVP,omitempty"`
+	BismarckUndamagedAtSeaVP *int     `json:"bismarckUndamagedAtSeaVP,omitempty"`
+	BismarckFranceVP         *int     `json:"bismarckFranceVP,omitempty"`
+	BismarckNorwayVP         *int     `json:"bismarckNorwayVP,omitempty"`
+	BismarckEndGameVP        *int     `json:"bismarckEndGameVP,omitempty"`
+	BismarckNoFuelVP         *int     `json:"bismarckNoFuelVP,omitempty"`
+	FrancePortHexes          []string `json:"francePortHexes,omitempty"`
+	NorwayPortHexes          []string `json:"norwayPortHexes,omitempty"`
+}
+
+// GetSide возвращает расстановку стороны
+func (sc *ScenarioConfig) GetSide(side string) *ScenarioSide {
+	switch side {
+	case "german":
+		return &sc.German
+	case "allied":
+		return &sc.Allied
+	}
+	return nil
+}
+
+// Validate проверяет сценарий по каталогу кораблей
+func (sc *ScenarioConfig) Validate(ships *ShipConfigManager) error {
+	if sc.Version != ScenarioFormatVersion {
+		return fmt.Errorf("%w: %d", ErrUnsupportedScenario, sc.Version)
+	}
+	if sc.ID == "" {
+		return &ConfigError{Message: "ID сценария не может быть пустым"}
+	}
+	if sc.TurnTrack.FirstTurn < 1 || sc.TurnTrack.LastTurn < sc.TurnTrack.FirstTurn {
+		return &ConfigError{Message: "некорректный Трек хода сценария"}
+	}
+
+	used := make(map[string]bool)
+	zones := make(map[string]bool)
+	for _, side := range []string{"german", "allied"} {
+		if err := sc.GetSide(side).validate(side, sc.TurnTrack, ships, used, zones); err != nil {
+			return fmt.Errorf("%s: %w", side, err)
+		}
+	}
+
+	return nil
+}
+
+// validate проверяет расстановку стороны
+func (ss *ScenarioSide) validate(side string, track ScenarioTurnTrack, ships *ShipConfigManager, used map[string]bool, zones map[string]bool) error {
+	checkShip := func(shipID string) (*ShipConfig, error) {
+		ship, err := ships.GetShipConfig(shipID)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", shipID, err)
+		}
+		if ship.Side != side {
+			return nil, &ConfigError{Message: "корабль " + shipID + " принадлежит другой стороне"}
+		}
+		if used[shipID] {
+			return nil, &ConfigError{Message: "корабль " + shipID + " указан в сценарии дважды"}
+		}
+		used[shipID] = true
+		return ship, nil
+	}
+
+	taskForceHexes := make(map[string]string)
+	for _, tf := range ss.TaskForces {
+		if tf.ID == "" {
+			return &ConfigError{Message: "ID соединения не может быть пустым"}
+		}
+		taskForceHexes[tf.ID] = ""
+	}
+
+	// ID зон уникальны в сценарии: зоны обеих сторон хранятся в настройках игры вместе
+	deployZones := make(map[string]*ScenarioDeploymentZone)
+	for i := range ss.DeploymentZones {
+		zone := &ss.DeploymentZones[i]
+		if zone.ID == "" || len(zone.Hexes) == 0 {
+			return &ConfigError{Message: "у зоны развертывания должны быть ID и гексы"}
+		}
+		if zones[zone.ID] {
+			return &ConfigError{Message: "зона развертывания " + zone.ID + " указана дважды"}
+		}
+		zones[zone.ID] = true
+		deployZones[zone.ID] = zone
+	}
+	checkZone := func(shipID, zoneID, hex string) error {
+		zone, exists := deployZones[zoneID]
+		if !exists {
+			return &ConfigError{Message: "неизвестная зона развертывания " + zoneID}
+		}
+		for _, zoneHex := range zone.Hexes {
+			if zoneHex == hex {
+				return nil
+			}
+		}
+		return &ConfigError{Message: "гекс расстановки " + shipID + " вне зоны " + zoneID}
+	}
+
+	carriers := make(map[string]bool)
+	deployable := make(map[string]bool)
+	for _, placement := range ss.Ships {
+		ship, err := checkShip(placement.ShipID)
+		if err != nil {
+			return err
+		}
+		if placement.Hex == "" {
+			return &ConfigError{Message: "не указан гекс корабля " + placement.ShipID}
+		}
+		if placement.Fuel != nil && (*placement.Fuel < 0 || *placement.Fuel > ship.MaxFuel) {
+			return &ConfigError{Message: "некорректное топливо корабля " + placement.ShipID}
+		}
+		if placement.ArrivalTurn != 0 && (placement.ArrivalTurn <= track.FirstTurn || placement.ArrivalTurn > track.LastTurn) {
+			return &ConfigError{Message: "ход подкрепления " + placement.ShipID + " вне Трека хода"}
+		}
+		if placement.TaskForce != "" {
+			hex, exists := taskForceHexes[placement.TaskForce]
+			if !exists {
+				return &ConfigError{Message: "неизвестное соединение " + placement.TaskForce}
+			}
+			if placement.ArrivalTurn != 0 {
+				return &ConfigError{Message: "подкрепление " + placement.ShipID + " не может входить в соединение"}
+			}
+			if hex != "" && hex != placement.Hex {
+				return &ConfigError{Message: "корабли соединения " + placement.TaskForce + " находятся в разных гексах"}
+			}
+			taskForceHexes[placement.TaskForce] = placement.Hex
+		}
+		if placement.DeployZone != "" {
+			if err := checkZone(placement.ShipID, placement.DeployZone, placement.Hex); err != nil {
+				return err
+			}
+			// Развертываемый корабль перемещается один: без соединения и не с Трека хода
+			if placement.TaskForce != "" || placement.ArrivalTurn != 0 {
+				return &ConfigError{Message: "развертываемый корабль " + placement.ShipID + " не может входить в соединение или быть подкреплением"}
+			}
+			deployable[placement.ShipID] = true
+		}
+		if ship.Type == "CV" {
+			carriers[placement.ShipID] = true
+		}
+	}
+
+	bases := make(map[string]bool)
+	for _, base := range ss.AirBases {
+		if base.ID == "" || base.Hex == "" {
+			return &ConfigError{Message: "у авиабазы должны быть ID и гекс"}
+		}
+		bases[base.ID] = true
+	}
+	for _, air := range ss.AirUnits {
+		if air.Type != "B" && air.Type != "R" {
+			return &ConfigError{Message: "некорректный тип воздушного юнита " + air.Type}
+		}
+		if !bases[air.Base] && !carriers[air.Base] {
+			return &ConfigError{Message: "неизвестная база воздушного юнита " + air.Base}
+		}
+		if deployable[air.Base] {
+			return &ConfigError{Message: "развертываемый авианосец " + air.Base + " не может быть базой воздушных юнитов"}
+		}
+		if air.MaxSpeed <= 0 || air.Endurance <= 0 || air.Count < 0 {
+			return &ConfigError{Message: "некорректные характеристики воздушного юнита на базе " + air.Base}
+		}
+	}
+
+	for _, optional := range ss.OptionalUnits {
+		if _, err := checkShip(optional.ShipID); err != nil {
+			return err
+		}
+		if optional.SetupHex == "" {
+			return &ConfigError{Message: "не указан гекс подготовки " + optional.ShipID}
+		}
+		if optional.DeployZone != "" {
+			if err := checkZone(optional.ShipID, optional.DeployZone, optional.SetupHex); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// ScenarioManager управляет загруженными сценариями
+type ScenarioManager struct {
+	scenarios map[string]*ScenarioConfig
+}
+
+// NewScenarioManager создает новый менеджер сценариев
+func NewScenarioManager() *ScenarioManager {
+	return &ScenarioManager{
+		scenarios: make(map[string]*ScenarioConfig),
+	}
+}
+
+// LoadScenario загружает сценарий из JSON файла
+func (sm *ScenarioManager) LoadScenario(path string) (*ScenarioConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var scenario ScenarioConfig
+	if err := json.Unmarshal(data, &scenario); err != nil {
+		return nil, fmt.Errorf("%s: %w", filepath.Base(path), err)
+	}
+	if scenario.Version != ScenarioFormatVersion {
+		return nil, fmt.Errorf("%s: %w: %d", filepath.Base(path), ErrUnsupportedScenario, scenario.Version)
+	}
+
+	sm.scenarios[scenario.ID] = &scenario
+	return &scenario, nil
+}
+
+// LoadScenarios загружает все сценарии из директории
+func (sm *ScenarioManager) LoadScenarios(dir string) ([]*ScenarioConfig, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+
+	var scenarios []*ScenarioConfig
+	for _, path := range paths {
+		scenario, err := sm.LoadScenario(path)
+		if err != nil {
+			return nil, err
+		}
+		scenarios = append(scenarios, scenario)
+	}
+
+	return scenarios, nil
+}
+
+// GetScenario возвращает сценарий по ID; пустой ID означает сценарий по умолчанию
+func (sm *ScenarioManager) GetScenario(id string) (*ScenarioConfig, error) {
+	if id == "" {
+		id = DefaultScenarioID
+	}
+
+	scenario, exists := sm.scenarios[id]
+	if !exists {
+		return nil, ErrScenarioNotFound
+	}
+	return scenario, nil
+}
+
+// GetAllScenarios возвращает все загруженные сценарии, отсортированные по ID
+func (sm *ScenarioManager) GetAllScenarios() []*ScenarioConfig {
+	scenarios := make([]*ScenarioConfig, 0, len(sm.scenarios))
+	for _, scenario := range sm.scenarios {
+		scenarios = append(scenarios, scenario)
+	}
+	sort.Slice(scenarios, func(i, j int) bool {
+		return scenarios[i].ID < scenarios[j].ID
+	})
+	return scenarios
+}
+
+// Ошибки сценариев
+var (
+	ErrScenarioNotFound    = &ConfigError{Message: "сценарий не найден"}
+	ErrUnsupportedScenario = &ConfigError{Message: "неподдерживаемая версия формата сценария"}
+)
@@ -0,0 +1,122 @@
+package config
+
+import (
+	"reflect"
+	"strings"
+)
+
+// JSONSchemaDraft версия JSON Schema генерируемых схем
+const JSONSchemaDraft = "http://json-schema.org/draft-07/schema#"
+
+// SchemaField представляет ограничения поля, которые нельзя вывести из типа Go
+type SchemaField struct {
+	Description string
+	Enum        []string
+	Minimum     *int
+	Maximum     *int
+}
+
+// GenerateSchema строит JSON Schema по структуре конфигурации и ее JSON тегам.
+// Поля без omitempty обязательны, неизвестные поля запрещены. Ограничения задаются
+// по пути поля через точку, например "ships.type"
+func GenerateSchema(v interface{}, title string, fields map[string]SchemaField) map[string]interface{} {
+	schema := typeSchema(reflect.TypeOf(v), "", fields)
+	schema["$schema"] = JSONSchemaDraft
+	schema["title"] = title
+	return schema
+}
+
+// typeSchema строит схему типа Go. Элементы срезов используют путь самого среза
+func typeSchema(t reflect.Type, path string, fields map[string]SchemaField) map[string]interface{} {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	schema := make(map[string]interface{})
+	switch t.Kind() {
+	case reflect.Struct:
+		properties := make(map[string]interface{})
+		required := []string{}
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			name, omitempty, ok := jsonFieldName(field)
+			if !ok {
+				continue
+			}
+			fieldPath := joinSchemaPath(path, name)
+			properties[name] = applySchemaField(typeSchema(field.Type, fieldPath, fields), fields[fieldPath])
+			if !omitempty {
+				required = append(required, name)
+			}
+		}
+		schema["type"] = "object"
+		schema["properties"] = properties
+		schema["additionalProperties"] = false
+		if len(required) > 0 {
+			schema["required"] = required
+		}
+	case reflect.Slice, reflect.Array:
+		schema["type"] = "array"
+		schema["items"] = typeSchema(t.Elem(), path, fields)
+	case reflect.String:
+		schema["type"] = "string"
+	case reflect.Bool:
+		schema["type"] = "boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		schema["type"] = "integer"
+	case reflect.Float32, reflect.Float64:
+		schema["type"] = "number"
+	}
+
+	return schema
+}
+
+// applySchemaField добавляет к схеме поля его ограничения
+func applySchemaField(schema map[string]interface{}, field SchemaField) map[string]interface{} {
+	if field.Description != "" {
+		schema["description"] = field.Description
+	}
+	if len(field.Enum) > 0 {
+		schema["enum"] = field.Enum
+	}
+	if field.Minimum != nil {
+		schema["minimum"] = *field.Minimum
+	}
+	if field.Maximum != nil {
+		schema["maximum"] = *field.Maximum
+	}
+	return schema
+}
+
+// jsonFieldName возвращает имя поля в JSON и признак omitempty
+func jsonFieldName(field reflect.StructField) (string, bool, bool) {
+	if field.PkgPath != "" {
+		return "", false, false
+	}
+	tag := field.Tag.Get("json")
+	if tag == "-" {
+		return "", false, false
+	}
+
+	parts := strings.Split(tag, ",")
+	name := parts[0]
+	if name == "" {
+		name = field.Name
+	}
+	omitempty := false
+	for _, option := range parts[1:] {
+		if option == "omitempty" {
+			omitempty = true
+		}
+	}
+	return name, omitempty, true
+}
+
+// joinSchemaPath добавляет имя поля к пути
+func joinSchemaPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
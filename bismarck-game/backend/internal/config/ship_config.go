@@ -1,9 +1,12 @@
 package config
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"os"
 	"path/filepath"
+	"sync"
 )
 
 // ShipConfig представляет конфигурацию корабля
@@ -34,50 +37,171 @@ type SpecialRuleConfig struct {
 
 // ShipsConfig представляет конфигурацию всех кораблей
 type ShipsConfig struct {
-	Ships []ShipConfig `json:"ships"`
+	Schema string       `json:"$schema,omitempty"` // Путь к JSON Schema для редакторов
+	Ships  []ShipConfig `json:"ships"`
 }
 
-// ShipConfigManager управляет конфигурацией кораблей
+// ShipConfigManager управляет конфигурацией кораблей. Каталог версионируется по хешу
+// содержимого: текущая версия используется для новых игр, а загруженные ранее версии
+// остаются доступными для уже начатых игр
 type ShipConfigManager struct {
+	mu       sync.RWMutex
+	config   *ShipsConfig
+	version  string
+	versions map[string]*shipCatalogVersion
+}
+
+// shipCatalogVersion представляет загруженную версию каталога
+type shipCatalogVersion struct {
 	config *ShipsConfig
+	data   []byte
 }
 
 // NewShipConfigManager создает новый менеджер конфигурации кораблей
 func NewShipConfigManager() *ShipConfigManager {
-	return &ShipConfigManager{}
+	return &ShipConfigManager{
+		versions: make(map[string]*shipCatalogVersion),
+	}
 }
 
-// LoadConfig загружает конфигурацию кораблей из JSON файла
-func (scm *ShipConfigManager) LoadConfig(configPath string) error {
+// ShipCatalogVersion возвращает версию каталога по хешу его содержимого
+func ShipCatalogVersion(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])[:16]
+}
+
+// ReadShipsConfig читает файл каталога кораблей
+func ReadShipsConfig(configPath string) ([]byte, error) {
 	// Получаем абсолютный путь к файлу конфигурации
 	absPath, err := filepath.Abs(configPath)
 	if err != nil {
-		return err
+		return nil, err
+	}
+
+	return os.ReadFile(absPath)
+}
+
+// ParseShipsConfig разбирает каталог кораблей из JSON
+func ParseShipsConfig(data []byte) (*ShipsConfig, error) {
+	var config ShipsConfig
+	if err := json.Unmarshal(data, &config); err != nil {
+		return nil, err
 	}
+	return &config, nil
+}
 
-	// Читаем файл
-	data, err := os.ReadFile(absPath)
+// LoadConfig загружает конфигурацию кораблей из JSON файла
+func (scm *ShipConfigManager) LoadConfig(configPath string) error {
+	data, err := ReadShipsConfig(configPath)
 	if err != nil {
 		return err
 	}
 
-	// Парсим JSON
-	var config ShipsConfig
-	if err := json.Unmarshal(data, &config); err != nil {
+	_, err = scm.LoadData(data)
+	return err
+}
+
+// LoadData разбирает каталог и атомарно делает его текущей версией. Возвращает версию каталога
+func (scm *ShipConfigManager) LoadData(data []byte) (string, error) {
+	config, err := ParseShipsConfig(data)
+	if err != nil {
+		return "", err
+	}
+	version := ShipCatalogVersion(data)
+
+	scm.mu.Lock()
+	defer scm.mu.Unlock()
+	scm.addVersion(version, config, data)
+	scm.config = config
+	scm.version = version
+	return version, nil
+}
+
+// AddVersion добавляет сохраненную версию каталога, не меняя текущую
+func (scm *ShipConfigManager) AddVersion(version string, data []byte) error {
+	config, err := ParseShipsConfig(data)
+	if err != nil {
 		return err
 	}
 
-	scm.config = &config
+	scm.mu.Lock()
+	defer scm.mu.Unlock()
+	scm.addVersion(version, config, data)
 	return nil
 }
 
+// addVersion запоминает версию каталога; вызывается под блокировкой
+func (scm *ShipConfigManager) addVersion(version string, config *ShipsConfig, data []byte) {
+	if scm.versions == nil {
+		scm.versions = make(map[string]*shipCatalogVersion)
+	}
+	if _, ok := scm.versions[version]; !ok {
+		scm.versions[version] = &shipCatalogVersion{config: config, data: data}
+	}
+}
+
+// Version возвращает текущую версию каталога
+func (scm *ShipConfigManager) Version() string {
+	scm.mu.RLock()
+	defer scm.mu.RUnlock()
+	return scm.version
+}
+
+// HasVersion проверяет, загружена ли версия каталога
+func (scm *ShipConfigManager) HasVersion(version string) bool {
+	scm.mu.RLock()
+	defer scm.mu.RUnlock()
+	_, ok := scm.versions[version]
+	return ok
+}
+
+// VersionData возвращает исходное содержимое версии каталога
+func (scm *ShipConfigManager) VersionData(version string) ([]byte, error) {
+	scm.mu.RLock()
+	defer scm.mu.RUnlock()
+	catalog, ok := scm.versions[version]
+	if !ok {
+		return nil, ErrCatalogVersionNotFound
+	}
+	return catalog.data, nil
+}
+
+// current возвращает текущий каталог. Загруженный каталог не изменяется, поэтому
+// его можно читать без блокировки после получения
+func (scm *ShipConfigManager) current() *ShipsConfig {
+	scm.mu.RLock()
+	defer scm.mu.RUnlock()
+	return scm.config
+}
+
 // GetShipConfig возвращает конфигурацию корабля по ID
 func (scm *ShipConfigManager) GetShipConfig(shipID string) (*ShipConfig, error) {
-	if scm.config == nil {
+	return findShipConfig(scm.current(), shipID)
+}
+
+// GetVersionShipConfig возвращает конфигурацию корабля по ID из указанной версии каталога.
+// Пустая версия означает текущий каталог
+func (scm *ShipConfigManager) GetVersionShipConfig(version, shipID string) (*ShipConfig, error) {
+	if version == "" {
+		return scm.GetShipConfig(shipID)
+	}
+
+	scm.mu.RLock()
+	catalog, ok := scm.versions[version]
+	scm.mu.RUnlock()
+	if !ok {
+		return nil, ErrCatalogVersionNotFound
+	}
+	return findShipConfig(catalog.config, shipID)
+}
+
+// findShipConfig ищет корабль в каталоге
+func findShipConfig(config *ShipsConfig, shipID string) (*ShipConfig, error) {
+	if config == nil {
 		return nil, ErrConfigNotLoaded
 	}
 
-	for _, ship := range scm.config.Ships {
+	for _, ship := range config.Ships {
 		if ship.ID == shipID {
 			return &ship, nil
 		}
@@ -88,12 +212,13 @@ func (scm *ShipConfigManager) GetShipConfig(shipID string) (*ShipConfig, error)
 
 // GetShipsBySide возвращает все корабли определенной стороны
 func (scm *ShipConfigManager) GetShipsBySide(side string) ([]ShipConfig, error) {
-	if scm.config == nil {
+	config := scm.current()
+	if config == nil {
 		return nil, ErrConfigNotLoaded
 	}
 
 	var ships []ShipConfig
-	for _, ship := range scm.config.Ships {
+	for _, ship := range config.Ships {
 		if ship.Side == side {
 			ships = append(ships, ship)
 		}
@@ -104,12 +229,13 @@ func (scm *ShipConfigManager) GetShipsBySide(side string) ([]ShipConfig, error)
 
 // GetShipsByType возвращает все корабли определенного типа
 func (scm *ShipConfigManager) GetShipsByType(shipType string) ([]ShipConfig, error) {
-	if scm.config == nil {
+	config := scm.current()
+	if config == nil {
 		return nil, ErrConfigNotLoaded
 	}
 
 	var ships []ShipConfig
-	for _, ship := range scm.config.Ships {
+	for _, ship := range config.Ships {
 		if ship.Type == shipType {
 			ships = append(ships, ship)
 		}
@@ -120,21 +246,23 @@ func (scm *ShipConfigManager) GetShipsByType(shipType string) ([]ShipConfig, err
 
 // GetAllShips возвращает все корабли
 func (scm *ShipConfigManager) GetAllShips() ([]ShipConfig, error) {
-	if scm.config == nil {
+	config := scm.current()
+	if config == nil {
 		return nil, ErrConfigNotLoaded
 	}
 
-	return scm.config.Ships, nil
+	return config.Ships, nil
 }
 
 // GetShipNames возвращает список всех названий кораблей
 func (scm *ShipConfigManager) GetShipNames() ([]string, error) {
-	if scm.config == nil {
+	config := scm.current()
+	if config == nil {
 		return nil, ErrConfigNotLoaded
 	}
 
 	var names []string
-	for _, ship := range scm.config.Ships {
+	for _, ship := range config.Ships {
 		names = append(names, ship.Name)
 	}
 
@@ -143,22 +271,24 @@ func (scm *ShipConfigManager) GetShipNames() ([]string, error) {
 
 // IsConfigLoaded проверяет, загружена ли конфигурация
 func (scm *ShipConfigManager) IsConfigLoaded() bool {
-	return scm.config != nil
+	return scm.current() != nil
 }
 
 // GetConfigStats возвращает статистику по конфигурации
 func (scm *ShipConfigManager) GetConfigStats() (*ConfigStats, error) {
-	if scm.config == nil {
+	config := scm.current()
+	if config == nil {
 		return nil, ErrConfigNotLoaded
 	}
 
 	stats := &ConfigStats{
-		TotalShips:  len(scm.config.Ships),
+		Version:     scm.Version(),
+		TotalShips:  len(config.Ships),
 		ShipsBySide: make(map[string]int),
 		ShipsByType: make(map[string]int),
 	}
 
-	for _, ship := range scm.config.Ships {
+	for _, ship := range config.Ships {
 		stats.ShipsBySide[ship.Side]++
 		stats.ShipsByType[ship.Type]++
 	}
@@ -168,6 +298,7 @@ func (scm *ShipConfigManager) GetConfigStats() (*ConfigStats, error) {
 
 // ConfigStats представляет статистику конфигурации
 type ConfigStats struct {
+	Version     string         `json:"version"`
 	TotalShips  int            `json:"totalShips"`
 	ShipsBySide map[string]int `json:"shipsBySide"`
 	ShipsByType map[string]int `json:"shipsByType"`
@@ -177,6 +308,8 @@ type ConfigStats struct {
 var (
 	ErrConfigNotLoaded = &ConfigError{Message: "конфигурация не загружена"}
 	ErrShipNotFound    = &ConfigError{Message: "корабль не найден"}
+
+	ErrCatalogVersionNotFound = &ConfigError{Message: "версия каталога кораблей не найдена"}
 )
 
 // ConfigError представляет ошибку конфигурации
@@ -0,0 +1,57 @@
+package models
+
+// AdminEventType представляет тип события Фазы администрирования
+type AdminEventType string
+
+const (
+	AdminEventReinforcement  AdminEventType = "reinforcement_placed" // Подкрепление размещено
+	AdminEventAirRefitted    AdminEventType = "air_unit_refitted"    // Воздушный юнит вернулся на базу
+	AdminEventAirLanded      AdminEventType = "air_unit_to_refit"    // Воздушный юнит переведен в Ремонт
+	AdminEventMarkerRemoved  AdminEventType = "marker_removed"       // Маркер Фазы движения убран
+	AdminEventNoMovementFlip AdminEventType = "no_movement_flipped"  // Маркер "Нет движения" перевернут
+	AdminEventNoMovementEnd  AdminEventType = "no_movement_removed"  // Маркер "Нет движения" убран
+	AdminEventTurnAdvanced   AdminEventType = "turn_marker_advanced" // Маркер Трека хода перемещен
+	AdminEventContactLost    AdminEventType = "contact_lost"         // Контакт с кораблем противника потерян
+	AdminEventCrewExhausted  AdminEventType = "crew_exhausted"       // Экипаж корабля устал на следующие сутки (13.2)
+)
+
+// AdminPhaseEvent представляет одно изменение в Фазе администрирования
+type AdminPhaseEvent struct {
+	Type     AdminEventType `json:"type"`
+	Side     PlayerSide     `json:"side,omitempty"` // Сторона, которой принадлежит юнит; для потери контакта - сторона, потерявшая контакт
+	UnitID   string         `json:"unit_id,omitempty"`
+	UnitName string         `json:"unit_name,omitempty"`
+	Position string         `json:"position,omitempty"`
+	Marker   string         `json:"marker,omitempty"`
+	Value    int            `json:"value,omitempty"`
+}
+
+// AdminPhaseSummary представляет итог Фазы администрирования
+type AdminPhaseSummary struct {
+	GameID   string            `json:"game_id"`
+	Turn     int               `json:"turn"`
+	NextTurn int               `json:"next_turn"`
+	Events   []AdminPhaseEvent `json:"events"`
+}
+
+// AddEvent добавляет событие в итог
+func (s *AdminPhaseSummary) AddEvent(event AdminPhaseEvent) {
+	s.Events = append(s.Events, event)
+}
+
+// ForSide возвращает итог, содержащий только то, что может знать указанная сторона:
+// события своих юнитов и перемещение маркера Трека хода
+func (s *AdminPhaseSummary) ForSide(side PlayerSide) AdminPhaseSummary {
+	filtered := AdminPhaseSummary{
+		GameID:   s.GameID,
+		Turn:     s.Turn,
+		NextTurn: s.NextTurn,
+		Events:   []AdminPhaseEvent{},
+	}
+	for _, event := range s.Events {
+		if event.Side == side || event.Type == AdminEventTurnAdvanced {
+			filtered.Events = append(filtered.Events, event)
+		}
+	}
+	return filtered
+}
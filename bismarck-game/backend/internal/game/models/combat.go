@@ -0,0 +1,74 @@
+package models
+
+// AirAttackTarget представляет корабль-цель воздушной атаки
+// и модификатор броска атаки против него
+type AirAttackTarget struct {
+	UnitID string `json:"unit_id"`
+	Name   string `json:"name"`
+	Hex    string `json:"hex"`
+	DRM    int    `json:"drm"`
+}
+
+// IsAttackableFromAir проверяет, может ли сторона противника атаковать корабль с воздуха:
+// корабль должен быть на карте и обнаружен или преследуем
+func (u *NavalUnit) IsAttackableFromAir() bool {
+	if !u.IsAlive() || !u.IsOnMap() {
+		return false
+	}
+	return u.IsDetected()
+}
+
+// AirAttackTarget возвращает цель воздушной атаки с модификатором броска атаки против корабля
+func (u *NavalUnit) AirAttackTarget() AirAttackTarget {
+	return AirAttackTarget{
+		UnitID: u.ID,
+		Name:   u.Name,
+		Hex:    u.Position,
+		DRM:    u.GetAirAttackDRM(),
+	}
+}
+
+// TacticalFacingClosing - курс корабля на сближение, с которым он вступает в бой
+const TacticalFacingClosing = "closing"
+
+// NavalCombatant представляет корабль, вступивший в морской бой,
+// с уклоняемостью и модификатором броска стрельбы в этом бою
+type NavalCombatant struct {
+	UnitID               string `json:"unit_id"`
+	Name                 string `json:"name"`
+	Evasion              int    `json:"evasion"`
+	FireDRM              int    `json:"fire_drm"`
+	CrewExhausted        bool   `json:"crew_exhausted"`
+	PrimaryArmamentBow   int    `json:"primary_armament_bow"`   // Носовой главный калибр с учетом специальных правил
+	PrimaryArmamentStern int    `json:"primary_armament_stern"` // Кормовой главный калибр с учетом специальных правил
+	HasFired             bool   `json:"has_fired"`
+}
+
+// NavalCombatant возвращает участника морского боя. Вызывается после EnterTacticalCombat
+func (u *NavalUnit) NavalCombatant() NavalCombatant {
+	return NavalCombatant{
+		UnitID:               u.ID,
+		Name:                 u.Name,
+		Evasion:              u.GetTacticalEvasion(),
+		FireDRM:              u.GetFireDRM(),
+		CrewExhausted:        u.CrewExhausted,
+		PrimaryArmamentBow:   u.PrimaryArmamentBow,
+		PrimaryArmamentStern: u.PrimaryArmamentStern,
+		HasFired:             u.HasFired,
+	}
+}
+
+// NewFireRoll возвращает стрельбу корабля в раунде боя до применения специальных правил.
+// Фактор главного калибра складывается из носовых и кормовых орудий
+func (u *NavalUnit) NewFireRoll(mainArmament bool, preFireRoll int) FireRoll {
+	factor := u.SecondaryArmament
+	if mainArmament {
+		factor = u.PrimaryArmamentBow + u.PrimaryArmamentStern
+	}
+	return FireRoll{
+		MainArmament: mainArmament,
+		Factor:       factor,
+		DRM:          u.GetFireDRM(),
+		PreFireRoll:  preFireRoll,
+	}
+}
@@ -0,0 +1,104 @@
+package models
+
+import "time"
+
+// DeploymentZone представляет зону, в которой сторона расставляет юниты в фазе развертывания
+type DeploymentZone struct {
+	ID    string     `json:"id"`
+	Name  string     `json:"name,omitempty"`
+	Side  PlayerSide `json:"side"`
+	Hexes []string   `json:"hexes"`
+}
+
+// Contains проверяет, входит ли гекс в зону
+func (z DeploymentZone) Contains(hex string) bool {
+	for _, zoneHex := range z.Hexes {
+		if zoneHex == hex {
+			return true
+		}
+	}
+	return false
+}
+
+// DeployableUnit представляет юнит, который сторона расставляет в своей зоне
+type DeployableUnit struct {
+	UnitID string `json:"unit_id"`
+	Zone   string `json:"zone"` // ID зоны развертывания
+}
+
+// DeploymentOption представляет юнит, который игрок может расставить, и его зону
+type DeploymentOption struct {
+	UnitID   string         `json:"unit_id"`
+	UnitName string         `json:"unit_name"`
+	Position string         `json:"position"` // Гекс по умолчанию, если юнит не расставлен
+	Zone     DeploymentZone `json:"zone"`
+}
+
+// DeploymentPlacement представляет выбранный игроком гекс расстановки юнита
+type DeploymentPlacement struct {
+	UnitID string `json:"unit_id"`
+	Hex    string `json:"hex"`
+}
+
+// DeploymentBatch представляет секретную расстановку игрока. Как и приказы на движение,
+// расстановка хранится на сервере нераскрытой и применяется после того, как оба игрока ее зафиксировали
+type DeploymentBatch struct {
+	ID         string                `json:"id" db:"id"`
+	GameID     string                `json:"game_id" db:"game_id"`
+	PlayerID   string                `json:"player_id" db:"player_id"`
+	Side       PlayerSide            `json:"side" db:"side"`
+	Placements []DeploymentPlacement `json:"placements" db:"placements"`
+	Locked     bool                  `json:"locked" db:"locked"`
+	LockedAt   *time.Time            `json:"locked_at" db:"locked_at"`
+	ResolvedAt *time.Time            `json:"resolved_at" db:"resolved_at"`
+	CreatedAt  time.Time             `json:"created_at" db:"created_at"`
+	UpdatedAt  time.Time             `json:"updated_at" db:"updated_at"`
+}
+
+// IsResolved проверяет, применена ли расстановка
+func (b *DeploymentBatch) IsResolved() bool {
+	return b.ResolvedAt != nil
+}
+
+// Status возвращает публичный статус расстановки без ее содержания
+func (b *DeploymentBatch) Status() OrderBatchStatus {
+	return OrderBatchStatus{
+		Side:     b.Side,
+		Locked:   b.Locked,
+		LockedAt: b.LockedAt,
+	}
+}
+
+// DeploymentReady проверяет, можно ли применять расстановку: оба игрока ее зафиксировали
+// или истекло время хода
+func DeploymentReady(game *Game, batches []DeploymentBatch, now time.Time) bool {
+	if game.IsTurnTimerExpired(now) {
+		return true
+	}
+
+	statuses := make([]OrderBatchStatus, 0, len(batches))
+	for i := range batches {
+		statuses = append(statuses, batches[i].Status())
+	}
+	return bothSidesLocked(statuses)
+}
+
+// HasDeployment проверяет, проходит ли игра фазу развертывания перед первым ходом
+func (s GameSettings) HasDeployment() bool {
+	return len(s.DeploymentZones) > 0
+}
+
+// FindDeploymentZone возвращает зону развертывания по ID
+func (s GameSettings) FindDeploymentZone(id string) (DeploymentZone, bool) {
+	for _, zone := range s.DeploymentZones {
+		if zone.ID == id {
+			return zone, true
+		}
+	}
+	return DeploymentZone{}, false
+}
+
+// IsDeployment проверяет, идет ли фаза развертывания
+func (g *Game) IsDeployment() bool {
+	return g.CurrentPhase == PhaseDeployment && g.IsActive()
+}
@@ -0,0 +1,81 @@
+package models
+
+import "time"
+
+// CombatType представляет вид боя, в котором участвовал корабль
+type CombatType string
+
+const (
+	CombatTypeNaval     CombatType = "naval"      // Морской бой
+	CombatTypeAirAttack CombatType = "air_attack" // Воздушная атака
+)
+
+// Штрафы усталости экипажа (13.2)
+const (
+	CrewExhaustionCombats     = 2  // Боев за сутки, после которых экипаж устает
+	CrewExhaustionEvasionLoss = 2  // Потеря уклоняемости в бою
+	CrewExhaustionFireDRM     = -1 // Модификатор броска стрельбы
+)
+
+// CombatParticipation представляет участие корабля в бою
+type CombatParticipation struct {
+	ID        string     `json:"id" db:"id"`
+	GameID    string     `json:"game_id" db:"game_id"`
+	UnitID    string     `json:"unit_id" db:"unit_id"`
+	Turn      int        `json:"turn" db:"turn"`
+	Day       int        `json:"day" db:"day"` // Сутки Трека ходов
+	Type      CombatType `json:"type" db:"type"`
+	CreatedAt time.Time  `json:"created_at" db:"created_at"`
+}
+
+// NewCombatParticipation создает запись об участии корабля в бою на ходу turn
+func NewCombatParticipation(gameID, unitID string, turn int, combatType CombatType) CombatParticipation {
+	return CombatParticipation{
+		GameID: gameID,
+		UnitID: unitID,
+		Turn:   turn,
+		Day:    DayForTurn(turn),
+		Type:   combatType,
+	}
+}
+
+// IsSubjectToCrewExhaustion проверяет, распространяется ли на корабль правило усталости экипажа:
+// только крупные немецкие корабли (BB, CA, CV)
+func (u *NavalUnit) IsSubjectToCrewExhaustion(side PlayerSide) bool {
+	if side != PlayerSideGerman {
+		return false
+	}
+	switch u.Type {
+	case UnitTypeBattleship, UnitTypeHeavyCruiser, UnitTypeAircraftCarrier:
+		return true
+	}
+	return false
+}
+
+// IsCrewExhausted проверяет, устал ли экипаж на ходу turn: корабль участвовал
+// в двух и более боях в предыдущие сутки
+func IsCrewExhausted(records []CombatParticipation, turn int) bool {
+	previousDay := DayForTurn(turn) - 1
+	combats := 0
+	for _, record := range records {
+		if record.Day == previousDay {
+			combats++
+		}
+	}
+	return combats >= CrewExhaustionCombats
+}
+
+// ApplyCrewExhaustion применяет штрафы усталости экипажа к кораблю, вступившему в бой.
+// Вызывается после EnterTacticalCombat; потеря уклоняемости действует только до конца боя
+// и не попадает в Эффекты уклонения
+func (u *NavalUnit) ApplyCrewExhaustion() {
+	u.CrewExhausted = true
+}
+
+// GetFireDRM возвращает модификатор броска стрельбы корабля
+func (u *NavalUnit) GetFireDRM() int {
+	if u.CrewExhausted {
+		return CrewExhaustionFireDRM
+	}
+	return 0
+}
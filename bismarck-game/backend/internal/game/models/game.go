@@ -1,6 +1,7 @@
 package models
 
 import (
+	"strings"
 	"time"
 )
 
@@ -28,6 +29,7 @@ const (
 	PhaseChance      GamePhase = "chance"
 	PhaseAdmin       GamePhase = "admin"
 	PhaseWaiting     GamePhase = "waiting"
+	PhaseDeployment  GamePhase = "deployment" // Секретная расстановка перед первым ходом
 )
 
 // VictoryType представляет тип победы
@@ -47,6 +49,7 @@ type Game struct {
 	Player2ID    string       `json:"player2_id" db:"player2_id"` // Союзник
 	CurrentTurn  int          `json:"current_turn" db:"current_turn"`
 	CurrentPhase GamePhase    `json:"current_phase" db:"current_phase"`
+	Weather      int          `json:"weather" db:"weather"` // Статус на Треке погоды (0-9)
 	GameState    *GameState   `json:"game_state" db:"game_state"`
 	Status       GameStatus   `json:"status" db:"status"`
 	Settings     GameSettings `json:"settings" db:"settings"`
@@ -61,28 +64,40 @@ type Game struct {
 
 // GameSettings представляет настройки игры
 type GameSettings struct {
-	UseOptionalUnits     bool          `json:"use_optional_units"`
-	EnableCrewExhaustion bool          `json:"enable_crew_exhaustion"`
-	VictoryConditions    VictoryConfig `json:"victory_conditions"`
-	TimeLimitMinutes     int           `json:"time_limit_minutes"`
-	PrivateLobby         bool          `json:"private_lobby"`
-	Password             string        `json:"password,omitempty"`
-	MaxTurnTime          int           `json:"max_turn_time"` // в минутах
-	AllowSpectators      bool          `json:"allow_spectators"`
-	AutoSave             bool          `json:"auto_save"`
-	Difficulty           string        `json:"difficulty"`
+	UseOptionalUnits     bool             `json:"use_optional_units"`
+	EnableCrewExhaustion bool             `json:"enable_crew_exhaustion"`
+	VictoryConditions    VictoryConfig    `json:"victory_conditions"`
+	TimeLimitMinutes     int              `json:"time_limit_minutes"`
+	PrivateLobby         bool             `json:"private_lobby"`
+	Password             string           `json:"password,omitempty"`
+	MaxTurnTime          int              `json:"max_turn_time"` // в минутах
+	AllowSpectators      bool             `json:"allow_spectators"`
+	AutoSave             bool             `json:"auto_save"`
+	Difficulty           string           `json:"difficulty"`
+	MaxTurns             int              `json:"max_turns"`                      // Последний ход на Треке ходов
+	PortHexes            []string         `json:"port_hexes"`                     // Гексы с иконкой порта
+	FogHexes             []string         `json:"fog_hexes"`                      // Туманные гексы
+	OptionalUnits        []OptionalUnit   `json:"optional_units,omitempty"`       // Гипотетические юниты (13.1); пусто - по умолчанию
+	Scenario             string           `json:"scenario,omitempty"`             // ID сценария; пусто - сценарий по умолчанию
+	DeploymentZones      []DeploymentZone `json:"deployment_zones,omitempty"`     // Зоны фазы развертывания сценария
+	DeployableUnits      []DeployableUnit `json:"deployable_units,omitempty"`     // Юниты сценария, расставляемые в зонах
+	ShipCatalogVersion   string           `json:"ship_catalog_version,omitempty"` // Версия каталога кораблей, с которой началась игра
 	// maxPlayers убран - всегда 2 игрока
 }
 
-// VictoryConfig представляет конфигурацию условий победы
+// VictoryConfig представляет конфигурацию условий победы.
+// Модификаторы окончания игры, равные nil, берутся из настроек по умолчанию; 0 - допустимое значение
 type VictoryConfig struct {
-	BismarckSunkVP    int                    `json:"bismarck_sunk_vp"`
-	BismarckFranceVP  int                    `json:"bismarck_france_vp"`
-	BismarckNorwayVP  int                    `json:"bismarck_norway_vp"`
-	BismarckEndGameVP int                    `json:"bismarck_end_game_vp"`
-	BismarckNoFuelVP  int                    `json:"bismarck_no_fuel_vp"`
-	ShipVPValues      map[string]interface{} `json:"ship_vp_values"`
-	ConvoyVP          map[string]interface{} `json:"convoy_vp"`
+	BismarckSunkVP           *int                    `json:"bismarck_sunk_vp,omitempty"`
+	BismarckUndamagedAtSeaVP *int                    `json:"bismarck_undamaged_at_sea_vp,omitempty"` // Не поврежден в море с 10+ FP
+	BismarckFranceVP         *int                    `json:"bismarck_france_vp,omitempty"`
+	BismarckNorwayVP         *int                    `json:"bismarck_norway_vp,omitempty"`
+	BismarckEndGameVP        *int                    `json:"bismarck_end_game_vp,omitempty"`
+	BismarckNoFuelVP         *int                    `json:"bismarck_no_fuel_vp,omitempty"`
+	ShipVPValues             map[string]ShipVPConfig `json:"ship_vp_values"`
+	FrancePortHexes          []string                `json:"france_port_hexes"` // Гексы дружественных портов во Франции
+	NorwayPortHexes          []string                `json:"norway_port_hexes"` // Гексы дружественных портов в Норвегии
+	ConvoyVP                 ConvoyVPConfig          `json:"convoy_vp"`
 }
 
 // ShipVPConfig представляет конфигурацию очков за корабли
@@ -91,12 +106,34 @@ type ShipVPConfig struct {
 	Damaged interface{} `json:"damaged"` // может быть числом или "half_hits"
 }
 
+// Специальные значения ShipVPConfig
+const (
+	// ShipVPHullBoxes - VP равно количеству отсеков корпуса
+	ShipVPHullBoxes = "hull_boxes"
+	// ShipVPHalfHits - 1 VP за каждые два попадания
+	ShipVPHalfHits = "half_hits"
+	// ShipVPOthers - ключ конфигурации для всех остальных типов кораблей
+	ShipVPOthers = "others"
+)
+
+// GetDefaultShipVPValues возвращает таблицу очков за корабли по умолчанию
+func GetDefaultShipVPValues() map[string]ShipVPConfig {
+	capital := ShipVPConfig{Sunk: ShipVPHullBoxes, Damaged: ShipVPHalfHits}
+	return map[string]ShipVPConfig{
+		string(UnitTypeBattleship):      capital,
+		string(UnitTypeAircraftCarrier): capital,
+		string(UnitTypeBattlecruiser):   capital,
+		string(UnitTypeHeavyCruiser):    capital,
+		ShipVPOthers:                    {Sunk: 1, Damaged: 0},
+	}
+}
+
 // ConvoyVPConfig представляет конфигурацию очков за конвои
 type ConvoyVPConfig struct {
-	SingleMerchant       float64 `json:"single_merchant"`
-	ConvoyMin            int     `json:"convoy_min"`
-	ConvoyMax            int     `json:"convoy_max"`
-	EscortSunkMultiplier float64 `json:"escort_sunk_multiplier"`
+	SingleMerchant       float64   `json:"single_merchant"`
+	ConvoyMax            float64   `json:"convoy_max"` // Наибольшее значение маркера VP конвоя
+	EscortSunkMultiplier float64   `json:"escort_sunk_multiplier"`
+	MarkerPool           []float64 `json:"marker_pool"` // Значения секретных маркеров VP в пуле
 }
 
 // GameState представляет состояние игры
@@ -119,6 +156,17 @@ const (
 	PlayerSideAllied PlayerSide = "allied"
 )
 
+// Opponent возвращает сторону противника
+func (s PlayerSide) Opponent() PlayerSide {
+	switch s {
+	case PlayerSideGerman:
+		return PlayerSideAllied
+	case PlayerSideAllied:
+		return PlayerSideGerman
+	}
+	return ""
+}
+
 // CreateGameRequest представляет запрос на создание игры
 type CreateGameRequest struct {
 	Name     string       `json:"name" validate:"required,min=3,max=100"`
@@ -155,6 +203,13 @@ type GameResponse struct {
 	LastActionAt    *time.Time   `json:"last_action_at"`
 }
 
+// Public возвращает настройки, которые можно показать обоим игрокам. Расстановка юнитов
+// сценария по зонам развертывания скрыта: каждая сторона получает свои варианты отдельно
+func (s GameSettings) Public() GameSettings {
+	s.DeployableUnits = nil
+	return s
+}
+
 // ToResponse преобразует Game в GameResponse
 func (g *Game) ToResponse() GameResponse {
 	return GameResponse{
@@ -167,7 +222,7 @@ func (g *Game) ToResponse() GameResponse {
 		CurrentTurn:  g.CurrentTurn,
 		CurrentPhase: g.CurrentPhase,
 		Status:       g.Status,
-		Settings:     g.Settings,
+		Settings:     g.Settings.Public(),
 		CreatedAt:    g.CreatedAt,
 		UpdatedAt:    g.UpdatedAt,
 		CompletedAt:  g.CompletedAt,
@@ -192,7 +247,7 @@ func (g *Game) ToResponseWithUsernames(player1Username, player2Username string)
 		CurrentTurn:     g.CurrentTurn,
 		CurrentPhase:    g.CurrentPhase,
 		Status:          g.Status,
-		Settings:        g.Settings,
+		Settings:        g.Settings.Public(),
 		CreatedAt:       g.CreatedAt,
 		UpdatedAt:       g.UpdatedAt,
 		CompletedAt:     g.CompletedAt,
@@ -218,6 +273,11 @@ func (g *Game) IsCompleted() bool {
 	return g.Status == GameStatusCompleted
 }
 
+// IsPreGame проверяет, что игра еще не началась: первый ход не наступил
+func (g *Game) IsPreGame() bool {
+	return g.CurrentPhase == PhaseWaiting && (g.IsWaiting() || g.IsActive())
+}
+
 // CanJoin проверяет, можно ли присоединиться к игре
 func (g *Game) CanJoin() bool {
 	return g.Status == GameStatusWaiting && (g.Player1ID == "" || g.Player2ID == "")
@@ -247,6 +307,30 @@ func (g *Game) GetPlayerRole(userID string) PlayerSide {
 	return ""
 }
 
+// GetOwnerSide возвращает сторону владельца юнита. Владельцем может быть
+// как игрок, так и сама сторона
+func (g *Game) GetOwnerSide(owner string) PlayerSide {
+	if side := g.GetPlayerRole(owner); side != "" {
+		return side
+	}
+	switch PlayerSide(owner) {
+	case PlayerSideGerman, PlayerSideAllied:
+		return PlayerSide(owner)
+	}
+	return ""
+}
+
+// GetPlayerIDBySide возвращает ID игрока, играющего за сторону
+func (g *Game) GetPlayerIDBySide(side PlayerSide) string {
+	switch side {
+	case PlayerSideGerman:
+		return g.Player1ID
+	case PlayerSideAllied:
+		return g.Player2ID
+	}
+	return ""
+}
+
 // IsValidStatus проверяет, является ли статус валидным
 func IsValidStatus(status string) bool {
 	switch GameStatus(status) {
@@ -260,26 +344,37 @@ func IsValidStatus(status string) bool {
 // IsValidPhase проверяет, является ли фаза валидной
 func IsValidPhase(phase string) bool {
 	switch GamePhase(phase) {
-	case PhaseVisibility, PhaseShadow, PhaseMovement, PhaseSearch, PhaseAirAttack, PhaseNavalCombat, PhaseChance, PhaseAdmin, PhaseWaiting:
+	case PhaseVisibility, PhaseShadow, PhaseMovement, PhaseSearch, PhaseAirAttack, PhaseNavalCombat, PhaseChance, PhaseAdmin, PhaseWaiting, PhaseDeployment:
 		return true
 	default:
 		return false
 	}
 }
 
+// intPtr возвращает указатель на значение для настроек по умолчанию
+func intPtr(value int) *int {
+	return &value
+}
+
 // GetDefaultGameSettings возвращает настройки игры по умолчанию
 func GetDefaultGameSettings() GameSettings {
 	return GameSettings{
 		UseOptionalUnits:     false,
 		EnableCrewExhaustion: false,
 		VictoryConditions: VictoryConfig{
-			BismarckSunkVP:    -10,
-			BismarckFranceVP:  -5,
-			BismarckNorwayVP:  -7,
-			BismarckEndGameVP: -10,
-			BismarckNoFuelVP:  -15,
-			ShipVPValues:      make(map[string]interface{}),
-			ConvoyVP:          make(map[string]interface{}),
+			BismarckSunkVP:           intPtr(-10),
+			BismarckUndamagedAtSeaVP: intPtr(-4),
+			BismarckFranceVP:         intPtr(-5),
+			BismarckNorwayVP:         intPtr(-7),
+			BismarckEndGameVP:        intPtr(-10),
+			BismarckNoFuelVP:         intPtr(-15),
+			ShipVPValues:             GetDefaultShipVPValues(),
+			ConvoyVP: ConvoyVPConfig{
+				SingleMerchant:       0.5,
+				ConvoyMax:            MaxConvoyMarkerVP,
+				EscortSunkMultiplier: 1,
+				MarkerPool:           GetDefaultConvoyMarkerPool(),
+			},
 		},
 		TimeLimitMinutes: 180,
 		PrivateLobby:     false,
@@ -287,5 +382,44 @@ func GetDefaultGameSettings() GameSettings {
 		AllowSpectators:  true,
 		AutoSave:         true,
 		Difficulty:       "standard",
+		MaxTurns:         DefaultMaxTurns,
+	}
+}
+
+// DefaultMaxTurns количество ходов на Треке ходов по умолчанию
+const DefaultMaxTurns = 30
+
+// GetMaxTurns возвращает последний ход игры
+func (s GameSettings) GetMaxTurns() int {
+	if s.MaxTurns <= 0 {
+		return DefaultMaxTurns
+	}
+	return s.MaxTurns
+}
+
+// IsPortHex проверяет, находится ли в гексе порт (включая порты условий победы)
+func (s GameSettings) IsPortHex(hex string) bool {
+	return containsHex(s.PortHexes, hex) ||
+		s.VictoryConditions.IsFrancePort(hex) ||
+		s.VictoryConditions.IsNorwayPort(hex)
+}
+
+// IsFrancePort проверяет, является ли гекс дружественным портом во Франции
+func (v VictoryConfig) IsFrancePort(hex string) bool {
+	return containsHex(v.FrancePortHexes, hex)
+}
+
+// IsNorwayPort проверяет, является ли гекс дружественным портом в Норвегии
+func (v VictoryConfig) IsNorwayPort(hex string) bool {
+	return containsHex(v.NorwayPortHexes, hex)
+}
+
+// containsHex проверяет наличие гекса в списке
+func containsHex(hexes []string, hex string) bool {
+	for _, h := range hexes {
+		if strings.EqualFold(h, hex) {
+			return true
+		}
 	}
+	return false
 }
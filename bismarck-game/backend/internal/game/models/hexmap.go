@@ -0,0 +1,38 @@
+package models
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Размер Карты поиска: ряды A-AH сверху вниз и колонки 1-35 слева направо
+const (
+	MapRows    = 34
+	MapColumns = 35
+)
+
+// ParseHex разбирает идентификатор гекса (например, "AH13") на номер ряда и колонки, начиная с 1
+func ParseHex(hex string) (row int, column int, ok bool) {
+	hex = strings.ToUpper(strings.TrimSpace(hex))
+
+	i := 0
+	for i < len(hex) && hex[i] >= 'A' && hex[i] <= 'Z' {
+		row = row*26 + int(hex[i]-'A') + 1
+		i++
+	}
+	if i == 0 || i > 2 || i == len(hex) {
+		return 0, 0, false
+	}
+
+	column, err := strconv.Atoi(hex[i:])
+	if err != nil {
+		return 0, 0, false
+	}
+	return row, column, true
+}
+
+// IsValidHex проверяет, что гекс находится на Карте поиска
+func IsValidHex(hex string) bool {
+	row, column, ok := ParseHex(hex)
+	return ok && row >= 1 && row <= MapRows && column >= 1 && column <= MapColumns
+}
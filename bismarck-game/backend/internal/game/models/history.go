@@ -0,0 +1,94 @@
+package models
+
+import (
+	"sort"
+	"time"
+)
+
+// Типы записей журнала действий юнита
+const (
+	HistoryEntryMovement = "movement" // Движение своего корабля
+	HistoryEntrySearch   = "search"   // Поиск своего корабля
+	HistoryEntryContact  = "contact"  // Донесение о корабле противника
+)
+
+// Размер страницы истории юнита
+const (
+	DefaultHistoryPerPage = 20
+	MaxHistoryPerPage     = 100
+)
+
+// HistoryFilter задает фильтр и страницу запроса истории юнита.
+// Пустые поля не ограничивают выборку
+type HistoryFilter struct {
+	Turn    *int      `json:"turn,omitempty"`
+	Phase   GamePhase `json:"phase,omitempty"`
+	Type    string    `json:"type,omitempty"` // Тип записи журнала или тип поиска
+	Page    int       `json:"page"`
+	PerPage int       `json:"per_page"`
+}
+
+// Normalize подставляет страницу и ее размер по умолчанию
+func (f *HistoryFilter) Normalize() {
+	if f.Page < 1 {
+		f.Page = 1
+	}
+	if f.PerPage < 1 || f.PerPage > MaxHistoryPerPage {
+		f.PerPage = DefaultHistoryPerPage
+	}
+}
+
+// UnitHistoryEntry представляет запись журнала действий юнита.
+// Заполнено только поле, соответствующее типу записи
+type UnitHistoryEntry struct {
+	Type      string         `json:"type"`
+	Turn      int            `json:"turn"`
+	Phase     GamePhase      `json:"phase"`
+	CreatedAt time.Time      `json:"created_at"`
+	Movement  *UnitMovement  `json:"movement,omitempty"`
+	Search    *UnitSearch    `json:"search,omitempty"`
+	Contact   *ContactReport `json:"contact,omitempty"`
+}
+
+// BuildUnitHistory объединяет движения, поиски и донесения в хронологический журнал,
+// оставляет записи типа filter.Type, если он задан, и возвращает запрошенную страницу и общее число записей
+func BuildUnitHistory(movements []UnitMovement, searches []UnitSearch, contacts []ContactReport, filter HistoryFilter) ([]UnitHistoryEntry, int) {
+	filter.Normalize()
+
+	entries := []UnitHistoryEntry{}
+	add := func(entry UnitHistoryEntry) {
+		if filter.Type == "" || filter.Type == entry.Type {
+			entries = append(entries, entry)
+		}
+	}
+	for i := range movements {
+		m := &movements[i]
+		add(UnitHistoryEntry{Type: HistoryEntryMovement, Turn: m.Turn, Phase: m.Phase, CreatedAt: m.CreatedAt, Movement: m})
+	}
+	for i := range searches {
+		s := &searches[i]
+		add(UnitHistoryEntry{Type: HistoryEntrySearch, Turn: s.Turn, Phase: s.Phase, CreatedAt: s.CreatedAt, Search: s})
+	}
+	for i := range contacts {
+		c := &contacts[i]
+		add(UnitHistoryEntry{Type: HistoryEntryContact, Turn: c.Turn, Phase: c.Phase, CreatedAt: c.CreatedAt, Contact: c})
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		if entries[i].Turn != entries[j].Turn {
+			return entries[i].Turn < entries[j].Turn
+		}
+		return entries[i].CreatedAt.Before(entries[j].CreatedAt)
+	})
+
+	total := len(entries)
+	start := (filter.Page - 1) * filter.PerPage
+	if start >= total {
+		return []UnitHistoryEntry{}, total
+	}
+	end := start + filter.PerPage
+	if end > total {
+		end = total
+	}
+	return entries[start:end], total
+}
@@ -0,0 +1,147 @@
+package models
+
+import "time"
+
+// ContactSource представляет источник сведений о противнике
+type ContactSource string
+
+const (
+	ContactSourceSearch       ContactSource = "search"        // Поиск
+	ContactSourceShadow       ContactSource = "shadow"        // Преследование
+	ContactSourceRandomSpot   ContactSource = "random_spot"   // Случайное обнаружение
+	ContactSourceSubmarine    ContactSource = "submarine"     // Подводная лодка
+	ContactSourceConvoyAttack ContactSource = "convoy_attack" // Атака на конвой
+)
+
+// IsValid проверяет, что источник сведений известен
+func (s ContactSource) IsValid() bool {
+	switch s {
+	case ContactSourceSearch, ContactSourceShadow, ContactSourceRandomSpot, ContactSourceSubmarine, ContactSourceConvoyAttack:
+		return true
+	}
+	return false
+}
+
+// ContactReport представляет запись журнала контактов стороны: что, где и когда было
+// обнаружено. Объект донесения - корабль или Task Force противника
+type ContactReport struct {
+	ID             string         `json:"id" db:"id"`
+	GameID         string         `json:"game_id" db:"game_id"`
+	Side           PlayerSide     `json:"side" db:"side"` // Сторона, получившая донесение
+	UnitID         *string        `json:"unit_id" db:"unit_id"`
+	TaskForceID    *string        `json:"task_force_id" db:"task_force_id"`
+	Turn           int            `json:"turn" db:"turn"`
+	Phase          GamePhase      `json:"phase" db:"phase"`
+	Hex            string         `json:"hex" db:"hex"`
+	Class          string         `json:"class" db:"class"` // Доложенный класс (может быть неточным)
+	Count          int            `json:"count" db:"count"` // Доложенное количество кораблей
+	Source         ContactSource  `json:"source" db:"source"`
+	DetectionLevel DetectionLevel `json:"detection_level" db:"detection_level"`
+	CreatedAt      time.Time      `json:"created_at" db:"created_at"`
+}
+
+// TargetID возвращает ID объекта донесения: Task Force или отдельного корабля
+func (r *ContactReport) TargetID() string {
+	if r.TaskForceID != nil {
+		return *r.TaskForceID
+	}
+	if r.UnitID != nil {
+		return *r.UnitID
+	}
+	return ""
+}
+
+// AgedDetectionLevel возвращает уровень обнаружения с учетом давности донесения.
+// Контакт действует до Фазы администрирования следующего хода, затем считается потерянным
+func (r *ContactReport) AgedDetectionLevel(turn int) DetectionLevel {
+	if IsContactExpired(r.Turn, turn) {
+		return DetectionLevelLost
+	}
+	return r.DetectionLevel
+}
+
+// IsContactExpired проверяет, устарел ли контакт, последнее донесение о котором получено на ходу reportTurn
+func IsContactExpired(reportTurn, turn int) bool {
+	return reportTurn < turn-1
+}
+
+// IntelligenceContact представляет сведения стороны об одном корабле или Task Force противника
+type IntelligenceContact struct {
+	TargetID       string          `json:"target_id"`
+	UnitID         *string         `json:"unit_id"`
+	TaskForceID    *string         `json:"task_force_id"`
+	Class          string          `json:"class"`
+	Count          int             `json:"count"`
+	LastKnownPos   string          `json:"last_known_pos"`
+	LastTurn       int             `json:"last_turn"`
+	Source         ContactSource   `json:"source"`
+	DetectionLevel DetectionLevel  `json:"detection_level"`
+	History        []ContactReport `json:"history"`
+}
+
+// BuildIntelligence сводит журнал контактов в сведения по каждому объекту.
+// Донесения должны быть упорядочены по времени; последнее определяет текущие сведения
+func BuildIntelligence(reports []ContactReport, turn int) []IntelligenceContact {
+	contacts := []IntelligenceContact{}
+	index := make(map[string]int)
+
+	for _, report := range reports {
+		targetID := report.TargetID()
+		i, ok := index[targetID]
+		if !ok {
+			i = len(contacts)
+			index[targetID] = i
+			contacts = append(contacts, IntelligenceContact{TargetID: targetID, History: []ContactReport{}})
+		}
+
+		contact := &contacts[i]
+		contact.UnitID = report.UnitID
+		contact.TaskForceID = report.TaskForceID
+		contact.Class = report.Class
+		contact.Count = report.Count
+		contact.LastKnownPos = report.Hex
+		contact.LastTurn = report.Turn
+		contact.Source = report.Source
+		contact.DetectionLevel = report.AgedDetectionLevel(turn)
+		contact.History = append(contact.History, report)
+	}
+
+	return contacts
+}
+
+// NewShadowContactReport возвращает донесение стороны side о новой позиции преследуемого корабля
+func NewShadowContactReport(game *Game, side PlayerSide, unit *NavalUnit) *ContactReport {
+	unitID := unit.ID
+	return &ContactReport{
+		GameID:         game.ID,
+		Side:           side,
+		UnitID:         &unitID,
+		Turn:           game.CurrentTurn,
+		Phase:          game.CurrentPhase,
+		Hex:            unit.Position,
+		Class:          unit.Class,
+		Count:          1,
+		Source:         ContactSourceShadow,
+		DetectionLevel: unit.DetectionLevel,
+	}
+}
+
+// ApplyContact отмечает на корабле донесение противника о нем
+func (u *NavalUnit) ApplyContact(report *ContactReport) {
+	u.DetectionLevel = report.DetectionLevel
+	hex := report.Hex
+	u.LastKnownPos = &hex
+}
+
+// LoseContact переводит в потерянный контакт, не обновленный на ходу turn (Фаза администрирования).
+// Возвращает true, если уровень изменился
+func (u *NavalUnit) LoseContact(lastReportTurn, turn int) bool {
+	if u.DetectionLevel != DetectionLevelSighted && u.DetectionLevel != DetectionLevelShadowed {
+		return false
+	}
+	if !IsContactExpired(lastReportTurn, turn+1) {
+		return false
+	}
+	u.DetectionLevel = DetectionLevelLost
+	return true
+}
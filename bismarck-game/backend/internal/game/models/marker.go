@@ -0,0 +1,209 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+// MarkerType представляет тип игрового маркера
+type MarkerType string
+
+const (
+	MarkerSighted            MarkerType = "sighted"              // Обнаружено
+	MarkerShadowed           MarkerType = "shadowed"             // Преследуется
+	MarkerPatrol             MarkerType = "patrol"               // Морской патруль
+	MarkerInPort             MarkerType = "in_port"              // В порту
+	MarkerRefuelAtSea        MarkerType = "refuel_at_sea"        // Заправка в море
+	MarkerRepairAtSea        MarkerType = "repair_at_sea"        // Ремонт в море
+	MarkerNoMovement         MarkerType = "no_movement"          // Нет движения (1-4)
+	MarkerSearchFlightPath   MarkerType = "search_flight_path"   // Путь полета Поиска
+	MarkerAttackFlightPath   MarkerType = "attack_flight_path"   // Путь полета Атаки
+	MarkerTargetAcquired     MarkerType = "target_acquired"      // Цель захвачена
+	MarkerRudderDamaged      MarkerType = "rudder_damaged"       // Поврежден руль
+	MarkerFireControlDamaged MarkerType = "fire_control_damaged" // Повреждена СУО
+	MarkerRadarDamaged       MarkerType = "radar_damaged"        // Поврежден радар
+)
+
+// MarkerExpiry представляет правило снятия маркера
+type MarkerExpiry string
+
+const (
+	MarkerExpiryAdminPhase MarkerExpiry = "admin_phase"  // Убирается в Фазе администрирования
+	MarkerExpiryCountdown  MarkerExpiry = "countdown"    // Переворачивается в Фазе администрирования (4 -> 3 -> 2 -> 1 -> убрать)
+	MarkerExpiryEndOfPhase MarkerExpiry = "end_of_phase" // Убирается в конце фазы, в которой размещен
+	MarkerExpiryManual     MarkerExpiry = "manual"       // Убирается только по событию (ремонт, потеря контакта)
+)
+
+// Marker представляет маркер на карте или на Карте корабля
+type Marker struct {
+	ID           string       `json:"id" db:"id"`
+	GameID       string       `json:"game_id" db:"game_id"`
+	Type         MarkerType   `json:"type" db:"type"`
+	Owner        PlayerSide   `json:"owner" db:"owner"`     // Сторона, разместившая маркер
+	UnitID       *string      `json:"unit_id" db:"unit_id"` // Юнит, к которому относится маркер
+	Hex          string       `json:"hex" db:"hex"`         // Гекс маркера
+	Value        int          `json:"value" db:"value"`     // Значение (например, N для "Нет движения N")
+	VisibleTo    []PlayerSide `json:"visible_to" db:"visible_to"`
+	Expiry       MarkerExpiry `json:"expiry" db:"expiry"`
+	CreatedTurn  int          `json:"created_turn" db:"created_turn"`
+	CreatedPhase GamePhase    `json:"created_phase" db:"created_phase"`
+	CreatedAt    time.Time    `json:"created_at" db:"created_at"`
+	UpdatedAt    time.Time    `json:"updated_at" db:"updated_at"`
+}
+
+// NewMarker создает маркер с правилом снятия и видимостью по умолчанию для его типа
+func NewMarker(gameID string, markerType MarkerType, owner PlayerSide, turn int, phase GamePhase) *Marker {
+	return &Marker{
+		GameID:       gameID,
+		Type:         markerType,
+		Owner:        owner,
+		VisibleTo:    DefaultMarkerVisibility(markerType, owner),
+		Expiry:       DefaultMarkerExpiry(markerType),
+		CreatedTurn:  turn,
+		CreatedPhase: phase,
+	}
+}
+
+// DefaultMarkerExpiry возвращает правило снятия маркера по умолчанию
+func DefaultMarkerExpiry(markerType MarkerType) MarkerExpiry {
+	switch markerType {
+	case MarkerPatrol, MarkerInPort, MarkerRefuelAtSea, MarkerRepairAtSea:
+		return MarkerExpiryAdminPhase
+	case MarkerNoMovement:
+		return MarkerExpiryCountdown
+	case MarkerSearchFlightPath, MarkerAttackFlightPath:
+		return MarkerExpiryEndOfPhase
+	}
+	return MarkerExpiryManual
+}
+
+// DefaultMarkerVisibility возвращает стороны, которые видят маркер по умолчанию.
+// Маркеры "Обнаружено" и "Преследуется" размещаются на картах обоих игроков,
+// повреждения отмечаются на Картах кораблей, видимых обеим сторонам
+func DefaultMarkerVisibility(markerType MarkerType, owner PlayerSide) []PlayerSide {
+	switch markerType {
+	case MarkerSighted, MarkerShadowed, MarkerRudderDamaged, MarkerFireControlDamaged, MarkerRadarDamaged:
+		return []PlayerSide{PlayerSideGerman, PlayerSideAllied}
+	}
+	return []PlayerSide{owner}
+}
+
+// IsVisibleTo проверяет, видит ли сторона маркер
+func (m *Marker) IsVisibleTo(side PlayerSide) bool {
+	for _, s := range m.VisibleTo {
+		if s == side {
+			return true
+		}
+	}
+	return false
+}
+
+// RevealTo делает маркер видимым для стороны
+func (m *Marker) RevealTo(side PlayerSide) {
+	if !m.IsVisibleTo(side) {
+		m.VisibleTo = append(m.VisibleTo, side)
+	}
+}
+
+// Flip переворачивает маркер с обратным отсчетом.
+// Возвращает true, если маркер нужно убрать
+func (m *Marker) Flip() bool {
+	if m.Expiry != MarkerExpiryCountdown {
+		return false
+	}
+	m.Value--
+	return m.Value <= 0
+}
+
+// ExpiresInAdminPhase проверяет, убирается ли маркер в Фазе администрирования
+func (m *Marker) ExpiresInAdminPhase() bool {
+	return m.Expiry == MarkerExpiryAdminPhase
+}
+
+// ExpiresAtEndOf проверяет, убирается ли маркер в конце указанной фазы
+func (m *Marker) ExpiresAtEndOf(turn int, phase GamePhase) bool {
+	return m.Expiry == MarkerExpiryEndOfPhase && m.CreatedTurn == turn && m.CreatedPhase == phase
+}
+
+// MarkerFilter представляет условия выборки маркеров
+type MarkerFilter struct {
+	Type      MarkerType `json:"type,omitempty"`
+	UnitID    string     `json:"unit_id,omitempty"`
+	Hex       string     `json:"hex,omitempty"`
+	VisibleTo PlayerSide `json:"visible_to,omitempty"`
+}
+
+// Matches проверяет, подходит ли маркер под фильтр
+func (f MarkerFilter) Matches(m *Marker) bool {
+	if f.Type != "" && m.Type != f.Type {
+		return false
+	}
+	if f.UnitID != "" && (m.UnitID == nil || *m.UnitID != f.UnitID) {
+		return false
+	}
+	if f.Hex != "" && m.Hex != f.Hex {
+		return false
+	}
+	if f.VisibleTo != "" && !m.IsVisibleTo(f.VisibleTo) {
+		return false
+	}
+	return true
+}
+
+// UnitMarkers строит маркеры состояния кораблей: маркеры Фазы движения, "Нет движения N",
+// Обнаружено и Преследуется. Эти маркеры не хранятся в game_markers: единственный источник
+// их состояния - поля юнита. Оперативное соединение получает один маркер Морского патруля
+func UnitMarkers(game *Game, units []NavalUnit) []Marker {
+	markers := []Marker{}
+	patrols := make(map[string]bool)
+
+	for i := range units {
+		unit := &units[i]
+		if !unit.IsAlive() || !unit.IsOnMap() {
+			continue
+		}
+		side := game.GetOwnerSide(unit.Owner)
+
+		if markerType, ok := unit.MovementMarker(); ok && !patrols[patrolGroup(unit, markerType)] {
+			if markerType == MarkerPatrol {
+				patrols[patrolGroup(unit, markerType)] = true
+			}
+			markers = append(markers, *newUnitMarker(game, unit, markerType, side, unit.Position))
+		}
+		if unit.NoMovement > 0 {
+			marker := newUnitMarker(game, unit, MarkerNoMovement, side, unit.Position)
+			marker.Value = unit.NoMovement
+			markers = append(markers, *marker)
+		}
+		if unit.IsDetected() && unit.LastKnownPos != nil {
+			markerType := MarkerSighted
+			if unit.DetectionLevel == DetectionLevelShadowed {
+				markerType = MarkerShadowed
+			}
+			markers = append(markers, *newUnitMarker(game, unit, markerType, side.Opponent(), *unit.LastKnownPos))
+		}
+	}
+
+	return markers
+}
+
+// newUnitMarker создает маркер состояния корабля
+func newUnitMarker(game *Game, unit *NavalUnit, markerType MarkerType, owner PlayerSide, hex string) *Marker {
+	marker := NewMarker(game.ID, markerType, owner, game.CurrentTurn, game.CurrentPhase)
+	marker.ID = fmt.Sprintf("%s:%s", markerType, unit.ID)
+	marker.UnitID = &unit.ID
+	marker.Hex = hex
+	return marker
+}
+
+// patrolGroup возвращает ключ, по которому маркер Морского патруля ставится один раз:
+// соединение или одиночный корабль. Для других маркеров ключ пуст
+func patrolGroup(unit *NavalUnit, markerType MarkerType) string {
+	if markerType != MarkerPatrol {
+		return ""
+	}
+	if unit.TaskForceID != nil {
+		return *unit.TaskForceID
+	}
+	return unit.ID
+}
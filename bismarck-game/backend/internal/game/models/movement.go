@@ -0,0 +1,67 @@
+package models
+
+// MovementStep представляет шаг Фазы движения
+type MovementStep string
+
+const (
+	MovementStepShadowed MovementStep = "shadowed" // Преследуемые юниты двигаются первыми (7.1)
+	MovementStepHidden   MovementStep = "hidden"   // Остальные юниты двигаются одновременно и скрытно
+)
+
+// ShadowedMoveReport сообщает противнику новую позицию преследуемого юнита
+type ShadowedMoveReport struct {
+	UnitID   string     `json:"unit_id"`
+	UnitName string     `json:"unit_name"`
+	Side     PlayerSide `json:"side"`
+	From     string     `json:"from,omitempty"` // Последняя известная противнику позиция
+	Position string     `json:"position"`
+}
+
+// IsShadowed проверяет, отмечен ли юнит на карте маркером "Преследуется"
+func (u *NavalUnit) IsShadowed() bool {
+	return u.IsOnMap() && u.DetectionLevel == DetectionLevelShadowed
+}
+
+// MustMoveFirst проверяет, должен ли юнит отдать приказ на движение до остальных юнитов
+func (u *NavalUnit) MustMoveFirst() bool {
+	return u.IsShadowed() && u.CanMove()
+}
+
+// RevealShadowedMove сообщает новую позицию преследуемого юнита и переворачивает
+// маркер "Преследуется" на "Обнаружено" (7.8)
+func (u *NavalUnit) RevealShadowedMove() ShadowedMoveReport {
+	report := ShadowedMoveReport{
+		UnitID:   u.ID,
+		UnitName: u.Name,
+		Position: u.Position,
+	}
+	if u.LastKnownPos != nil {
+		report.From = *u.LastKnownPos
+	}
+
+	u.DetectionLevel = DetectionLevelSighted
+	position := u.Position
+	u.LastKnownPos = &position
+
+	return report
+}
+
+// PendingShadowedUnits возвращает преследуемые юниты, еще не отдавшие приказ на движение
+func PendingShadowedUnits(units []NavalUnit, ordered map[string]bool) []NavalUnit {
+	pending := []NavalUnit{}
+	for _, unit := range units {
+		if unit.MustMoveFirst() && !ordered[unit.ID] {
+			pending = append(pending, unit)
+		}
+	}
+	return pending
+}
+
+// CurrentMovementStep возвращает шаг Фазы движения: пока преследуемые юниты не отдали
+// приказы, остальные юниты не двигаются
+func CurrentMovementStep(units []NavalUnit, ordered map[string]bool) MovementStep {
+	if len(PendingShadowedUnits(units, ordered)) > 0 {
+		return MovementStepShadowed
+	}
+	return MovementStepHidden
+}
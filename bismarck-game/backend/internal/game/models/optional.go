@@ -0,0 +1,70 @@
+package models
+
+import "time"
+
+// OptionalUnit представляет гипотетический юнит, который сторона может купить до начала игры (13.1)
+type OptionalUnit struct {
+	ShipID      string     `json:"ship_id"` // ID корабля в ships.json
+	Side        PlayerSide `json:"side"`
+	VP          int        `json:"vp"`                    // Стоимость с точки зрения немецкого игрока
+	SetupHex    string     `json:"setup_hex"`             // Гекс расстановки
+	RevealBelow int        `json:"reveal_below"`          // Противнику сообщается о покупке, если бросок 1d10 меньше этого значения
+	DeployZone  string     `json:"deploy_zone,omitempty"` // Зона фазы развертывания; пусто - юнит остается в гексе расстановки
+}
+
+// IsRevealedBy проверяет, требует ли бросок сообщить противнику о покупке
+func (o OptionalUnit) IsRevealedBy(roll int) bool {
+	return roll < o.RevealBelow
+}
+
+// GetDefaultOptionalUnits возвращает гипотетические юниты по таблице 13.1
+func GetDefaultOptionalUnits() []OptionalUnit {
+	return []OptionalUnit{
+		{ShipID: "tirpitz", Side: PlayerSideGerman, VP: -9, SetupHex: "O33", RevealBelow: 9},
+		{ShipID: "scharnhorst", Side: PlayerSideGerman, VP: -7, SetupHex: "U26", RevealBelow: 8},
+		{ShipID: "gneisenau", Side: PlayerSideGerman, VP: -8, SetupHex: "U26", RevealBelow: 6},
+		{ShipID: "koln", Side: PlayerSideGerman, VP: -3, SetupHex: "O32", RevealBelow: 6},
+		{ShipID: "destroyers_1", Side: PlayerSideAllied, VP: 1, SetupHex: "AE12", RevealBelow: 3},
+		{ShipID: "destroyers_2", Side: PlayerSideAllied, VP: 2, SetupHex: "H7", RevealBelow: 3},
+		{ShipID: "new_york", Side: PlayerSideAllied, VP: 5, SetupHex: "L2", RevealBelow: 5},
+		{ShipID: "augusta", Side: PlayerSideAllied, VP: 3, SetupHex: "M1", RevealBelow: 6},
+	}
+}
+
+// GetOptionalUnits возвращает гипотетические юниты игры
+func (s GameSettings) GetOptionalUnits() []OptionalUnit {
+	if len(s.OptionalUnits) == 0 {
+		return GetDefaultOptionalUnits()
+	}
+	return s.OptionalUnits
+}
+
+// FindOptionalUnit возвращает гипотетический юнит по ID корабля
+func (s GameSettings) FindOptionalUnit(shipID string) (OptionalUnit, bool) {
+	for _, option := range s.GetOptionalUnits() {
+		if option.ShipID == shipID {
+			return option, true
+		}
+	}
+	return OptionalUnit{}, false
+}
+
+// OptionalUnitPurchase представляет покупку гипотетического юнита
+type OptionalUnitPurchase struct {
+	ID         string     `json:"id" db:"id"`
+	GameID     string     `json:"game_id" db:"game_id"`
+	Side       PlayerSide `json:"side" db:"side"`
+	ShipID     string     `json:"ship_id" db:"ship_id"`
+	UnitID     string     `json:"unit_id" db:"unit_id"`
+	VP         int        `json:"vp" db:"vp"`
+	RevealRoll int        `json:"reveal_roll" db:"reveal_roll"` // Секретный бросок, известен только покупателю
+	Revealed   bool       `json:"revealed" db:"revealed"`
+	CreatedAt  time.Time  `json:"created_at" db:"created_at"`
+}
+
+// OptionalUnitOption представляет гипотетический юнит в списке выбора игрока
+type OptionalUnitOption struct {
+	OptionalUnit
+	Name      string `json:"name"`
+	Purchased bool   `json:"purchased"`
+}
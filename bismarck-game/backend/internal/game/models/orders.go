@@ -0,0 +1,109 @@
+package models
+
+import "time"
+
+// MovementOrder представляет приказ на движение корабля или ТФ
+type MovementOrder struct {
+	UnitID      string   `json:"unit_id,omitempty"`
+	TaskForceID string   `json:"task_force_id,omitempty"`
+	To          string   `json:"to"`
+	Speed       int      `json:"speed"`
+	Path        []string `json:"path,omitempty"`
+}
+
+// OrderBatch представляет приказы игрока на ход. Приказы хранятся на сервере
+// нераскрытыми и выполняются только после того, как оба игрока их зафиксировали
+type OrderBatch struct {
+	ID         string          `json:"id" db:"id"`
+	GameID     string          `json:"game_id" db:"game_id"`
+	PlayerID   string          `json:"player_id" db:"player_id"`
+	Side       PlayerSide      `json:"side" db:"side"`
+	Turn       int             `json:"turn" db:"turn"`
+	Orders     []MovementOrder `json:"orders" db:"orders"`
+	Locked     bool            `json:"locked" db:"locked"`
+	LockedAt   *time.Time      `json:"locked_at" db:"locked_at"`
+	ResolvedAt *time.Time      `json:"resolved_at" db:"resolved_at"`
+	CreatedAt  time.Time       `json:"created_at" db:"created_at"`
+	UpdatedAt  time.Time       `json:"updated_at" db:"updated_at"`
+}
+
+// OrderBatchStatus представляет то, что противник знает о приказах игрока
+type OrderBatchStatus struct {
+	Side     PlayerSide `json:"side"`
+	Locked   bool       `json:"locked"`
+	LockedAt *time.Time `json:"locked_at,omitempty"`
+}
+
+// OrderResult представляет результат выполнения приказа
+type OrderResult struct {
+	Order MovementOrder `json:"order"`
+	Error string        `json:"error,omitempty"`
+}
+
+// IsResolved проверяет, выполнены ли приказы
+func (b *OrderBatch) IsResolved() bool {
+	return b.ResolvedAt != nil
+}
+
+// CanEdit проверяет, может ли игрок изменять приказы
+func (b *OrderBatch) CanEdit() bool {
+	return !b.Locked && !b.IsResolved()
+}
+
+// Status возвращает публичный статус приказов без их содержания
+func (b *OrderBatch) Status() OrderBatchStatus {
+	return OrderBatchStatus{
+		Side:     b.Side,
+		Locked:   b.Locked,
+		LockedAt: b.LockedAt,
+	}
+}
+
+// IsTurnTimerExpired проверяет, истекло ли время на отдачу приказов.
+// Отсчет ведется от начала фазы (последнего действия в игре)
+func (g *Game) IsTurnTimerExpired(now time.Time) bool {
+	if g.Settings.MaxTurnTime <= 0 || g.LastActionAt == nil {
+		return false
+	}
+	deadline := g.LastActionAt.Add(time.Duration(g.Settings.MaxTurnTime) * time.Minute)
+	return !now.Before(deadline)
+}
+
+// OrdersReady проверяет, можно ли выполнять приказы хода: оба игрока зафиксировали
+// приказы или истекло время хода
+func OrdersReady(game *Game, batches []OrderBatch, now time.Time) bool {
+	if game.IsTurnTimerExpired(now) {
+		return true
+	}
+
+	statuses := make([]OrderBatchStatus, 0, len(batches))
+	for i := range batches {
+		statuses = append(statuses, batches[i].Status())
+	}
+	return bothSidesLocked(statuses)
+}
+
+// PhaseReady проверяет, можно ли завершить фазу без приказов: обе стороны закончили фазу
+// или истекло время хода
+func PhaseReady(game *Game, readySides []PlayerSide, now time.Time) bool {
+	if game.IsTurnTimerExpired(now) {
+		return true
+	}
+
+	statuses := make([]OrderBatchStatus, 0, len(readySides))
+	for _, side := range readySides {
+		statuses = append(statuses, OrderBatchStatus{Side: side, Locked: true})
+	}
+	return bothSidesLocked(statuses)
+}
+
+// bothSidesLocked проверяет, что обе стороны зафиксировали свой выбор
+func bothSidesLocked(statuses []OrderBatchStatus) bool {
+	locked := make(map[PlayerSide]bool)
+	for _, status := range statuses {
+		if status.Locked {
+			locked[status.Side] = true
+		}
+	}
+	return locked[PlayerSideGerman] && locked[PlayerSideAllied]
+}
@@ -0,0 +1,216 @@
+package models
+
+import (
+	"sort"
+	"sync"
+)
+
+// CombatPhase представляет фазу тактического боя
+type CombatPhase string
+
+const (
+	CombatPhaseInitial CombatPhase = "initial" // Начальная фаза
+	CombatPhaseMain    CombatPhase = "main"    // Основная фаза
+)
+
+// CombatRange представляет дистанцию тактического боя
+type CombatRange string
+
+const (
+	CombatRangeShort   CombatRange = "short"
+	CombatRangeMedium  CombatRange = "medium"
+	CombatRangeLong    CombatRange = "long"
+	CombatRangeExtreme CombatRange = "extreme"
+)
+
+// UnreliableArmamentFailBelow бросок 1d10 перед стрельбой, при котором ненадежный главный калибр теряет 1 фактор
+const UnreliableArmamentFailBelow = 2
+
+// CombatContext представляет обстановку боя, передаваемую хукам специальных правил.
+// Пустые поля означают, что обстановка не известна и правило не ограничивает юнит
+type CombatContext struct {
+	Phase CombatPhase `json:"phase,omitempty"`
+	Round int         `json:"round,omitempty"`
+	Range CombatRange `json:"range,omitempty"`
+}
+
+// FireRoll представляет стрельбу юнита, которую хуки могут изменить перед броском
+type FireRoll struct {
+	MainArmament bool `json:"main_armament"` // Стреляет главный калибр
+	Factor       int  `json:"factor"`        // Фактор вооружения
+	DRM          int  `json:"drm"`           // Модификатор броска
+	PreFireRoll  int  `json:"pre_fire_roll"` // Бросок 1d10 перед стрельбой (0-9)
+}
+
+// SpecialRuleHooks представляет эффекты специального правила в точках расширения боя.
+// Правило задает только нужные ему хуки
+type SpecialRuleHooks struct {
+	// PreFire изменяет стрельбу юнита перед броском
+	PreFire func(unit *NavalUnit, ctx CombatContext, roll *FireRoll)
+	// MainGunsInRange проверяет, может ли главный калибр стрелять на дистанции боя
+	MainGunsInRange func(unit *NavalUnit, ctx CombatContext) bool
+	// SternGunsAvailable проверяет, может ли кормовой главный калибр стрелять в фазе боя
+	SternGunsAvailable func(unit *NavalUnit, ctx CombatContext) bool
+	// AfterRound проверяет после раунда боя, активируется ли правило
+	AfterRound func(unit *NavalUnit, ctx CombatContext) bool
+	// Triggered применяет постоянный эффект активированного правила
+	Triggered func(unit *NavalUnit)
+}
+
+// specialRuleHooksMu защищает specialRuleHooks от одновременной регистрации и чтения
+var specialRuleHooksMu sync.RWMutex
+
+// specialRuleHooks хуки зарегистрированных типов специальных правил
+var specialRuleHooks = map[SpecialRuleType]SpecialRuleHooks{
+	SpecialRuleUnreliableMainArmament: {
+		PreFire: unreliableMainArmamentPreFire,
+	},
+	SpecialRuleSternGunsInitialPhaseOnly: {
+		SternGunsAvailable: func(unit *NavalUnit, ctx CombatContext) bool {
+			return ctx.Phase == "" || ctx.Phase == CombatPhaseInitial
+		},
+	},
+	SpecialRuleNoMainGunsExtremeRange: {
+		MainGunsInRange: func(unit *NavalUnit, ctx CombatContext) bool {
+			return ctx.Range != CombatRangeExtreme
+		},
+	},
+	SpecialRuleRadarLossAfterFirstRound: {
+		AfterRound: func(unit *NavalUnit, ctx CombatContext) bool {
+			return ctx.Round >= 1
+		},
+		Triggered: func(unit *NavalUnit) {
+			unit.RadarLevel = 0
+		},
+	},
+}
+
+// RegisterSpecialRuleHooks регистрирует эффекты типа специального правила.
+// Новые правила из ships.json добавляются регистрацией хуков без изменения логики боя
+func RegisterSpecialRuleHooks(ruleType SpecialRuleType, hooks SpecialRuleHooks) {
+	specialRuleHooksMu.Lock()
+	defer specialRuleHooksMu.Unlock()
+	specialRuleHooks[ruleType] = hooks
+}
+
+// UnregisterSpecialRuleHooks удаляет эффекты типа специального правила
+func UnregisterSpecialRuleHooks(ruleType SpecialRuleType) {
+	specialRuleHooksMu.Lock()
+	defer specialRuleHooksMu.Unlock()
+	delete(specialRuleHooks, ruleType)
+}
+
+// GetSpecialRuleHooks возвращает хуки типа специального правила
+func GetSpecialRuleHooks(ruleType SpecialRuleType) (SpecialRuleHooks, bool) {
+	specialRuleHooksMu.RLock()
+	defer specialRuleHooksMu.RUnlock()
+	hooks, ok := specialRuleHooks[ruleType]
+	return hooks, ok
+}
+
+// SpecialRuleTypes возвращает зарегистрированные типы специальных правил, отсортированные по имени
+func SpecialRuleTypes() []SpecialRuleType {
+	specialRuleHooksMu.RLock()
+	defer specialRuleHooksMu.RUnlock()
+	types := make([]SpecialRuleType, 0, len(specialRuleHooks))
+	for ruleType := range specialRuleHooks {
+		types = append(types, ruleType)
+	}
+	sort.Slice(types, func(i, j int) bool {
+		return types[i] < types[j]
+	})
+	return types
+}
+
+// unreliableMainArmamentPreFire снижает фактор ненадежного главного калибра при неудачном броске перед стрельбой
+func unreliableMainArmamentPreFire(unit *NavalUnit, ctx CombatContext, roll *FireRoll) {
+	if roll.MainArmament && roll.PreFireRoll < UnreliableArmamentFailBelow && roll.Factor > 0 {
+		roll.Factor--
+	}
+}
+
+// ruleHooks представляет хуки правила юнита
+type ruleHooks struct {
+	ruleType SpecialRuleType
+	hooks    SpecialRuleHooks
+}
+
+// activeHooks возвращает хуки активных правил юнита в порядке правил
+func (nusr *NavalUnitSpecialRules) activeHooks() []ruleHooks {
+	var result []ruleHooks
+	for _, rule := range nusr.Rules {
+		if !rule.IsActive {
+			continue
+		}
+		if hooks, ok := GetSpecialRuleHooks(rule.Type); ok {
+			result = append(result, ruleHooks{ruleType: rule.Type, hooks: hooks})
+		}
+	}
+	return result
+}
+
+// ApplyPreFire применяет правила юнита к стрельбе перед броском
+func (nusr *NavalUnitSpecialRules) ApplyPreFire(unit *NavalUnit, ctx CombatContext, roll *FireRoll) {
+	for _, rule := range nusr.activeHooks() {
+		if rule.hooks.PreFire != nil {
+			rule.hooks.PreFire(unit, ctx, roll)
+		}
+	}
+}
+
+// ApplyArmament устанавливает главный калибр, доступный юниту в обстановке боя.
+// Возвращает false, если ни одно правило юнита не ограничивает главный калибр
+func (nusr *NavalUnitSpecialRules) ApplyArmament(unit *NavalUnit, ctx CombatContext) bool {
+	applies, mainGuns, sternGuns := false, true, true
+	for _, rule := range nusr.activeHooks() {
+		if rule.hooks.MainGunsInRange != nil {
+			applies = true
+			mainGuns = mainGuns && rule.hooks.MainGunsInRange(unit, ctx)
+		}
+		if rule.hooks.SternGunsAvailable != nil {
+			applies = true
+			sternGuns = sternGuns && rule.hooks.SternGunsAvailable(unit, ctx)
+		}
+	}
+	if !applies {
+		return false
+	}
+
+	unit.PrimaryArmamentBow = unit.BasePrimaryArmamentBow
+	unit.PrimaryArmamentStern = unit.BasePrimaryArmamentStern
+	if !mainGuns {
+		unit.PrimaryArmamentBow = 0
+		unit.PrimaryArmamentStern = 0
+	}
+	if !sternGuns {
+		unit.PrimaryArmamentStern = 0
+	}
+	return true
+}
+
+// ApplyAfterRound проверяет правила юнита после раунда боя и применяет эффекты активированных правил.
+// Возвращает правила, активированные в этом раунде
+func (nusr *NavalUnitSpecialRules) ApplyAfterRound(unit *NavalUnit, ctx CombatContext) []SpecialRuleType {
+	var triggered []SpecialRuleType
+	for _, rule := range nusr.activeHooks() {
+		if rule.hooks.AfterRound == nil || nusr.IsRuleTriggered(rule.ruleType) {
+			continue
+		}
+		if !rule.hooks.AfterRound(unit, ctx) {
+			continue
+		}
+		nusr.SetRuleState(rule.ruleType, true, map[string]interface{}{"round": ctx.Round})
+		triggered = append(triggered, rule.ruleType)
+	}
+	nusr.ApplyTriggered(unit)
+	return triggered
+}
+
+// ApplyTriggered применяет постоянные эффекты активированных ранее правил
+func (nusr *NavalUnitSpecialRules) ApplyTriggered(unit *NavalUnit) {
+	for _, rule := range nusr.activeHooks() {
+		if rule.hooks.Triggered != nil && nusr.IsRuleTriggered(rule.ruleType) {
+			rule.hooks.Triggered(unit)
+		}
+	}
+}
@@ -1,6 +1,7 @@
 package models
 
 import (
+	"sync"
 	"time"
 )
 
@@ -40,12 +41,24 @@ type SpecialRuleState struct {
 
 // NavalUnitSpecialRules представляет специальные правила для морского юнита
 type NavalUnitSpecialRules struct {
+	GameID     string             `json:"game_id"`
 	UnitID     string             `json:"unit_id"`
 	Rules      []SpecialRule      `json:"rules"`
 	RuleStates []SpecialRuleState `json:"rule_states"`
 	UpdatedAt  time.Time          `json:"updated_at"`
 }
 
+// NewNavalUnitSpecialRules привязывает специальные правила корабля к юниту игры
+func NewNavalUnitSpecialRules(unit *NavalUnit) *NavalUnitSpecialRules {
+	return &NavalUnitSpecialRules{
+		GameID:     unit.GameID,
+		UnitID:     unit.ID,
+		Rules:      unit.SpecialRules,
+		RuleStates: make([]SpecialRuleState, 0),
+		UpdatedAt:  time.Now(),
+	}
+}
+
 // GetSpecialRule возвращает специальное правило по типу
 func (nusr *NavalUnitSpecialRules) GetSpecialRule(ruleType SpecialRuleType) *SpecialRule {
 	for _, rule := range nusr.Rules {
@@ -106,8 +119,10 @@ func (nusr *NavalUnitSpecialRules) GetRuleData(ruleType SpecialRuleType) map[str
 	return nil
 }
 
-// SpecialRuleManager управляет специальными правилами
+// SpecialRuleManager управляет специальными правилами.
+// Правила игр загружаются и читаются из разных запросов, поэтому доступ к ним защищен мьютексом
 type SpecialRuleManager struct {
+	mu    sync.RWMutex
 	rules map[string]*NavalUnitSpecialRules // unitID -> rules
 }
 
@@ -120,6 +135,8 @@ func NewSpecialRuleManager() *SpecialRuleManager {
 
 // RegisterUnitRules регистрирует специальные правила для юнита
 func (srm *SpecialRuleManager) RegisterUnitRules(unitID string, rules []SpecialRule) {
+	srm.mu.Lock()
+	defer srm.mu.Unlock()
 	srm.rules[unitID] = &NavalUnitSpecialRules{
 		UnitID:     unitID,
 		Rules:      rules,
@@ -128,89 +145,25 @@ func (srm *SpecialRuleManager) RegisterUnitRules(unitID string, rules []SpecialR
 	}
 }
 
+// SetUnitRules устанавливает сохраненные правила юнита вместе с их состоянием
+func (srm *SpecialRuleManager) SetUnitRules(rules *NavalUnitSpecialRules) {
+	srm.mu.Lock()
+	defer srm.mu.Unlock()
+	srm.rules[rules.UnitID] = rules
+}
+
 // GetUnitRules возвращает правила для юнита
 func (srm *SpecialRuleManager) GetUnitRules(unitID string) *NavalUnitSpecialRules {
+	srm.mu.RLock()
+	defer srm.mu.RUnlock()
 	return srm.rules[unitID]
 }
 
 // TriggerRule активирует правило для юнита
 func (srm *SpecialRuleManager) TriggerRule(unitID string, ruleType SpecialRuleType, data map[string]interface{}) {
-	if rules := srm.GetUnitRules(unitID); rules != nil {
+	srm.mu.Lock()
+	defer srm.mu.Unlock()
+	if rules := srm.rules[unitID]; rules != nil {
 		rules.SetRuleState(ruleType, true, data)
 	}
 }
-
-// CheckRuleConditions проверяет условия для активации правила
-func (srm *SpecialRuleManager) CheckRuleConditions(unitID string, ruleType SpecialRuleType, context map[string]interface{}) bool {
-	rules := srm.GetUnitRules(unitID)
-	if rules == nil {
-		return false
-	}
-
-	rule := rules.GetSpecialRule(ruleType)
-	if rule == nil {
-		return false
-	}
-
-	// Проверяем условия в зависимости от типа правила
-	switch ruleType {
-	case SpecialRuleUnreliableMainArmament:
-		// Ненадежное вооружение - всегда активно
-		return true
-
-	case SpecialRuleSternGunsInitialPhaseOnly:
-		// Кормовые орудия только в начальной фазе
-		phase, ok := context["battle_phase"].(string)
-		return ok && phase == "initial"
-
-	case SpecialRuleNoMainGunsExtremeRange:
-		// Нет главного калибра на экстремальной дистанции
-		rangeType, ok := context["range"].(string)
-		return ok && rangeType == "extreme"
-
-	case SpecialRuleRadarLossAfterFirstRound:
-		// Потеря радара после первого раунда
-		round, ok := context["battle_round"].(int)
-		return ok && round > 1
-
-	default:
-		return false
-	}
-}
-
-// ApplyRuleEffects применяет эффекты правила к юниту
-func (srm *SpecialRuleManager) ApplyRuleEffects(unit *NavalUnit, ruleType SpecialRuleType, context map[string]interface{}) {
-	switch ruleType {
-	case SpecialRuleUnreliableMainArmament:
-		// Ненадежное вооружение - уменьшаем эффективность стрельбы
-		// Это будет обрабатываться в логике боя
-		break
-
-	case SpecialRuleSternGunsInitialPhaseOnly:
-		// Кормовые орудия только в начальной фазе
-		phase, ok := context["battle_phase"].(string)
-		if ok && phase != "initial" {
-			unit.PrimaryArmamentStern = 0
-		} else {
-			unit.PrimaryArmamentStern = unit.BasePrimaryArmamentStern
-		}
-
-	case SpecialRuleNoMainGunsExtremeRange:
-		// Нет главного калибра на экстремальной дистанции
-		rangeType, ok := context["range"].(string)
-		if ok && rangeType == "extreme" {
-			unit.PrimaryArmamentBow = 0
-			unit.PrimaryArmamentStern = 0
-		} else {
-			unit.PrimaryArmamentBow = unit.BasePrimaryArmamentBow
-			unit.PrimaryArmamentStern = unit.BasePrimaryArmamentStern
-		}
-
-	case SpecialRuleRadarLossAfterFirstRound:
-		// Потеря радара после первого раунда
-		round, ok := context["battle_round"].(int)
-		if ok && round > 1 {
-			unit.RadarLevel = 0
-		}
-	}
-}
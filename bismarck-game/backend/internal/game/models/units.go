@@ -1,6 +1,7 @@
 package models
 
 import (
+	"strings"
 	"time"
 )
 
@@ -27,6 +28,22 @@ const (
 	UnitTypeTanker UnitType = "TK"
 )
 
+// NavalUnitTypes типы морских юнитов каталога кораблей
+var NavalUnitTypes = []UnitType{
+	UnitTypeBattleship, UnitTypeBattlecruiser, UnitTypeAircraftCarrier, UnitTypeHeavyCruiser,
+	UnitTypeLightCruiser, UnitTypeDestroyer, UnitTypeCoastGuard, UnitTypeTanker,
+}
+
+// CarriesTorpedoes проверяет, могут ли корабли этого типа нести торпеды.
+// Авианосцы, береговая охрана и танкеры торпед не имеют
+func (t UnitType) CarriesTorpedoes() bool {
+	switch t {
+	case UnitTypeAircraftCarrier, UnitTypeCoastGuard, UnitTypeTanker:
+		return false
+	}
+	return true
+}
+
 // Воздушные юниты (самолеты)
 const (
 	// B - Боевой самолет (Bomber/Fighter)
@@ -49,16 +66,22 @@ const (
 	SpeedTypeVerySlow SpeedType = "VS"
 )
 
+// SpeedTypes классы скорости кораблей
+var SpeedTypes = []SpeedType{SpeedTypeFast, SpeedTypeMedium, SpeedTypeSlow, SpeedTypeVerySlow}
+
 // UnitStatus представляет статус юнита
 type UnitStatus string
 
 const (
-	UnitStatusActive    UnitStatus = "active"
-	UnitStatusDamaged   UnitStatus = "damaged"
-	UnitStatusSunk      UnitStatus = "sunk"
-	UnitStatusRepairing UnitStatus = "repairing"
-	UnitStatusRefueling UnitStatus = "refueling"
-	UnitStatusHidden    UnitStatus = "hidden"
+	UnitStatusActive        UnitStatus = "active"
+	UnitStatusDamaged       UnitStatus = "damaged"
+	UnitStatusSunk          UnitStatus = "sunk"
+	UnitStatusRepairing     UnitStatus = "repairing"
+	UnitStatusRefueling     UnitStatus = "refueling"     // Заправка в море
+	UnitStatusInPort        UnitStatus = "in_port"       // Заправка в порту
+	UnitStatusPatrolling    UnitStatus = "patrolling"    // Морской патруль
+	UnitStatusReinforcement UnitStatus = "reinforcement" // Подкрепление на Треке хода
+	UnitStatusHidden        UnitStatus = "hidden"
 )
 
 // AirUnitStatus представляет статус воздушного юнита
@@ -109,14 +132,19 @@ type NavalUnit struct {
 	BasePrimaryArmamentStern int `json:"base_primary_armament_stern" db:"base_primary_armament_stern"` // Базовое основное вооружение (корма)
 	BaseSecondaryArmament    int `json:"base_secondary_armament" db:"base_secondary_armament"`         // Базовое вспомогательное вооружение
 
-	Torpedoes      int            `json:"torpedoes" db:"torpedoes"`
-	MaxTorpedoes   int            `json:"max_torpedoes" db:"max_torpedoes"`
-	RadarLevel     int            `json:"radar_level" db:"radar_level"` // 0, 1, 2 (RADAR I, RADAR II, RADAR II*)
-	Status         UnitStatus     `json:"status" db:"status"`
-	DetectionLevel DetectionLevel `json:"detection_level" db:"detection_level"`
-	LastKnownPos   *string        `json:"last_known_pos" db:"last_known_pos"`
-	TaskForceID    *string        `json:"task_force_id" db:"task_force_id"`
-	Damage         []Damage       `json:"damage" db:"damage"`
+	Torpedoes         int            `json:"torpedoes" db:"torpedoes"`
+	MaxTorpedoes      int            `json:"max_torpedoes" db:"max_torpedoes"`
+	RadarLevel        int            `json:"radar_level" db:"radar_level"` // 0, 1, 2 (RADAR I, RADAR II, RADAR II*)
+	Status            UnitStatus     `json:"status" db:"status"`
+	DetectionLevel    DetectionLevel `json:"detection_level" db:"detection_level"`
+	LastKnownPos      *string        `json:"last_known_pos" db:"last_known_pos"`
+	TaskForceID       *string        `json:"task_force_id" db:"task_force_id"`
+	Damage            []Damage       `json:"damage" db:"damage"`
+	SunkBy            *string        `json:"sunk_by" db:"sunk_by"`                         // ID юнита, потопившего корабль
+	EmergencyFuelTurn *int           `json:"emergency_fuel_turn" db:"emergency_fuel_turn"` // Ход, до которого нужно заправиться
+	NoMovement        int            `json:"no_movement" db:"no_movement"`                 // Маркер "Нет движения" (0-4)
+	ArrivalTurn       *int           `json:"arrival_turn" db:"arrival_turn"`               // Ход входа подкрепления в игру
+	SpecialRules      []SpecialRule  `json:"special_rules,omitempty" db:"-"`               // Правила корабля, привязываемые к юниту при создании
 
 	// Поля для тактического боя (используются только во время боя)
 	TacticalPosition    *string  `json:"tactical_position" db:"tactical_position"` // Movement Zone ID
@@ -128,6 +156,7 @@ type NavalUnit struct {
 	TargetAcquired      *string  `json:"target_acquired" db:"target_acquired"`
 	TorpedoesUsed       int      `json:"torpedoes_used" db:"torpedoes_used"`
 	MovementUsed        int      `json:"movement_used" db:"movement_used"`
+	CrewExhausted       bool     `json:"crew_exhausted" db:"crew_exhausted"` // Усталость экипажа (13.2) в текущем бою
 
 	CreatedAt time.Time `json:"created_at" db:"created_at"`
 	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
@@ -196,8 +225,9 @@ type UnitSearch struct {
 	TargetHex     string    `json:"target_hex" db:"target_hex"`
 	SearchType    string    `json:"search_type" db:"search_type"` // "air", "naval", "radar"
 	SearchFactors int       `json:"search_factors" db:"search_factors"`
-	Result        string    `json:"result" db:"result"`           // "no_contact", "contact", "detection"
-	UnitsFound    []string  `json:"units_found" db:"units_found"` // IDs найденных юнитов
+	PatrolFactors int       `json:"patrol_factors" db:"patrol_factors"` // Факторы от маркеров Патруля (входят в SearchFactors)
+	Result        string    `json:"result" db:"result"`                 // "no_contact", "contact", "detection"
+	UnitsFound    []string  `json:"units_found" db:"units_found"`       // IDs найденных юнитов
 	Turn          int       `json:"turn" db:"turn"`
 	Phase         GamePhase `json:"phase" db:"phase"`
 	CreatedAt     time.Time `json:"created_at" db:"created_at"`
@@ -210,14 +240,170 @@ func (u *NavalUnit) IsAlive() bool {
 	return u.Status != UnitStatusSunk && u.CurrentHull > 0
 }
 
+// IsBismarck проверяет, является ли юнит линкором "Бисмарк"
+func (u *NavalUnit) IsBismarck() bool {
+	return strings.EqualFold(u.Name, BismarckName)
+}
+
+// IsCapitalShip проверяет, является ли юнит капитальным кораблем (BB/BC/CV)
+func (u *NavalUnit) IsCapitalShip() bool {
+	return u.Type == UnitTypeBattleship || u.Type == UnitTypeBattlecruiser || u.Type == UnitTypeAircraftCarrier
+}
+
+// GetHullHits возвращает количество попаданий в корпус
+func (u *NavalUnit) GetHullHits() int {
+	hits := u.HullBoxes - u.CurrentHull
+	if hits < 0 {
+		hits = 0
+	}
+	return hits
+}
+
+// IsUndamaged проверяет, не получал ли юнит повреждений
+func (u *NavalUnit) IsUndamaged() bool {
+	return u.GetHullHits() == 0 && len(u.Damage) == 0
+}
+
+// EmergencyFuelTurns количество ходов на аварийном топливе до удаления корабля
+const EmergencyFuelTurns = 10
+
+// UsesFuel проверяет, расходует ли юнит топливо (медленные корабли топливо не используют)
+func (u *NavalUnit) UsesFuel() bool {
+	return u.SpeedRating != SpeedTypeSlow && u.SpeedRating != SpeedTypeVerySlow
+}
+
+// IsOnEmergencyFuel проверяет, находится ли юнит на аварийном топливе
+func (u *NavalUnit) IsOnEmergencyFuel() bool {
+	return u.EmergencyFuelTurn != nil
+}
+
+// StartEmergencyFuel помечает юнит маркером Аварийное топливо, если топливо закончилось
+func (u *NavalUnit) StartEmergencyFuel(turn int) {
+	if u.Fuel > 0 || !u.UsesFuel() || u.EmergencyFuelTurn != nil {
+		return
+	}
+	deadline := turn + EmergencyFuelTurns
+	u.EmergencyFuelTurn = &deadline
+}
+
+// IsEmergencyFuelExpired проверяет, истек ли срок аварийного топлива
+func (u *NavalUnit) IsEmergencyFuelExpired(turn int) bool {
+	return u.EmergencyFuelTurn != nil && turn >= *u.EmergencyFuelTurn
+}
+
 // CanMove проверяет, может ли юнит двигаться
 func (u *NavalUnit) CanMove() bool {
-	return u.IsAlive() && u.Fuel > 0 && u.Status != UnitStatusRepairing
+	return u.IsOnMap() && u.Fuel > 0 && !u.HasMovementMarker() && u.NoMovement == 0
 }
 
 // CanSearch проверяет, может ли юнит искать
 func (u *NavalUnit) CanSearch() bool {
-	return u.IsAlive() // Все корабли могут искать
+	// Юнит, выполняющий ремонт или заправку, не вносит свои факторы поиска
+	return u.IsOnMap() && !u.IsRepairingOrRefueling()
+}
+
+// IsOnMap проверяет, находится ли живой юнит на карте (а не на Треке хода)
+func (u *NavalUnit) IsOnMap() bool {
+	return u.IsAlive() && u.Status != UnitStatusReinforcement
+}
+
+// IsReinforcement проверяет, ожидает ли юнит входа в игру
+func (u *NavalUnit) IsReinforcement() bool {
+	return u.Status == UnitStatusReinforcement
+}
+
+// IsReinforcementDue проверяет, должно ли подкрепление войти в игру к указанному ходу
+func (u *NavalUnit) IsReinforcementDue(turn int) bool {
+	return u.IsReinforcement() && u.ArrivalTurn != nil && *u.ArrivalTurn <= turn
+}
+
+// PlaceReinforcement размещает подкрепление на карте
+func (u *NavalUnit) PlaceReinforcement() {
+	u.Status = UnitStatusActive
+}
+
+// Маркеры "Нет движения" для медленных кораблей
+const (
+	NoMovementSlow     = 2 // Медленный корабль двигается на один гекс каждые два хода
+	NoMovementVerySlow = 4 // Очень медленный корабль двигается на один гекс каждые четыре хода
+)
+
+// MarkNoMovementAfterMove отмечает медленный корабль маркером "Нет движения" после движения
+func (u *NavalUnit) MarkNoMovementAfterMove() {
+	u.MarkNoMovementForSpeed(u.SpeedRating)
+}
+
+// MarkNoMovementForSpeed отмечает корабль маркером "Нет движения" для класса скорости,
+// с которым он двигался (в ТФ - класс самого медленного корабля)
+func (u *NavalUnit) MarkNoMovementForSpeed(rating SpeedType) {
+	switch rating {
+	case SpeedTypeSlow:
+		u.NoMovement = NoMovementSlow
+	case SpeedTypeVerySlow:
+		u.NoMovement = NoMovementVerySlow
+	}
+}
+
+// AdvanceNoMovement переворачивает маркер "Нет движения" (4 -> 3 -> 2 -> 1 -> убрать).
+// Возвращает true, если маркер изменился
+func (u *NavalUnit) AdvanceNoMovement() bool {
+	if u.NoMovement <= 0 {
+		return false
+	}
+	u.NoMovement--
+	return true
+}
+
+// IsRepairingOrRefueling проверяет, выполняет ли юнит ремонт в море или заправку
+func (u *NavalUnit) IsRepairingOrRefueling() bool {
+	switch u.Status {
+	case UnitStatusRepairing, UnitStatusRefueling, UnitStatusInPort:
+		return true
+	}
+	return false
+}
+
+// HasMovementMarker проверяет, отмечен ли юнит маркером Фазы движения
+// (Ремонт в море, Заправка в море, В порту или Морской патруль)
+func (u *NavalUnit) HasMovementMarker() bool {
+	return u.IsRepairingOrRefueling() || u.Status == UnitStatusPatrolling
+}
+
+// MovementMarker возвращает тип маркера Фазы движения, которым отмечен юнит
+func (u *NavalUnit) MovementMarker() (MarkerType, bool) {
+	switch u.Status {
+	case UnitStatusPatrolling:
+		return MarkerPatrol, true
+	case UnitStatusInPort:
+		return MarkerInPort, true
+	case UnitStatusRefueling:
+		return MarkerRefuelAtSea, true
+	case UnitStatusRepairing:
+		return MarkerRepairAtSea, true
+	}
+	return "", false
+}
+
+// IsPatrolling проверяет, отмечен ли юнит маркером Морского патруля
+func (u *NavalUnit) IsPatrolling() bool {
+	return u.Status == UnitStatusPatrolling
+}
+
+// CanPatrol проверяет, может ли юнит получить маркер Морского патруля
+func (u *NavalUnit) CanPatrol() bool {
+	return u.IsOnMap() && !u.HasMovementMarker()
+}
+
+// ClearMovementMarker убирает маркеры Ремонт в море, Заправка в море, В порту
+// и Морской патруль (Фаза администрирования)
+func (u *NavalUnit) ClearMovementMarker() {
+	if !u.HasMovementMarker() {
+		return
+	}
+	u.Status = UnitStatusActive
+	if u.CurrentHull < u.HullBoxes/2 {
+		u.Status = UnitStatusDamaged
+	}
 }
 
 // CanFire проверяет, может ли юнит стрелять
@@ -297,6 +483,217 @@ func (u *NavalUnit) RepairDamage(damageIndex int) bool {
 	return true
 }
 
+// DamageTypeRudder тип повреждения руля
+const DamageTypeRudder = "rudder"
+
+// RepairAtSeaResult представляет результат попытки ремонта в море
+type RepairAtSeaResult struct {
+	UnitID          string `json:"unit_id"`
+	Roll            int    `json:"roll"`
+	Modifier        int    `json:"modifier"`
+	ModifiedRoll    int    `json:"modified_roll"`
+	EvasionRestored int    `json:"evasion_restored"`
+	RudderRepaired  bool   `json:"rudder_repaired"`
+	Success         bool   `json:"success"`
+	Turn            int    `json:"turn"`
+}
+
+// RepairAtSeaModifier возвращает модификатор броска ремонта в море по Треку погоды
+func RepairAtSeaModifier(weather int) int {
+	switch {
+	case weather >= 7:
+		return 2
+	case weather >= 4:
+		return 1
+	}
+	return 0
+}
+
+// HasRudderDamage проверяет, поврежден ли руль корабля
+func (u *NavalUnit) HasRudderDamage() bool {
+	for _, damage := range u.Damage {
+		if damage.Type == DamageTypeRudder {
+			return true
+		}
+	}
+	return false
+}
+
+// GetEvasionLoss возвращает сумму Эффектов уклонения корабля
+func (u *NavalUnit) GetEvasionLoss() int {
+	loss := 0
+	for _, effect := range u.EvasionEffects {
+		loss += effect
+	}
+	return loss
+}
+
+// IsRepairingAtSea проверяет, отмечен ли корабль маркером Ремонт в море
+func (u *NavalUnit) IsRepairingAtSea() bool {
+	return u.Status == UnitStatusRepairing
+}
+
+// CanRepairAtSea проверяет, может ли корабль попытаться провести ремонт в море
+func (u *NavalUnit) CanRepairAtSea() bool {
+	if !u.IsOnMap() || u.HasMovementMarker() {
+		return false
+	}
+	return u.HasRudderDamage() || u.GetEvasionLoss() > 0
+}
+
+// ResolveRepairAtSea выполняет попытку ремонта в море по Таблице ремонта в море.
+// Корабль отмечается маркером Ремонт в море до Фазы администрирования
+func (u *NavalUnit) ResolveRepairAtSea(roll int, weather int, turn int) RepairAtSeaResult {
+	result := RepairAtSeaResult{
+		UnitID:   u.ID,
+		Roll:     roll,
+		Modifier: RepairAtSeaModifier(weather),
+		Turn:     turn,
+	}
+	result.ModifiedRoll = roll + result.Modifier
+
+	u.Status = UnitStatusRepairing
+
+	switch result.ModifiedRoll {
+	case 0:
+		// Повреждение руля ремонтируется в первую очередь
+		if u.HasRudderDamage() {
+			u.repairRudder()
+			result.RudderRepaired = true
+		} else {
+			result.EvasionRestored = u.RestoreEvasion(5)
+		}
+	case 1:
+		result.EvasionRestored = u.RestoreEvasion(3)
+	case 2:
+		result.EvasionRestored = u.RestoreEvasion(2)
+	}
+	result.Success = result.RudderRepaired || result.EvasionRestored > 0
+
+	return result
+}
+
+// RestoreEvasion восстанавливает факторы уклонения, уменьшая Эффекты уклонения.
+// Возвращает количество восстановленных факторов
+func (u *NavalUnit) RestoreEvasion(points int) int {
+	restored := 0
+	for i := len(u.EvasionEffects) - 1; i >= 0 && points > 0; i-- {
+		repair := u.EvasionEffects[i]
+		if repair > points {
+			repair = points
+		}
+		u.EvasionEffects[i] -= repair
+		points -= repair
+		restored += repair
+	}
+
+	// Полностью отремонтированные эффекты возвращаются в мешочек
+	effects := u.EvasionEffects[:0]
+	for _, effect := range u.EvasionEffects {
+		if effect > 0 {
+			effects = append(effects, effect)
+		}
+	}
+	u.EvasionEffects = effects
+
+	return restored
+}
+
+// Заправка кораблей (7.4, 7.5)
+const (
+	RefuelFuelPoints         = 4 // FP за заправку в порту или в море
+	RefuelFuelPointsGermanDD = 2 // FP за заправку в море немецких эсминцев
+)
+
+// Модификаторы воздушной атаки против заправляющихся кораблей
+const (
+	AirAttackDRMRefuelAtSea = -1 // Против юнита с поврежденным рулем или заправляющегося в море
+	AirAttackDRMInPort      = -3 // Против юнита, заправляющегося в порту
+)
+
+// CanRefuel проверяет, может ли корабль заправиться в этот ход
+func (u *NavalUnit) CanRefuel() bool {
+	return u.IsOnMap() && u.UsesFuel() && !u.HasMovementMarker()
+}
+
+// IsTanker проверяет, является ли юнит танкером
+func (u *NavalUnit) IsTanker() bool {
+	return u.Type == UnitTypeTanker
+}
+
+// RefuelInPort заправляет корабль в порту (+4 FP) и пополняет торпеды.
+// Возвращает количество полученных FP
+func (u *NavalUnit) RefuelInPort() int {
+	u.Status = UnitStatusInPort
+	u.Torpedoes = u.MaxTorpedoes
+	return u.addFuel(RefuelFuelPoints)
+}
+
+// RefuelAtSea заправляет корабль от танкера (+4 FP, немецкие эсминцы +2 FP)
+// и отмечает оба юнита маркером Заправка в море. Торпеды в море не пополняются (7.4).
+// Возвращает количество полученных FP
+func (u *NavalUnit) RefuelAtSea(tanker *NavalUnit) int {
+	points := RefuelFuelPoints
+	if u.Type == UnitTypeDestroyer {
+		points = RefuelFuelPointsGermanDD
+	}
+
+	u.Status = UnitStatusRefueling
+	tanker.Status = UnitStatusRefueling
+	return u.addFuel(points)
+}
+
+// GetAirAttackDRM возвращает модификатор воздушной атаки против корабля
+func (u *NavalUnit) GetAirAttackDRM() int {
+	switch {
+	case u.Status == UnitStatusInPort:
+		return AirAttackDRMInPort
+	case u.Status == UnitStatusRefueling, u.HasRudderDamage():
+		return AirAttackDRMRefuelAtSea
+	}
+	return 0
+}
+
+// addFuel добавляет топливо, не превышая лимит, и снимает маркер Аварийное топливо
+func (u *NavalUnit) addFuel(points int) int {
+	before := u.Fuel
+	u.Fuel += points
+	if u.Fuel > u.MaxFuel {
+		u.Fuel = u.MaxFuel
+	}
+	if u.Fuel > 0 {
+		u.EmergencyFuelTurn = nil
+	}
+	return u.Fuel - before
+}
+
+// PatrolSearchFactors факторы поиска за маркер Морского патруля
+const PatrolSearchFactors = 3
+
+// CountPatrolMarkers возвращает количество маркеров Морского патруля стороны в гексе.
+// Оперативное соединение получает только один маркер
+func CountPatrolMarkers(markers []Marker, side PlayerSide, hex string) int {
+	count := 0
+	for i := range markers {
+		marker := &markers[i]
+		if marker.Type == MarkerPatrol && marker.Owner == side && marker.Hex == hex {
+			count++
+		}
+	}
+	return count
+}
+
+// repairRudder убирает повреждение руля
+func (u *NavalUnit) repairRudder() {
+	damage := u.Damage[:0]
+	for _, d := range u.Damage {
+		if d.Type != DamageTypeRudder {
+			damage = append(damage, d)
+		}
+	}
+	u.Damage = damage
+}
+
 // Методы для AirUnit
 
 // IsAlive проверяет, жив ли воздушный юнит
@@ -309,6 +706,22 @@ func (u *AirUnit) CanSearch() bool {
 	return u.IsAlive() // Все самолеты могут искать
 }
 
+// AdvanceRefitCycle выполняет шаг B Фазы администрирования: юнит из Ремонта
+// возвращается на авиабазу/авианосец, юнит из Посадки переходит в Ремонт.
+// Возвращает true, если статус изменился
+func (u *AirUnit) AdvanceRefitCycle() bool {
+	switch u.Status {
+	case AirUnitStatusRefit:
+		u.Status = AirUnitStatusOperational
+		u.Position = u.BasePosition
+		return true
+	case AirUnitStatusLanding:
+		u.Status = AirUnitStatusRefit
+		return true
+	}
+	return false
+}
+
 // GetRange возвращает дальность полета
 func (u *AirUnit) GetRange() int {
 	return u.Endurance * u.MaxSpeed
@@ -348,19 +761,86 @@ func (tf *TaskForce) IsEmpty() bool {
 	return len(tf.Units) == 0
 }
 
+// HasUnit проверяет, входит ли юнит в соединение
+func (tf *TaskForce) HasUnit(unitID string) bool {
+	for _, id := range tf.Units {
+		if id == unitID {
+			return true
+		}
+	}
+	return false
+}
+
+// speedRatingOrder упорядочивает классы скорости от самого медленного
+var speedRatingOrder = map[SpeedType]int{
+	SpeedTypeVerySlow: 0,
+	SpeedTypeSlow:     1,
+	SpeedTypeMedium:   2,
+	SpeedTypeFast:     3,
+}
+
+// SlowestSpeedRating возвращает класс скорости самого медленного корабля.
+// ТФ двигается с классом скорости самого медленного корабля (7.2)
+func SlowestSpeedRating(units []NavalUnit) SpeedType {
+	slowest := SpeedTypeFast
+	for _, unit := range units {
+		if rank, ok := speedRatingOrder[unit.SpeedRating]; ok && rank < speedRatingOrder[slowest] {
+			slowest = unit.SpeedRating
+		}
+	}
+	return slowest
+}
+
+// detectionLevelOrder упорядочивает уровни обнаружения
+var detectionLevelOrder = map[DetectionLevel]int{
+	DetectionLevelNone:     0,
+	DetectionLevelLost:     0,
+	DetectionLevelSighted:  1,
+	DetectionLevelShadowed: 2,
+}
+
+// TaskForceDetectionLevel возвращает уровень обнаружения соединения -
+// наивысший среди его кораблей
+func TaskForceDetectionLevel(units []NavalUnit) DetectionLevel {
+	level := DetectionLevelNone
+	for _, unit := range units {
+		if detectionLevelOrder[unit.DetectionLevel] > detectionLevelOrder[level] {
+			level = unit.DetectionLevel
+		}
+	}
+	return level
+}
+
+// IsDetected проверяет, что противник сейчас видит корабль: он обнаружен или преследуем
+func (u *NavalUnit) IsDetected() bool {
+	return u.DetectionLevel == DetectionLevelSighted || u.DetectionLevel == DetectionLevelShadowed
+}
+
+// InheritDetection переносит на корабль уровень обнаружения соединения.
+// Корабли, отделившиеся от обнаруженной ТФ, остаются обнаруженными
+func (u *NavalUnit) InheritDetection(level DetectionLevel) {
+	if detectionLevelOrder[level] <= detectionLevelOrder[u.DetectionLevel] {
+		return
+	}
+	u.DetectionLevel = level
+	position := u.Position
+	u.LastKnownPos = &position
+}
+
 // Методы для тактического боя NavalUnit
 
-// EnterTacticalCombat подготавливает юнит для тактического боя
+// EnterTacticalCombat подготавливает юнит для тактического боя.
+// Эффекты уклонения сохраняются между боями до ремонта
 func (u *NavalUnit) EnterTacticalCombat(position string, facing string) {
 	u.TacticalPosition = &position
 	u.TacticalFacing = &facing
 	u.TacticalSpeed = &u.Evasion
-	u.EvasionEffects = []int{}
 	u.TacticalDamageTaken = []Damage{}
 	u.HasFired = false
 	u.TargetAcquired = nil
 	u.TorpedoesUsed = 0
 	u.MovementUsed = 0
+	u.CrewExhausted = false
 }
 
 // ExitTacticalCombat завершает тактический бой
@@ -368,12 +848,12 @@ func (u *NavalUnit) ExitTacticalCombat() {
 	u.TacticalPosition = nil
 	u.TacticalFacing = nil
 	u.TacticalSpeed = nil
-	u.EvasionEffects = []int{}
 	u.TacticalDamageTaken = []Damage{}
 	u.HasFired = false
 	u.TargetAcquired = nil
 	u.TorpedoesUsed = 0
 	u.MovementUsed = 0
+	u.CrewExhausted = false
 }
 
 // IsInTacticalCombat проверяет, участвует ли юнит в тактическом бою
@@ -383,9 +863,9 @@ func (u *NavalUnit) IsInTacticalCombat() bool {
 
 // GetTacticalEvasion возвращает эффективную уклоняемость в тактическом бою
 func (u *NavalUnit) GetTacticalEvasion() int {
-	evasion := u.Evasion
-	for _, effect := range u.EvasionEffects {
-		evasion -= effect
+	evasion := u.Evasion - u.GetEvasionLoss()
+	if u.CrewExhausted {
+		evasion -= CrewExhaustionEvasionLoss
 	}
 	if evasion < 0 {
 		evasion = 0
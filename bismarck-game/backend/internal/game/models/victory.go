@@ -0,0 +1,156 @@
+package models
+
+import (
+	"time"
+)
+
+// Допустимые значения маркеров VP конвоя
+const (
+	MinConvoyMarkerVP = 0.5
+	MaxConvoyMarkerVP = 2.0
+)
+
+// ConvoyVPMarker представляет секретный маркер VP конвоя
+type ConvoyVPMarker struct {
+	Value     float64   `json:"value"`      // Значение VP на маркере (от 0.5 до 2)
+	ConvoyID  string    `json:"convoy_id"`  // Идентификатор потопленного конвоя
+	TurnDrawn int       `json:"turn_drawn"` // Ход, когда маркер был вытянут
+	DrawnAt   time.Time `json:"drawn_at"`   // Время вытягивания
+}
+
+// ConvoyVPBox представляет секретную коробку VP конвоя немецкого игрока
+// вместе с пулом еще не вытянутых маркеров
+type ConvoyVPBox struct {
+	GameID        string           `json:"game_id" db:"game_id"`
+	Pool          []float64        `json:"pool" db:"pool"`                     // Невытянутые маркеры (значения VP)
+	Markers       []ConvoyVPMarker `json:"markers" db:"markers"`               // Вытянутые маркеры
+	MerchantsSunk int              `json:"merchants_sunk" db:"merchants_sunk"` // Потопленные одиночные торговые суда
+	MerchantValue float64          `json:"merchant_value" db:"merchant_value"` // VP за одиночное торговое судно
+	CreatedAt     time.Time        `json:"created_at" db:"created_at"`
+	UpdatedAt     time.Time        `json:"updated_at" db:"updated_at"`
+}
+
+// ConvoyVP возвращает VP за потопленные конвои
+func (b *ConvoyVPBox) ConvoyVP() float64 {
+	total := 0.0
+	for _, marker := range b.Markers {
+		total += marker.Value
+	}
+	return total
+}
+
+// MerchantVP возвращает VP за потопленные одиночные торговые суда
+func (b *ConvoyVPBox) MerchantVP() float64 {
+	return float64(b.MerchantsSunk) * b.MerchantValue
+}
+
+// TotalVP возвращает общее количество VP в коробке конвоя
+func (b *ConvoyVPBox) TotalVP() float64 {
+	return b.ConvoyVP() + b.MerchantVP()
+}
+
+// ConvoyVPSummary представляет сводку коробки VP конвоя, безопасную для немецкого игрока
+type ConvoyVPSummary struct {
+	Markers       []ConvoyVPMarker `json:"markers"`
+	MerchantsSunk int              `json:"merchants_sunk"`
+	ConvoyVP      float64          `json:"convoy_vp"`
+	MerchantVP    float64          `json:"merchant_vp"`
+	TotalVP       float64          `json:"total_vp"`
+	PoolRemaining int              `json:"pool_remaining"`
+}
+
+// Summary возвращает сводку коробки VP конвоя
+func (b *ConvoyVPBox) Summary() ConvoyVPSummary {
+	return ConvoyVPSummary{
+		Markers:       b.Markers,
+		MerchantsSunk: b.MerchantsSunk,
+		ConvoyVP:      b.ConvoyVP(),
+		MerchantVP:    b.MerchantVP(),
+		TotalVP:       b.TotalVP(),
+		PoolRemaining: len(b.Pool),
+	}
+}
+
+// GetDefaultConvoyMarkerPool возвращает состав пула маркеров VP конвоя по умолчанию
+func GetDefaultConvoyMarkerPool() []float64 {
+	return []float64{0.5, 0.5, 0.5, 1, 1, 1, 1, 1.5, 1.5, 2}
+}
+
+// BismarckName название линкора "Бисмарк" в конфигурации кораблей
+const BismarckName = "BISMARCK"
+
+// StrategicVictoryTarget корабль, который должен быть среди двух потопленных британских
+// капитальных кораблей для Стратегической победы Германии
+const StrategicVictoryTarget = "KING GEORGE V"
+
+// GameEndReason представляет условие окончания игры
+type GameEndReason string
+
+const (
+	// GameEndBismarckSunk - "Бисмарк" потоплен
+	GameEndBismarckSunk GameEndReason = "bismarck_sunk"
+	// GameEndBismarckFrance - "Бисмарк" вошел в порт во Франции
+	GameEndBismarckFrance GameEndReason = "bismarck_france"
+	// GameEndBismarckNorway - "Бисмарк" вошел в порт в Норвегии
+	GameEndBismarckNorway GameEndReason = "bismarck_norway"
+	// GameEndBismarckNoFuel - у "Бисмарка" закончилось аварийное топливо
+	GameEndBismarckNoFuel GameEndReason = "bismarck_no_fuel"
+	// GameEndTurnTrack - разыгран последний ход по Треку ходов
+	GameEndTurnTrack GameEndReason = "turn_track_end"
+)
+
+// VictoryLineCategory представляет категорию строки подсчета очков
+type VictoryLineCategory string
+
+const (
+	VictoryLineShipSunk     VictoryLineCategory = "ship_sunk"
+	VictoryLineShipDamaged  VictoryLineCategory = "ship_damaged"
+	VictoryLineConvoy       VictoryLineCategory = "convoy"
+	VictoryLineMerchant     VictoryLineCategory = "merchant"
+	VictoryLineEndCondition VictoryLineCategory = "end_condition"
+	VictoryLineOptionalUnit VictoryLineCategory = "optional_unit"
+)
+
+// VictoryLine представляет одну строку подсчета очков победы (с точки зрения немецкого игрока)
+type VictoryLine struct {
+	Category    VictoryLineCategory `json:"category"`
+	Description string              `json:"description"`
+	UnitID      string              `json:"unit_id,omitempty"`
+	VP          float64             `json:"vp"`
+}
+
+// VictoryBreakdown представляет полный итоговый подсчет очков победы
+type VictoryBreakdown struct {
+	GameID                  string           `json:"game_id"`
+	EndReason               GameEndReason    `json:"end_reason"`
+	Lines                   []VictoryLine    `json:"lines"`
+	ShipVP                  float64          `json:"ship_vp"`
+	ConvoyVP                float64          `json:"convoy_vp"`
+	MerchantVP              float64          `json:"merchant_vp"`
+	EndConditionVP          float64          `json:"end_condition_vp"`
+	OptionalUnitVP          float64          `json:"optional_unit_vp"`
+	TotalVP                 float64          `json:"total_vp"` // Итог немецкого игрока
+	BritishCapitalShipsSunk []string         `json:"british_capital_ships_sunk"`
+	ConvoyBox               *ConvoyVPSummary `json:"convoy_box,omitempty"` // Раскрывается только в итоговом подсчете
+	WinnerSide              PlayerSide       `json:"winner_side"`
+	VictoryType             VictoryType      `json:"victory_type"`
+	CalculatedAt            time.Time        `json:"calculated_at"`
+}
+
+// AddLine добавляет строку подсчета и обновляет итоги
+func (b *VictoryBreakdown) AddLine(line VictoryLine) {
+	b.Lines = append(b.Lines, line)
+	switch line.Category {
+	case VictoryLineShipSunk, VictoryLineShipDamaged:
+		b.ShipVP += line.VP
+	case VictoryLineConvoy:
+		b.ConvoyVP += line.VP
+	case VictoryLineMerchant:
+		b.MerchantVP += line.VP
+	case VictoryLineEndCondition:
+		b.EndConditionVP += line.VP
+	case VictoryLineOptionalUnit:
+		b.OptionalUnitVP += line.VP
+	}
+	b.TotalVP += line.VP
+}
@@ -0,0 +1,110 @@
+package models
+
+// NavalContact представляет то, что сторона знает о корабле противника
+type NavalContact struct {
+	UnitID         string         `json:"unit_id"`
+	Name           string         `json:"name"`
+	Type           UnitType       `json:"type"`
+	Class          string         `json:"class"`
+	DetectionLevel DetectionLevel `json:"detection_level"`
+	LastKnownPos   string         `json:"last_known_pos"` // Последняя известная позиция, а не текущая
+}
+
+// PlayerView представляет состояние игры глазами одной стороны.
+// Свои юниты видны полностью, корабли противника - только как контакты
+type PlayerView struct {
+	GameID     string           `json:"game_id"`
+	Side       PlayerSide       `json:"side"`
+	Turn       int              `json:"turn"`
+	Phase      GamePhase        `json:"phase"`
+	Weather    int              `json:"weather"`
+	NavalUnits []NavalUnit      `json:"naval_units"`
+	AirUnits   []AirUnit        `json:"air_units"`
+	TaskForces []TaskForce      `json:"task_forces"`
+	Contacts   []NavalContact   `json:"contacts"`
+	Markers    []Marker         `json:"markers"`
+	ConvoyVP   *ConvoyVPSummary `json:"convoy_vp,omitempty"` // Только немецкому игроку или после окончания игры
+}
+
+// Contact возвращает контакт с кораблем для стороны противника.
+// Корабль виден противнику только на карте и только обнаруженным или преследуемым;
+// потерянный контакт остается на последней известной позиции
+func (u *NavalUnit) Contact() (NavalContact, bool) {
+	if !u.IsOnMap() || u.LastKnownPos == nil {
+		return NavalContact{}, false
+	}
+	switch u.DetectionLevel {
+	case DetectionLevelSighted, DetectionLevelShadowed, DetectionLevelLost:
+	default:
+		return NavalContact{}, false
+	}
+
+	return NavalContact{
+		UnitID:         u.ID,
+		Name:           u.Name,
+		Type:           u.Type,
+		Class:          u.Class,
+		DetectionLevel: u.DetectionLevel,
+		LastKnownPos:   *u.LastKnownPos,
+	}, true
+}
+
+// BuildPlayerView строит состояние игры для стороны. Подкрепления противника и другие
+// юниты вне карты не попадают в представление, секретный пул VP конвоя передается
+// отдельно и добавляется только тому, кому он доступен
+func BuildPlayerView(game *Game, side PlayerSide, navalUnits []NavalUnit, airUnits []AirUnit, taskForces []TaskForce, markers []Marker, convoyVP *ConvoyVPSummary) *PlayerView {
+	view := &PlayerView{
+		GameID:     game.ID,
+		Side:       side,
+		Turn:       game.CurrentTurn,
+		Phase:      game.CurrentPhase,
+		Weather:    game.Weather,
+		NavalUnits: []NavalUnit{},
+		AirUnits:   []AirUnit{},
+		TaskForces: []TaskForce{},
+		Contacts:   []NavalContact{},
+		Markers:    []Marker{},
+	}
+
+	for _, unit := range navalUnits {
+		if game.GetOwnerSide(unit.Owner) == side {
+			view.NavalUnits = append(view.NavalUnits, unit)
+			continue
+		}
+		if contact, ok := unit.Contact(); ok {
+			view.Contacts = append(view.Contacts, contact)
+		}
+	}
+	for _, unit := range airUnits {
+		if game.GetOwnerSide(unit.Owner) == side {
+			view.AirUnits = append(view.AirUnits, unit)
+		}
+	}
+	for _, tf := range taskForces {
+		if game.GetOwnerSide(tf.Owner) == side {
+			view.TaskForces = append(view.TaskForces, tf)
+		}
+	}
+	for _, marker := range markers {
+		if marker.IsVisibleTo(side) {
+			view.Markers = append(view.Markers, marker)
+		}
+	}
+
+	if side == PlayerSideGerman || game.IsCompleted() {
+		view.ConvoyVP = convoyVP
+	}
+
+	return view
+}
+
+// ContactsAt возвращает контакты, последняя известная позиция которых - указанный гекс
+func (v *PlayerView) ContactsAt(position string) []NavalContact {
+	contacts := []NavalContact{}
+	for _, contact := range v.Contacts {
+		if contact.LastKnownPos == position {
+			contacts = append(contacts, contact)
+		}
+	}
+	return contacts
+}
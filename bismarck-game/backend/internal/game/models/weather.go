@@ -0,0 +1,83 @@
+package models
+
+// TimeOfDay представляет время суток хода
+type TimeOfDay string
+
+const (
+	TimeMorning   TimeOfDay = "morning"
+	TimeAfternoon TimeOfDay = "afternoon"
+	TimeNight     TimeOfDay = "night"
+)
+
+// Параметры Трека погоды и Трека видимости
+const (
+	TurnsPerDay      = 3  // Ходов в сутках
+	FogThreshold     = 5  // Статус погоды 5-9 - туман
+	VisibilityX      = 10 // Уровень видимости X - поиск, преследование и бой невозможны
+	NightVisibilityM = 3  // Модификатор времени суток для ночного хода
+)
+
+// TimeOfDayForTurn возвращает время суток для хода (утро, день, ночь)
+func TimeOfDayForTurn(turn int) TimeOfDay {
+	if turn < 1 {
+		return TimeMorning
+	}
+	switch (turn - 1) % TurnsPerDay {
+	case 0:
+		return TimeMorning
+	case 1:
+		return TimeAfternoon
+	default:
+		return TimeNight
+	}
+}
+
+// DayForTurn возвращает сутки Трека ходов, к которым относится ход (первые сутки - 1)
+func DayForTurn(turn int) int {
+	if turn < 1 {
+		return 1
+	}
+	return (turn-1)/TurnsPerDay + 1
+}
+
+// IsFogWeather проверяет, вызывает ли статус погоды туман
+func IsFogWeather(weather int) bool {
+	return weather >= FogThreshold
+}
+
+// VisibilityLevel возвращает Уровень видимости: статус погоды плюс модификатор времени суток
+func VisibilityLevel(weather int, turn int) int {
+	visibility := weather
+	if TimeOfDayForTurn(turn) == TimeNight {
+		visibility += NightVisibilityM
+	}
+	if visibility < 1 {
+		visibility = 1
+	}
+	if visibility > VisibilityX {
+		visibility = VisibilityX
+	}
+	return visibility
+}
+
+// GetVisibility возвращает текущий Уровень видимости игры
+func (g *Game) GetVisibility() int {
+	return VisibilityLevel(g.Weather, g.CurrentTurn)
+}
+
+// IsVisibilityX проверяет, находится ли маркер видимости в клетке X
+func (g *Game) IsVisibilityX() bool {
+	return g.GetVisibility() >= VisibilityX
+}
+
+// IsFogHex проверяет, находится ли гекс в тумане при текущей погоде.
+// Если туманные гексы не заданы в настройках, туман действует во всех гексах
+func (g *Game) IsFogHex(hex string) bool {
+	if !IsFogWeather(g.Weather) {
+		return false
+	}
+	if len(g.Settings.FogHexes) == 0 {
+		return true
+	}
+	return containsHex(g.Settings.FogHexes, hex)
+}
@@ -0,0 +1,251 @@
+package services
+
+import (
+	"database/sql"
+	"fmt"
+
+	"bismarck-game/backend/internal/game/models"
+	"bismarck-game/backend/pkg/database"
+	"bismarck-game/backend/pkg/logger"
+)
+
+// AdminPhaseService выполняет Фазу администрирования (12)
+type AdminPhaseService struct {
+	db            *database.Database
+	logger        *logger.Logger
+	unitService   *UnitService
+	markerService *MarkerService
+	intelligence  *IntelligenceService
+	exhaustion    *CrewExhaustionService
+}
+
+// NewAdminPhaseService создает новый сервис Фазы администрирования
+func NewAdminPhaseService(db *database.Database, logger *logger.Logger, unitService *UnitService, markerService *MarkerService, intelligence *IntelligenceService, exhaustion *CrewExhaustionService) *AdminPhaseService {
+	return &AdminPhaseService{
+		db:            db,
+		logger:        logger,
+		unitService:   unitService,
+		markerService: markerService,
+		intelligence:  intelligence,
+		exhaustion:    exhaustion,
+	}
+}
+
+// ProcessAdminPhase выполняет шаги A-C Фазы администрирования в рамках транзакции.
+// Перемещение маркера Трека хода (шаг D) выполняет PhaseService
+func (s *AdminPhaseService) ProcessAdminPhase(tx *sql.Tx, game *models.Game) (*models.AdminPhaseSummary, error) {
+	navalUnits, err := s.unitService.GetNavalUnitsByGameIDTx(tx, game.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get naval units: %w", err)
+	}
+	airUnits, err := s.unitService.GetAirUnitsByGameIDTx(tx, game.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get air units: %w", err)
+	}
+
+	summary := &models.AdminPhaseSummary{
+		GameID:   game.ID,
+		Turn:     game.CurrentTurn,
+		NextTurn: game.CurrentTurn + 1,
+		Events:   []models.AdminPhaseEvent{},
+	}
+
+	changed := processNavalAdmin(game, navalUnits, summary)
+
+	// Контакты, не обновленные на этом ходу, теряются
+	lastReports, err := s.intelligence.GetLastContactTurns(tx, game.ID)
+	if err != nil {
+		return nil, err
+	}
+	for _, unit := range loseStaleContacts(navalUnits, lastReports, game.CurrentTurn) {
+		changed[unit.ID] = true
+		summary.AddEvent(contactLostEvent(game, unit))
+	}
+
+	for i := range navalUnits {
+		if !changed[navalUnits[i].ID] {
+			continue
+		}
+		if err := s.unitService.UpdateNavalUnitTx(tx, &navalUnits[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	// Экипажи, участвовавшие в двух и более боях за сутки, устают на следующие сутки
+	if err := s.addCrewExhaustionEvents(game, navalUnits, summary); err != nil {
+		return nil, err
+	}
+
+	// B. Воздушные юниты: Ремонт -> авиабаза, Посадка -> Ремонт
+	for i := range airUnits {
+		unit := &airUnits[i]
+		from := unit.Status
+		if !unit.AdvanceRefitCycle() {
+			continue
+		}
+		eventType := models.AdminEventAirLanded
+		if from == models.AirUnitStatusRefit {
+			eventType = models.AdminEventAirRefitted
+		}
+		summary.AddEvent(models.AdminPhaseEvent{
+			Type:     eventType,
+			Side:     airUnitSide(game, unit),
+			UnitID:   unit.ID,
+			Position: unit.Position,
+		})
+		if err := s.unitService.UpdateAirUnitTx(tx, unit); err != nil {
+			return nil, err
+		}
+	}
+
+	// C. Игровые маркеры снимаются или переворачиваются по своему правилу
+	markers, err := s.markerService.ExpireAdminPhaseMarkers(tx, game.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to expire markers: %w", err)
+	}
+	for _, marker := range markers {
+		summary.AddEvent(markerAdminEvent(&marker))
+	}
+
+	s.logger.Info("Admin phase processed", "game_id", game.ID, "turn", game.CurrentTurn, "events", len(summary.Events))
+	return summary, nil
+}
+
+// addCrewExhaustionEvents добавляет в итог события усталости экипажей,
+// если следующий ход начинает новые сутки
+func (s *AdminPhaseService) addCrewExhaustionEvents(game *models.Game, units []models.NavalUnit, summary *models.AdminPhaseSummary) error {
+	if models.DayForTurn(summary.NextTurn) == models.DayForTurn(game.CurrentTurn) {
+		return nil
+	}
+
+	nextTurn := *game
+	nextTurn.CurrentTurn = summary.NextTurn
+	alive := make([]*models.NavalUnit, 0, len(units))
+	for i := range units {
+		if units[i].IsAlive() && units[i].IsOnMap() {
+			alive = append(alive, &units[i])
+		}
+	}
+	exhausted, err := s.exhaustion.GetExhaustedUnits(&nextTurn, alive)
+	if err != nil {
+		return err
+	}
+	for _, unit := range alive {
+		if exhausted[unit.ID] {
+			summary.AddEvent(models.AdminPhaseEvent{
+				Type:     models.AdminEventCrewExhausted,
+				Side:     unitSide(game, unit),
+				UnitID:   unit.ID,
+				UnitName: unit.Name,
+				Position: unit.Position,
+			})
+		}
+	}
+	return nil
+}
+
+// processNavalAdmin размещает подкрепления и переворачивает маркеры морских юнитов.
+// Возвращает множество измененных юнитов
+func processNavalAdmin(game *models.Game, units []models.NavalUnit, summary *models.AdminPhaseSummary) map[string]bool {
+	changed := make(map[string]bool)
+
+	for i := range units {
+		unit := &units[i]
+		side := unitSide(game, unit)
+
+		// A. Подкрепления, входящие в игру на следующем ходу
+		if unit.IsReinforcementDue(summary.NextTurn) {
+			unit.PlaceReinforcement()
+			changed[unit.ID] = true
+			summary.AddEvent(models.AdminPhaseEvent{
+				Type:     models.AdminEventReinforcement,
+				Side:     side,
+				UnitID:   unit.ID,
+				UnitName: unit.Name,
+				Position: unit.Position,
+			})
+			continue
+		}
+		if !unit.IsOnMap() {
+			continue
+		}
+
+		// Тактические бои хода завершаются
+		if unit.IsInTacticalCombat() {
+			unit.ExitTacticalCombat()
+			changed[unit.ID] = true
+		}
+
+		// C. Маркеры Патруля, Ремонта в море, Заправки в море и В порту убираются
+		if marker, ok := unit.MovementMarker(); ok {
+			unit.ClearMovementMarker()
+			changed[unit.ID] = true
+			summary.AddEvent(models.AdminPhaseEvent{
+				Type:     models.AdminEventMarkerRemoved,
+				Side:     side,
+				UnitID:   unit.ID,
+				UnitName: unit.Name,
+				Marker:   string(marker),
+			})
+		}
+
+		// Маркеры "Нет движения": 4 -> 3 -> 2 -> 1 -> убрать
+		if unit.AdvanceNoMovement() {
+			changed[unit.ID] = true
+			eventType := models.AdminEventNoMovementFlip
+			if unit.NoMovement == 0 {
+				eventType = models.AdminEventNoMovementEnd
+			}
+			summary.AddEvent(models.AdminPhaseEvent{
+				Type:     eventType,
+				Side:     side,
+				UnitID:   unit.ID,
+				UnitName: unit.Name,
+				Value:    unit.NoMovement,
+			})
+		}
+	}
+
+	return changed
+}
+
+// markerAdminEvent возвращает событие итога для снятого или перевернутого маркера
+func markerAdminEvent(marker *models.Marker) models.AdminPhaseEvent {
+	event := models.AdminPhaseEvent{
+		Type:     models.AdminEventMarkerRemoved,
+		Side:     marker.Owner,
+		Position: marker.Hex,
+		Marker:   string(marker.Type),
+	}
+	if marker.UnitID != nil {
+		event.UnitID = *marker.UnitID
+	}
+	if marker.Expiry == models.MarkerExpiryCountdown {
+		event.Value = marker.Value
+		if marker.Value > 0 {
+			event.Type = models.AdminEventNoMovementFlip
+		} else {
+			event.Type = models.AdminEventNoMovementEnd
+		}
+	}
+	return event
+}
+
+// contactLostEvent возвращает событие потери контакта для стороны, потерявшей корабль противника
+func contactLostEvent(game *models.Game, unit *models.NavalUnit) models.AdminPhaseEvent {
+	event := models.AdminPhaseEvent{
+		Type:     models.AdminEventContactLost,
+		Side:     unitSide(game, unit).Opponent(),
+		UnitID:   unit.ID,
+		UnitName: unit.Name,
+	}
+	if unit.LastKnownPos != nil {
+		event.Position = *unit.LastKnownPos
+	}
+	return event
+}
+
+// airUnitSide возвращает сторону, которой принадлежит воздушный юнит
+func airUnitSide(game *models.Game, unit *models.AirUnit) models.PlayerSide {
+	return game.GetOwnerSide(unit.Owner)
+}
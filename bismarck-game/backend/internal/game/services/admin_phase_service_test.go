@@ -0,0 +1,85 @@
+package services
+
+import (
+	"bismarck-game/backend/internal/game/models"
+	"testing"
+)
+
+func TestProcessNavalAdmin(t *testing.T) {
+	game := newVictoryTestGame()
+	game.CurrentTurn = 12
+
+	arrival := 13
+	later := 20
+	units := []models.NavalUnit{
+		{ID: "dorsetshire", Name: "DORSETSHIRE", Owner: "allied", Position: "AH13", HullBoxes: 6, CurrentHull: 6,
+			Status: models.UnitStatusReinforcement, ArrivalTurn: &arrival},
+		{ID: "ramillies", Name: "RAMILLIES", Owner: "allied", HullBoxes: 10, CurrentHull: 10,
+			Status: models.UnitStatusReinforcement, ArrivalTurn: &later},
+		{ID: "bismarck", Name: "BISMARCK", Owner: "german", HullBoxes: 12, CurrentHull: 12,
+			Status: models.UnitStatusPatrolling},
+		{ID: "tanker", Name: "WEISSENBURG", Owner: "german", HullBoxes: 1, CurrentHull: 1,
+			Status: models.UnitStatusActive, NoMovement: 1},
+		{ID: "convoy", Name: "REVENGE", Owner: "allied", HullBoxes: 10, CurrentHull: 10,
+			Status: models.UnitStatusActive, NoMovement: 4},
+	}
+
+	summary := &models.AdminPhaseSummary{GameID: game.ID, Turn: 12, NextTurn: 13}
+	changed := processNavalAdmin(game, units, summary)
+
+	if units[0].Status != models.UnitStatusActive || !changed["dorsetshire"] {
+		t.Error("Подкрепление должно быть размещено")
+	}
+	if units[1].Status != models.UnitStatusReinforcement || changed["ramillies"] {
+		t.Error("Подкрепление более позднего хода не размещается")
+	}
+	if units[2].Status != models.UnitStatusActive {
+		t.Error("Маркер Патруля должен быть убран")
+	}
+	if units[3].NoMovement != 0 || units[4].NoMovement != 3 {
+		t.Errorf("Маркеры \"Нет движения\" должны перевернуться, получено %d/%d", units[3].NoMovement, units[4].NoMovement)
+	}
+
+	t.Run("SummaryForSide", func(t *testing.T) {
+		summary.AddEvent(models.AdminPhaseEvent{Type: models.AdminEventTurnAdvanced, Value: 13})
+
+		german := summary.ForSide(models.PlayerSideGerman)
+		for _, event := range german.Events {
+			if event.Side == models.PlayerSideAllied {
+				t.Errorf("Немецкий игрок не должен видеть событие союзников %s", event.Type)
+			}
+		}
+		// Патруль, "Нет движения" танкера и Трек хода
+		if len(german.Events) != 3 {
+			t.Errorf("Ожидалось 3 события для немецкого игрока, получено %d", len(german.Events))
+		}
+	})
+
+	t.Run("TacticalCombatEnds", func(t *testing.T) {
+		hood := []models.NavalUnit{{ID: "hood", Owner: "allied", Position: "Q20", HullBoxes: 10, CurrentHull: 10,
+			Status: models.UnitStatusActive}}
+		hood[0].EnterTacticalCombat("zone-1", models.TacticalFacingClosing)
+		hood[0].ApplyCrewExhaustion()
+
+		changed := processNavalAdmin(game, hood, &models.AdminPhaseSummary{GameID: game.ID, Turn: 12, NextTurn: 13})
+		if hood[0].IsInTacticalCombat() || hood[0].CrewExhausted || !changed["hood"] {
+			t.Error("Тактический бой должен завершиться в Административной фазе")
+		}
+	})
+}
+
+func TestAirRefitCycle(t *testing.T) {
+	refit := models.AirUnit{ID: "swordfish", Position: "K20", BasePosition: "AK10", Status: models.AirUnitStatusRefit}
+	landing := models.AirUnit{ID: "catalina", Status: models.AirUnitStatusLanding}
+	operational := models.AirUnit{ID: "fulmar", Status: models.AirUnitStatusOperational}
+
+	if !refit.AdvanceRefitCycle() || refit.Status != models.AirUnitStatusOperational || refit.Position != "AK10" {
+		t.Error("Юнит из Ремонта должен вернуться на авиабазу")
+	}
+	if !landing.AdvanceRefitCycle() || landing.Status != models.AirUnitStatusRefit {
+		t.Error("Юнит из Посадки должен перейти в Ремонт")
+	}
+	if operational.AdvanceRefitCycle() {
+		t.Error("Операционный юнит не меняет статус")
+	}
+}
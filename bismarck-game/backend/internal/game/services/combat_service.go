@@ -0,0 +1,317 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+
+	"bismarck-game/backend/internal/game/models"
+	"bismarck-game/backend/pkg/database"
+	"bismarck-game/backend/pkg/logger"
+)
+
+// Ошибки боя
+var (
+	ErrNotAirAttackPhase   = errors.New("action is only allowed in the air attack phase")
+	ErrNoAirAttackTargets  = errors.New("no air attack targets selected")
+	ErrNotAirAttackTarget  = errors.New("unit is not a valid air attack target")
+	ErrNotNavalCombatPhase = errors.New("action is only allowed in the naval combat phase")
+	ErrNoNavalCombatUnits  = errors.New("no ships selected for naval combat")
+	ErrNotNavalCombatant   = errors.New("unit cannot take part in this naval combat")
+	ErrNotConvoyRaider     = errors.New("only the German player sinks convoys and merchants")
+	ErrShipAlreadyFired    = errors.New("ship has already fired this round")
+)
+
+// CombatService начинает воздушные атаки и бои кораблей
+type CombatService struct {
+	db           *database.Database
+	logger       *logger.Logger
+	unitService  *UnitService
+	exhaustion   *CrewExhaustionService
+	convoys      *ConvoyVPService
+	specialRules *SpecialRulesService
+	dice         DiceRoller
+}
+
+// NewCombatService создает новый сервис боя
+func NewCombatService(db *database.Database, logger *logger.Logger, unitService *UnitService, exhaustion *CrewExhaustionService, convoys *ConvoyVPService, specialRules *SpecialRulesService, dice DiceRoller) *CombatService {
+	return &CombatService{
+		db:           db,
+		logger:       logger,
+		unitService:  unitService,
+		exhaustion:   exhaustion,
+		convoys:      convoys,
+		specialRules: specialRules,
+		dice:         dice,
+	}
+}
+
+// BeginAirAttack проверяет цели воздушной атаки стороны в Фазе воздушных атак
+// и возвращает модификатор броска атаки против каждой цели
+func (s *CombatService) BeginAirAttack(game *models.Game, side models.PlayerSide, unitIDs []string) ([]models.AirAttackTarget, error) {
+	if game.CurrentPhase != models.PhaseAirAttack {
+		return nil, ErrNotAirAttackPhase
+	}
+	if len(unitIDs) == 0 {
+		return nil, ErrNoAirAttackTargets
+	}
+
+	targets := make([]models.AirAttackTarget, 0, len(unitIDs))
+	for _, unitID := range unitIDs {
+		unit, err := s.loadCombatUnit(game, side, unitID, ErrNotAirAttackTarget)
+		if err != nil {
+			return nil, err
+		}
+		target, err := airAttackTarget(game, side, unit)
+		if err != nil {
+			return nil, err
+		}
+		targets = append(targets, target)
+	}
+
+	// Воздушная атака считается боем для усталости экипажа
+	if err := s.recordCombat(game, unitIDs, models.CombatTypeAirAttack); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("Air attack begun", "game_id", game.ID, "side", side, "targets", len(targets))
+	return targets, nil
+}
+
+// BeginNavalCombat вводит корабли одного гекса в морской бой в Фазе морского боя, применяет
+// усталость экипажа (13.2) и специальные правила кораблей и возвращает уклоняемость,
+// модификатор стрельбы и главный калибр своих участников.
+// Участие в бою учитывается для усталости экипажа в следующие сутки
+func (s *CombatService) BeginNavalCombat(game *models.Game, side models.PlayerSide, zone string, unitIDs []string) ([]models.NavalCombatant, error) {
+	if game.CurrentPhase != models.PhaseNavalCombat {
+		return nil, ErrNotNavalCombatPhase
+	}
+	if len(unitIDs) == 0 {
+		return nil, ErrNoNavalCombatUnits
+	}
+
+	units := make([]*models.NavalUnit, 0, len(unitIDs))
+	for _, unitID := range unitIDs {
+		unit, err := s.loadCombatUnit(game, side, unitID, ErrNotNavalCombatant)
+		if err != nil {
+			return nil, err
+		}
+		units = append(units, unit)
+	}
+	if err := validateNavalCombatants(game, side, units); err != nil {
+		return nil, err
+	}
+
+	// Бой начинается с первого раунда начальной фазы
+	ctx := models.CombatContext{Phase: models.CombatPhaseInitial, Round: 1}
+	for _, unit := range units {
+		unit.EnterTacticalCombat(zone, models.TacticalFacingClosing)
+		if err := s.specialRules.ApplySpecialRulesToUnit(unit, ctx); err != nil {
+			return nil, err
+		}
+	}
+	if err := s.exhaustion.ApplyCrewExhaustion(game, units); err != nil {
+		return nil, err
+	}
+	if err := s.recordCombat(game, unitIDs, models.CombatTypeNaval, units...); err != nil {
+		return nil, err
+	}
+
+	// Уклоняемость и модификаторы кораблей противника стороне не раскрываются
+	combatants := make([]models.NavalCombatant, 0, len(units))
+	for _, unit := range units {
+		if unitSide(game, unit) == side {
+			combatants = append(combatants, unit.NavalCombatant())
+		}
+	}
+
+	s.logger.Info("Naval combat begun", "game_id", game.ID, "side", side, "hex", units[0].Position, "units", len(units))
+	return combatants, nil
+}
+
+// FireNaval готовит стрельбу своего корабля в раунде морского боя: применяет специальные
+// правила к вооружению, бросает 1d10 перед стрельбой для хуков PreFire и отмечает,
+// что корабль стрелял в этом раунде. Возвращает фактор и модификатор броска стрельбы
+func (s *CombatService) FireNaval(game *models.Game, side models.PlayerSide, unitID string, ctx models.CombatContext, mainArmament bool) (*models.FireRoll, error) {
+	if game.CurrentPhase != models.PhaseNavalCombat {
+		return nil, ErrNotNavalCombatPhase
+	}
+
+	unit, err := s.loadCombatUnit(game, side, unitID, ErrNotNavalCombatant)
+	if err != nil {
+		return nil, err
+	}
+	if unitSide(game, unit) != side || !unit.IsInTacticalCombat() {
+		return nil, fmt.Errorf("%w: %s", ErrNotNavalCombatant, unitID)
+	}
+	if unit.HasFired {
+		return nil, ErrShipAlreadyFired
+	}
+
+	if err := s.specialRules.ApplySpecialRulesToUnit(unit, ctx); err != nil {
+		return nil, err
+	}
+	roll := unit.NewFireRoll(mainArmament, s.dice.RollD10())
+	s.specialRules.PreFire(unit, ctx, &roll)
+	unit.HasFired = true
+
+	if err := s.unitService.UpdateNavalUnit(unit); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("Naval fire resolved", "game_id", game.ID, "unit_id", unit.ID, "round", ctx.Round, "factor", roll.Factor)
+	return &roll, nil
+}
+
+// EndNavalRound завершает раунд морского боя для кораблей стороны: проверяет специальные правила,
+// активируемые после раунда, и снимает отметку о стрельбе. Возвращает своих участников боя
+func (s *CombatService) EndNavalRound(game *models.Game, side models.PlayerSide, ctx models.CombatContext) ([]models.NavalCombatant, error) {
+	if game.CurrentPhase != models.PhaseNavalCombat {
+		return nil, ErrNotNavalCombatPhase
+	}
+
+	gameUnits, err := s.unitService.GetNavalUnitsByGameID(game.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get naval units: %w", err)
+	}
+	units := make([]*models.NavalUnit, 0, len(gameUnits))
+	for i := range gameUnits {
+		if unitSide(game, &gameUnits[i]) == side && gameUnits[i].IsInTacticalCombat() {
+			units = append(units, &gameUnits[i])
+		}
+	}
+	if len(units) == 0 {
+		return nil, ErrNoNavalCombatUnits
+	}
+
+	if err := s.specialRules.EndRound(units, ctx); err != nil {
+		return nil, err
+	}
+
+	tx, err := s.db.BeginTx()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	combatants := make([]models.NavalCombatant, 0, len(units))
+	for _, unit := range units {
+		unit.HasFired = false
+		if err := s.unitService.UpdateNavalUnitTx(tx, unit); err != nil {
+			return nil, err
+		}
+		combatants = append(combatants, unit.NavalCombatant())
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit naval round: %w", err)
+	}
+
+	s.logger.Info("Naval combat round ended", "game_id", game.ID, "side", side, "round", ctx.Round, "units", len(units))
+	return combatants, nil
+}
+
+// SinkConvoy записывает потопление конвоя немецким игроком в Фазе морского боя
+// и вытягивает маркер VP в его секретную коробку конвоя
+func (s *CombatService) SinkConvoy(game *models.Game, side models.PlayerSide, convoyID string) (*models.ConvoyVPMarker, error) {
+	if err := checkConvoyAttack(game, side); err != nil {
+		return nil, err
+	}
+	return s.convoys.RecordConvoySunk(game, convoyID)
+}
+
+// SinkMerchant записывает потопление одиночного торгового судна немецким игроком в Фазе морского боя
+func (s *CombatService) SinkMerchant(game *models.Game, side models.PlayerSide) error {
+	if err := checkConvoyAttack(game, side); err != nil {
+		return err
+	}
+	return s.convoys.RecordMerchantSunk(game)
+}
+
+// checkConvoyAttack проверяет, что конвои и торговые суда топит немецкий игрок в Фазе морского боя
+func checkConvoyAttack(game *models.Game, side models.PlayerSide) error {
+	if game.CurrentPhase != models.PhaseNavalCombat {
+		return ErrNotNavalCombatPhase
+	}
+	if side != models.PlayerSideGerman {
+		return ErrNotConvoyRaider
+	}
+	return nil
+}
+
+// loadCombatUnit загружает корабль, выбранный стороной для боя. Корабль противника доступен,
+// только если сторона его обнаружила: несуществующий, чужой и необнаруженный корабль
+// дают одну и ту же ошибку invalid, чтобы ответ не раскрывал скрытые юниты
+func (s *CombatService) loadCombatUnit(game *models.Game, side models.PlayerSide, unitID string, invalid error) (*models.NavalUnit, error) {
+	unit, err := s.unitService.GetNavalUnitByID(unitID)
+	if errors.Is(err, ErrUnitNotFound) {
+		return nil, fmt.Errorf("%w: %s", invalid, unitID)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !canTargetInCombat(game, side, unit) {
+		return nil, fmt.Errorf("%w: %s", invalid, unitID)
+	}
+	return unit, nil
+}
+
+// canTargetInCombat проверяет, может ли сторона выбрать корабль для боя:
+// свой корабль этой игры или обнаруженный ею корабль противника
+func canTargetInCombat(game *models.Game, side models.PlayerSide, unit *models.NavalUnit) bool {
+	if unit.GameID != game.ID {
+		return false
+	}
+	return unitSide(game, unit) == side || unit.IsDetected()
+}
+
+// recordCombat учитывает участие кораблей в бою и сохраняет их тактическое состояние
+// в одной транзакции
+func (s *CombatService) recordCombat(game *models.Game, unitIDs []string, combatType models.CombatType, units ...*models.NavalUnit) error {
+	tx, err := s.db.BeginTx()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := s.exhaustion.RecordCombat(tx, game, unitIDs, combatType); err != nil {
+		return err
+	}
+	for _, unit := range units {
+		if err := s.unitService.UpdateNavalUnitTx(tx, unit); err != nil {
+			return err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit combat participation: %w", err)
+	}
+	return nil
+}
+
+// validateNavalCombatants проверяет, что корабли находятся в одном гексе карты,
+// не ведут другой бой и среди них есть корабль стороны, начинающей бой
+func validateNavalCombatants(game *models.Game, side models.PlayerSide, units []*models.NavalUnit) error {
+	hasOwn := false
+	for _, unit := range units {
+		if unit.GameID != game.ID {
+			return fmt.Errorf("%w: %s", ErrNotNavalCombatant, unit.ID)
+		}
+		if !unit.IsAlive() || !unit.IsOnMap() || unit.IsInTacticalCombat() || unit.Position != units[0].Position {
+			return fmt.Errorf("%w: %s", ErrNotNavalCombatant, unit.ID)
+		}
+		if unitSide(game, unit) == side {
+			hasOwn = true
+		}
+	}
+	if !hasOwn {
+		return fmt.Errorf("%w: no ships of side %s", ErrNotNavalCombatant, side)
+	}
+	return nil
+}
+
+// airAttackTarget проверяет, что сторона может атаковать корабль с воздуха,
+// и возвращает цель с модификатором броска атаки
+func airAttackTarget(game *models.Game, side models.PlayerSide, unit *models.NavalUnit) (models.AirAttackTarget, error) {
+	if unit.GameID != game.ID || unitSide(game, unit) == side || !unit.IsAttackableFromAir() {
+		return models.AirAttackTarget{}, fmt.Errorf("%w: %s", ErrNotAirAttackTarget, unit.ID)
+	}
+	return unit.AirAttackTarget(), nil
+}
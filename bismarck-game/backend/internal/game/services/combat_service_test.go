@@ -0,0 +1,164 @@
+package services
+
+import (
+	"bismarck-game/backend/internal/game/models"
+	"errors"
+	"testing"
+)
+
+func TestAirAttackTarget(t *testing.T) {
+	game := newVictoryTestGame()
+	newTarget := func() *models.NavalUnit {
+		return &models.NavalUnit{
+			ID: "bismarck", GameID: "game", Name: "BISMARCK", Owner: "german", Position: "Q20",
+			HullBoxes: 12, CurrentHull: 12, Status: models.UnitStatusActive,
+			DetectionLevel: models.DetectionLevelSighted,
+		}
+	}
+
+	tests := []struct {
+		name   string
+		status models.UnitStatus
+		want   int
+	}{
+		{"AtSea", models.UnitStatusActive, 0},
+		{"RefuelingAtSea", models.UnitStatusRefueling, models.AirAttackDRMRefuelAtSea},
+		{"InPort", models.UnitStatusInPort, models.AirAttackDRMInPort},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			unit := newTarget()
+			unit.Status = tt.status
+			target, err := airAttackTarget(game, models.PlayerSideAllied, unit)
+			if err != nil {
+				t.Fatalf("Корабль должен быть целью воздушной атаки: %v", err)
+			}
+			if target.DRM != tt.want {
+				t.Errorf("Ожидался модификатор %d, получено %d", tt.want, target.DRM)
+			}
+		})
+	}
+
+	t.Run("RudderDamaged", func(t *testing.T) {
+		unit := newTarget()
+		unit.Damage = []models.Damage{{Type: models.DamageTypeRudder, Severity: 1}}
+		if target, _ := airAttackTarget(game, models.PlayerSideAllied, unit); target.DRM != models.AirAttackDRMRefuelAtSea {
+			t.Errorf("Ожидался модификатор за поврежденный руль, получено %d", target.DRM)
+		}
+	})
+
+	t.Run("InvalidTargets", func(t *testing.T) {
+		if _, err := airAttackTarget(game, models.PlayerSideGerman, newTarget()); !errors.Is(err, ErrNotAirAttackTarget) {
+			t.Errorf("Свой корабль нельзя атаковать, получено %v", err)
+		}
+		hidden := newTarget()
+		hidden.DetectionLevel = models.DetectionLevelNone
+		if _, err := airAttackTarget(game, models.PlayerSideAllied, hidden); !errors.Is(err, ErrNotAirAttackTarget) {
+			t.Errorf("Необнаруженный корабль нельзя атаковать, получено %v", err)
+		}
+	})
+}
+
+func TestNavalCombatants(t *testing.T) {
+	game := newVictoryTestGame()
+	newUnits := func() []*models.NavalUnit {
+		return []*models.NavalUnit{
+			{ID: "bismarck", GameID: "game", Type: models.UnitTypeBattleship, Owner: "german", Position: "Q20", HullBoxes: 12, CurrentHull: 12, Evasion: 28},
+			{ID: "hood", GameID: "game", Type: models.UnitTypeBattlecruiser, Owner: "allied", Position: "Q20", HullBoxes: 10, CurrentHull: 10, Evasion: 30},
+		}
+	}
+
+	t.Run("SameHex", func(t *testing.T) {
+		if err := validateNavalCombatants(game, models.PlayerSideAllied, newUnits()); err != nil {
+			t.Errorf("Корабли одного гекса должны вступить в бой: %v", err)
+		}
+		units := newUnits()
+		units[1].Position = "Q21"
+		if err := validateNavalCombatants(game, models.PlayerSideAllied, units); !errors.Is(err, ErrNotNavalCombatant) {
+			t.Errorf("Корабли разных гексов не вступают в бой, получено %v", err)
+		}
+		units = newUnits()[:1]
+		if err := validateNavalCombatants(game, models.PlayerSideAllied, units); !errors.Is(err, ErrNotNavalCombatant) {
+			t.Errorf("Сторона не может начать бой без своих кораблей, получено %v", err)
+		}
+	})
+
+	t.Run("AlreadyInCombat", func(t *testing.T) {
+		units := newUnits()
+		units[0].EnterTacticalCombat("zone-1", models.TacticalFacingClosing)
+		if err := validateNavalCombatants(game, models.PlayerSideAllied, units); !errors.Is(err, ErrNotNavalCombatant) {
+			t.Errorf("Корабль, уже ведущий бой, не может вступить в бой повторно, получено %v", err)
+		}
+	})
+
+	t.Run("CrewExhaustion", func(t *testing.T) {
+		bismarck := newUnits()[0]
+		bismarck.EnterTacticalCombat("zone-1", models.TacticalFacingClosing)
+		bismarck.ApplyCrewExhaustion()
+
+		combatant := bismarck.NavalCombatant()
+		if !combatant.CrewExhausted || combatant.Evasion != 28-models.CrewExhaustionEvasionLoss || combatant.FireDRM != models.CrewExhaustionFireDRM {
+			t.Errorf("Усталость экипажа должна снизить уклоняемость и стрельбу, получено %+v", combatant)
+		}
+	})
+
+	t.Run("FireRoll", func(t *testing.T) {
+		rules := NewSpecialRulesService()
+		pow := &models.NavalUnit{ID: "pow", GameID: "game", Owner: "allied", Position: "Q20",
+			PrimaryArmamentBow: 4, PrimaryArmamentStern: 2, SecondaryArmament: 2}
+		rules.ruleManager.RegisterUnitRules(pow.ID, []models.SpecialRule{
+			{Type: models.SpecialRuleUnreliableMainArmament, IsActive: true},
+		})
+		pow.EnterTacticalCombat("zone-1", models.TacticalFacingClosing)
+		pow.ApplyCrewExhaustion()
+
+		roll := pow.NewFireRoll(true, 0)
+		rules.PreFire(pow, models.CombatContext{Round: 1}, &roll)
+		if roll.Factor != 5 || roll.DRM != models.CrewExhaustionFireDRM {
+			t.Errorf("Ненадежный главный калибр и усталость экипажа должны ухудшить стрельбу, получено %+v", roll)
+		}
+		if secondary := pow.NewFireRoll(false, 0); secondary.Factor != 2 {
+			t.Errorf("Ожидался фактор вспомогательного калибра 2, получено %d", secondary.Factor)
+		}
+	})
+}
+
+func TestCanTargetInCombat(t *testing.T) {
+	game := newVictoryTestGame()
+
+	tests := []struct {
+		name string
+		unit models.NavalUnit
+		want bool
+	}{
+		{"OwnHidden", models.NavalUnit{GameID: "game", Owner: "allied", DetectionLevel: models.DetectionLevelNone}, true},
+		{"EnemySighted", models.NavalUnit{GameID: "game", Owner: "german", DetectionLevel: models.DetectionLevelSighted}, true},
+		{"EnemyShadowed", models.NavalUnit{GameID: "game", Owner: "german", DetectionLevel: models.DetectionLevelShadowed}, true},
+		{"EnemyHidden", models.NavalUnit{GameID: "game", Owner: "german", DetectionLevel: models.DetectionLevelNone}, false},
+		{"EnemyLost", models.NavalUnit{GameID: "game", Owner: "german", DetectionLevel: models.DetectionLevelLost}, false},
+		{"OtherGame", models.NavalUnit{GameID: "other", Owner: "allied"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := canTargetInCombat(game, models.PlayerSideAllied, &tt.unit); got != tt.want {
+				t.Errorf("Ожидалось %v, получено %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestCheckConvoyAttack(t *testing.T) {
+	game := newVictoryTestGame()
+	game.CurrentPhase = models.PhaseNavalCombat
+
+	if err := checkConvoyAttack(game, models.PlayerSideGerman); err != nil {
+		t.Errorf("Немецкий игрок топит конвои в Фазе морского боя: %v", err)
+	}
+	if err := checkConvoyAttack(game, models.PlayerSideAllied); !errors.Is(err, ErrNotConvoyRaider) {
+		t.Errorf("Союзник не может записать потопленный конвой, получено %v", err)
+	}
+	game.CurrentPhase = models.PhaseMovement
+	if err := checkConvoyAttack(game, models.PlayerSideGerman); !errors.Is(err, ErrNotNavalCombatPhase) {
+		t.Errorf("Конвой топят только в Фазе морского боя, получено %v", err)
+	}
+}
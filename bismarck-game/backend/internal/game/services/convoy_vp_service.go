@@ -0,0 +1,282 @@
+package services
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"bismarck-game/backend/internal/game/models"
+	"bismarck-game/backend/pkg/database"
+	"bismarck-game/backend/pkg/logger"
+)
+
+// ErrConvoyBoxHidden возвращается, когда коробку VP конвоя запрашивает не немецкий игрок
+var ErrConvoyBoxHidden = errors.New("convoy VP box is hidden from this player")
+
+// ErrConvoyBoxNotFound возвращается, если коробка VP конвоя для игры не создана
+var ErrConvoyBoxNotFound = errors.New("convoy VP box not found")
+
+// ErrConvoyPoolExhausted возвращается, если в пуле не осталось маркеров VP конвоя
+var ErrConvoyPoolExhausted = errors.New("convoy VP marker pool is exhausted")
+
+// ConvoyVPService управляет секретным пулом маркеров VP конвоя.
+// Содержимое коробки конвоя доступно только немецкому игроку и раскрывается
+// в итоговом подсчете очков
+type ConvoyVPService struct {
+	db     *database.Database
+	logger *logger.Logger
+	dice   DiceRoller
+}
+
+// NewConvoyVPService создает новый сервис VP конвоя
+func NewConvoyVPService(db *database.Database, logger *logger.Logger, dice DiceRoller) *ConvoyVPService {
+	if dice == nil {
+		dice = NewRandomDice()
+	}
+	return &ConvoyVPService{
+		db:     db,
+		logger: logger,
+		dice:   dice,
+	}
+}
+
+// InitializeBoxTx создает пул маркеров и пустую коробку конвоя для игры в рамках транзакции начала игры
+func (s *ConvoyVPService) InitializeBoxTx(tx *sql.Tx, game *models.Game) (*models.ConvoyVPBox, error) {
+	convoyConfig := game.Settings.VictoryConditions.ConvoyVP
+
+	pool := append([]float64(nil), convoyConfig.MarkerPool...)
+	if len(pool) == 0 {
+		pool = models.GetDefaultConvoyMarkerPool()
+	}
+	if err := validateConvoyMarkerPool(pool, convoyConfig.ConvoyMax); err != nil {
+		return nil, err
+	}
+	merchantValue := convoyConfig.SingleMerchant
+	if merchantValue == 0 {
+		merchantValue = 0.5
+	}
+
+	box := &models.ConvoyVPBox{
+		GameID:        game.ID,
+		Pool:          pool,
+		Markers:       []models.ConvoyVPMarker{},
+		MerchantValue: merchantValue,
+	}
+
+	query := `
+		INSERT INTO convoy_vp_boxes (game_id, pool, markers, merchants_sunk, merchant_value)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (game_id) DO NOTHING
+		RETURNING created_at, updated_at`
+
+	poolJSON, markersJSON, err := marshalConvoyBox(box)
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.QueryRow(query,
+		box.GameID, poolJSON, markersJSON, box.MerchantsSunk, box.MerchantValue,
+	).Scan(&box.CreatedAt, &box.UpdatedAt)
+	if err == sql.ErrNoRows {
+		// Коробка уже создана
+		return s.getBox(tx, game.ID, false)
+	}
+	if err != nil {
+		s.logger.Error("Failed to initialize convoy VP box", "game_id", game.ID, "error", err)
+		return nil, fmt.Errorf("failed to initialize convoy VP box: %w", err)
+	}
+
+	s.logger.Info("Initialized convoy VP box", "game_id", game.ID, "pool_size", len(box.Pool))
+	return box, nil
+}
+
+// RecordConvoySunk вытягивает случайный маркер VP за потопленный конвой в коробку немецкого игрока
+func (s *ConvoyVPService) RecordConvoySunk(game *models.Game, convoyID string) (*models.ConvoyVPMarker, error) {
+	tx, err := s.db.BeginTx()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	box, err := s.getBox(tx, game.ID, true)
+	if err != nil {
+		return nil, err
+	}
+
+	drawn, err := drawConvoyMarker(box, s.dice, convoyID, game.CurrentTurn)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.saveBox(tx, box); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit convoy VP box: %w", err)
+	}
+
+	// Значение маркера не логируется, чтобы не раскрывать его
+	s.logger.Info("Convoy VP marker drawn", "game_id", game.ID, "convoy_id", convoyID)
+	return drawn, nil
+}
+
+// RecordMerchantSunk добавляет маркер 0.5 VP за потопленное одиночное торговое судно
+func (s *ConvoyVPService) RecordMerchantSunk(game *models.Game) error {
+	query := `
+		UPDATE convoy_vp_boxes SET
+			merchants_sunk = merchants_sunk + 1,
+			updated_at = CURRENT_TIMESTAMP
+		WHERE game_id = $1`
+
+	result, err := s.db.Exec(query, game.ID)
+	if err != nil {
+		s.logger.Error("Failed to record merchant sunk", "game_id", game.ID, "error", err)
+		return fmt.Errorf("failed to record merchant sunk: %w", err)
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return ErrConvoyBoxNotFound
+	}
+
+	s.logger.Info("Merchant sunk recorded", "game_id", game.ID)
+	return nil
+}
+
+// GetBoxForPlayer возвращает содержимое коробки VP конвоя игроку.
+// Пока игра не завершена, коробка доступна только немецкому игроку
+func (s *ConvoyVPService) GetBoxForPlayer(game *models.Game, userID string) (*models.ConvoyVPSummary, error) {
+	if !game.IsCompleted() && game.GetPlayerRole(userID) != models.PlayerSideGerman {
+		return nil, ErrConvoyBoxHidden
+	}
+
+	box, err := s.getBox(s.db, game.ID, false)
+	if err != nil {
+		return nil, err
+	}
+
+	summary := box.Summary()
+	return &summary, nil
+}
+
+// RevealBox раскрывает коробку VP конвоя для итогового подсчета очков
+func (s *ConvoyVPService) RevealBox(game *models.Game) (*models.ConvoyVPBox, error) {
+	box, err := s.getBox(s.db, game.ID, false)
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("Convoy VP box revealed", "game_id", game.ID, "total_vp", box.TotalVP())
+	return box, nil
+}
+
+// validateConvoyMarkerPool проверяет, что значения маркеров пула лежат в пределах от 0.5 VP
+// до наибольшего значения маркера (по умолчанию 2 VP)
+func validateConvoyMarkerPool(pool []float64, maxVP float64) error {
+	if maxVP <= 0 {
+		maxVP = models.MaxConvoyMarkerVP
+	}
+	for _, value := range pool {
+		if value < models.MinConvoyMarkerVP || value > maxVP {
+			return fmt.Errorf("invalid convoy VP marker value %.1f: must be between %.1f and %.1f",
+				value, models.MinConvoyMarkerVP, maxVP)
+		}
+	}
+	return nil
+}
+
+// drawConvoyMarker вытягивает случайный маркер из пула в коробку конвоя
+func drawConvoyMarker(box *models.ConvoyVPBox, dice DiceRoller, convoyID string, turn int) (*models.ConvoyVPMarker, error) {
+	if len(box.Pool) == 0 {
+		return nil, ErrConvoyPoolExhausted
+	}
+
+	index := dice.Intn(len(box.Pool))
+	value := box.Pool[index]
+	box.Pool = append(box.Pool[:index], box.Pool[index+1:]...)
+
+	marker := models.ConvoyVPMarker{
+		Value:     value,
+		ConvoyID:  convoyID,
+		TurnDrawn: turn,
+		DrawnAt:   time.Now(),
+	}
+	box.Markers = append(box.Markers, marker)
+
+	return &marker, nil
+}
+
+// marshalConvoyBox сериализует пул и вытянутые маркеры коробки конвоя
+func marshalConvoyBox(box *models.ConvoyVPBox) ([]byte, []byte, error) {
+	poolJSON, err := json.Marshal(box.Pool)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to marshal convoy VP pool: %w", err)
+	}
+	markersJSON, err := json.Marshal(box.Markers)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to marshal convoy VP markers: %w", err)
+	}
+	return poolJSON, markersJSON, nil
+}
+
+// queryRower выполняет запрос, возвращающий одну строку
+type queryRower interface {
+	QueryRow(query string, args ...interface{}) *sql.Row
+}
+
+// getBox загружает коробку VP конвоя
+func (s *ConvoyVPService) getBox(q queryRower, gameID string, forUpdate bool) (*models.ConvoyVPBox, error) {
+	query := `
+		SELECT game_id, pool, markers, merchants_sunk, merchant_value, created_at, updated_at
+		FROM convoy_vp_boxes
+		WHERE game_id = $1`
+	if forUpdate {
+		query += ` FOR UPDATE`
+	}
+
+	var box models.ConvoyVPBox
+	var poolJSON, markersJSON []byte
+
+	err := q.QueryRow(query, gameID).Scan(
+		&box.GameID, &poolJSON, &markersJSON, &box.MerchantsSunk, &box.MerchantValue,
+		&box.CreatedAt, &box.UpdatedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrConvoyBoxNotFound
+		}
+		s.logger.Error("Failed to get convoy VP box", "game_id", gameID, "error", err)
+		return nil, fmt.Errorf("failed to get convoy VP box: %w", err)
+	}
+
+	if err := json.Unmarshal(poolJSON, &box.Pool); err != nil {
+		return nil, fmt.Errorf("failed to parse convoy VP pool: %w", err)
+	}
+	if err := json.Unmarshal(markersJSON, &box.Markers); err != nil {
+		return nil, fmt.Errorf("failed to parse convoy VP markers: %w", err)
+	}
+
+	return &box, nil
+}
+
+// saveBox сохраняет коробку VP конвоя
+func (s *ConvoyVPService) saveBox(tx *sql.Tx, box *models.ConvoyVPBox) error {
+	query := `
+		UPDATE convoy_vp_boxes SET
+			pool = $2, markers = $3, merchants_sunk = $4,
+			updated_at = CURRENT_TIMESTAMP
+		WHERE game_id = $1`
+
+	poolJSON, markersJSON, err := marshalConvoyBox(box)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(query, box.GameID, poolJSON, markersJSON, box.MerchantsSunk)
+	if err != nil {
+		s.logger.Error("Failed to save convoy VP box", "game_id", box.GameID, "error", err)
+		return fmt.Errorf("failed to save convoy VP box: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,106 @@
+package services
+
+import (
+	"bismarck-game/backend/internal/game/models"
+	"testing"
+)
+
+// fixedDice возвращает заранее заданные результаты бросков
+type fixedDice struct {
+	rolls []int
+	next  int
+}
+
+func (d *fixedDice) RollD10() int {
+	return d.Intn(10)
+}
+
+func (d *fixedDice) Intn(n int) int {
+	if len(d.rolls) == 0 {
+		return 0
+	}
+	roll := d.rolls[d.next%len(d.rolls)]
+	d.next++
+	return roll % n
+}
+
+func TestConvoyVPBox(t *testing.T) {
+	t.Run("DrawMarkers", func(t *testing.T) {
+		box := &models.ConvoyVPBox{
+			Pool:          []float64{0.5, 1, 1.5, 2},
+			MerchantValue: 0.5,
+		}
+		dice := &fixedDice{rolls: []int{2, 0}}
+
+		first, err := drawConvoyMarker(box, dice, "HX 127", 5)
+		if err != nil {
+			t.Fatalf("Ошибка вытягивания маркера: %v", err)
+		}
+		second, err := drawConvoyMarker(box, dice, "SL 74", 5)
+		if err != nil {
+			t.Fatalf("Ошибка вытягивания маркера: %v", err)
+		}
+
+		if first.Value != 1.5 || second.Value != 0.5 {
+			t.Errorf("Неверные значения маркеров: %.1f, %.1f", first.Value, second.Value)
+		}
+		if first.ConvoyID != "HX 127" || first.TurnDrawn != 5 {
+			t.Errorf("Маркер должен хранить конвой и ход, получено %s/%d", first.ConvoyID, first.TurnDrawn)
+		}
+		if len(box.Pool) != 2 || len(box.Markers) != 2 {
+			t.Errorf("В пуле должно остаться 2 маркера, осталось %d", len(box.Pool))
+		}
+		if box.ConvoyVP() != 2 {
+			t.Errorf("Ожидалось 2 VP за конвои, получено %.1f", box.ConvoyVP())
+		}
+	})
+
+	t.Run("MerchantsAndTotal", func(t *testing.T) {
+		box := &models.ConvoyVPBox{
+			Markers: []models.ConvoyVPMarker{
+				{Value: 2},
+				{Value: 0.5},
+			},
+			MerchantsSunk: 3,
+			MerchantValue: 0.5,
+		}
+
+		if box.TotalVP() != 4 {
+			t.Errorf("Ожидалось 4 VP, получено %.1f", box.TotalVP())
+		}
+	})
+
+	t.Run("PoolExhausted", func(t *testing.T) {
+		box := &models.ConvoyVPBox{Pool: []float64{}}
+
+		if _, err := drawConvoyMarker(box, &fixedDice{}, "OB 324", 1); err == nil {
+			t.Error("Ожидалась ошибка при исчерпании пула маркеров")
+		}
+	})
+
+	t.Run("MarkerValues", func(t *testing.T) {
+		if err := validateConvoyMarkerPool(models.GetDefaultConvoyMarkerPool(), 0); err != nil {
+			t.Errorf("Пул по умолчанию должен быть допустимым: %v", err)
+		}
+		if err := validateConvoyMarkerPool([]float64{1, 3}, 2); err == nil {
+			t.Error("Ожидалась ошибка для маркера дороже 2 VP")
+		}
+		if err := validateConvoyMarkerPool([]float64{0.25}, 2); err == nil {
+			t.Error("Ожидалась ошибка для маркера дешевле 0.5 VP")
+		}
+	})
+
+	t.Run("HiddenFromAllied", func(t *testing.T) {
+		service := NewConvoyVPService(nil, nil, &fixedDice{})
+		game := &models.Game{
+			ID:        "game",
+			Player1ID: "german",
+			Player2ID: "allied",
+			Status:    models.GameStatusActive,
+		}
+
+		if _, err := service.GetBoxForPlayer(game, "allied"); err != ErrConvoyBoxHidden {
+			t.Errorf("Коробка конвоя должна быть скрыта от союзника, получено: %v", err)
+		}
+	})
+}
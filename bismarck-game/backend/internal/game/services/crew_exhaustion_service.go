@@ -0,0 +1,111 @@
+package services
+
+import (
+	"database/sql"
+	"fmt"
+
+	"bismarck-game/backend/internal/game/models"
+	"bismarck-game/backend/pkg/database"
+	"bismarck-game/backend/pkg/logger"
+)
+
+// CrewExhaustionService учитывает участие кораблей в боях по суткам и применяет
+// необязательное правило усталости экипажа (13.2)
+type CrewExhaustionService struct {
+	db     *database.Database
+	logger *logger.Logger
+}
+
+// NewCrewExhaustionService создает новый сервис усталости экипажа
+func NewCrewExhaustionService(db *database.Database, logger *logger.Logger) *CrewExhaustionService {
+	return &CrewExhaustionService{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// RecordCombat отмечает участие кораблей в бою на текущем ходу.
+// Участие учитывается всегда, независимо от настройки правила
+func (s *CrewExhaustionService) RecordCombat(tx *sql.Tx, game *models.Game, unitIDs []string, combatType models.CombatType) error {
+	query := `
+		INSERT INTO combat_participation (game_id, unit_id, turn, day, type)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (game_id, unit_id, turn, type) DO NOTHING`
+
+	for _, unitID := range unitIDs {
+		record := models.NewCombatParticipation(game.ID, unitID, game.CurrentTurn, combatType)
+		if _, err := tx.Exec(query, record.GameID, record.UnitID, record.Turn, record.Day, record.Type); err != nil {
+			s.logger.Error("Failed to record combat participation", "game_id", game.ID, "unit_id", unitID, "error", err)
+			return fmt.Errorf("failed to record combat participation: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// GetExhaustedUnits возвращает корабли, экипажи которых устали на текущем ходу.
+// Если правило выключено в настройках игры, усталых кораблей нет
+func (s *CrewExhaustionService) GetExhaustedUnits(game *models.Game, units []*models.NavalUnit) (map[string]bool, error) {
+	exhausted := make(map[string]bool)
+	if !game.Settings.EnableCrewExhaustion {
+		return exhausted, nil
+	}
+
+	query := `
+		SELECT id, game_id, unit_id, turn, day, type, created_at
+		FROM combat_participation
+		WHERE game_id = $1 AND day = $2`
+
+	rows, err := s.db.Query(query, game.ID, models.DayForTurn(game.CurrentTurn)-1)
+	if err != nil {
+		s.logger.Error("Failed to get combat participation", "game_id", game.ID, "error", err)
+		return nil, fmt.Errorf("failed to get combat participation: %w", err)
+	}
+	defer rows.Close()
+
+	records := make(map[string][]models.CombatParticipation)
+	for rows.Next() {
+		var record models.CombatParticipation
+		err := rows.Scan(&record.ID, &record.GameID, &record.UnitID, &record.Turn, &record.Day, &record.Type, &record.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan combat participation: %w", err)
+		}
+		records[record.UnitID] = append(records[record.UnitID], record)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read combat participation: %w", err)
+	}
+
+	return exhaustedUnits(game, units, records), nil
+}
+
+// ApplyCrewExhaustion применяет штрафы усталости к кораблям, вступившим в бой.
+// Вызывается после EnterTacticalCombat, до первой стрельбы
+func (s *CrewExhaustionService) ApplyCrewExhaustion(game *models.Game, units []*models.NavalUnit) error {
+	exhausted, err := s.GetExhaustedUnits(game, units)
+	if err != nil {
+		return err
+	}
+	for _, unit := range units {
+		if exhausted[unit.ID] {
+			unit.ApplyCrewExhaustion()
+			s.logger.Info("Crew exhaustion applied", "game_id", game.ID, "unit_id", unit.ID)
+		}
+	}
+
+	return nil
+}
+
+// exhaustedUnits возвращает крупные немецкие корабли, участвовавшие в двух и более боях в предыдущие сутки
+func exhaustedUnits(game *models.Game, units []*models.NavalUnit, records map[string][]models.CombatParticipation) map[string]bool {
+	exhausted := make(map[string]bool)
+	for _, unit := range units {
+		if !unit.IsSubjectToCrewExhaustion(unitSide(game, unit)) {
+			continue
+		}
+		if models.IsCrewExhausted(records[unit.ID], game.CurrentTurn) {
+			exhausted[unit.ID] = true
+		}
+	}
+	return exhausted
+}
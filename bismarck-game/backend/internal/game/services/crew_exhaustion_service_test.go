@@ -0,0 +1,63 @@
+package services
+
+import (
+	"bismarck-game/backend/internal/game/models"
+	"testing"
+)
+
+func TestCrewExhaustion(t *testing.T) {
+	game := newVictoryTestGame()
+	game.Settings.EnableCrewExhaustion = true
+	game.CurrentTurn = 4 // Утро вторых суток
+
+	bismarck := &models.NavalUnit{ID: "bismarck", Type: models.UnitTypeBattleship, Owner: "german", Evasion: 28}
+	prinz := &models.NavalUnit{ID: "prinz-eugen", Type: models.UnitTypeHeavyCruiser, Owner: "german", Evasion: 32}
+	hood := &models.NavalUnit{ID: "hood", Type: models.UnitTypeBattlecruiser, Owner: "allied", Evasion: 30}
+	units := []*models.NavalUnit{bismarck, prinz, hood}
+
+	records := map[string][]models.CombatParticipation{
+		"bismarck": {
+			models.NewCombatParticipation(game.ID, "bismarck", 1, models.CombatTypeNaval),
+			models.NewCombatParticipation(game.ID, "bismarck", 3, models.CombatTypeAirAttack),
+		},
+		"prinz-eugen": {
+			models.NewCombatParticipation(game.ID, "prinz-eugen", 1, models.CombatTypeNaval),
+		},
+		"hood": {
+			models.NewCombatParticipation(game.ID, "hood", 1, models.CombatTypeNaval),
+			models.NewCombatParticipation(game.ID, "hood", 2, models.CombatTypeNaval),
+		},
+	}
+
+	exhausted := exhaustedUnits(game, units, records)
+	if !exhausted["bismarck"] {
+		t.Error("BISMARCK после двух боев в первые сутки должен быть утомлен")
+	}
+	if exhausted["prinz-eugen"] {
+		t.Error("Один бой за сутки не вызывает усталости")
+	}
+	if exhausted["hood"] {
+		t.Error("Правило усталости действует только на немецкие корабли")
+	}
+
+	t.Run("NextDayOnly", func(t *testing.T) {
+		later := *game
+		later.CurrentTurn = 7 // Третьи сутки
+		if exhaustedUnits(&later, units, records)["bismarck"] {
+			t.Error("Усталость действует только в следующие сутки")
+		}
+	})
+
+	t.Run("Penalties", func(t *testing.T) {
+		bismarck.EnterTacticalCombat("zone-1", "closing")
+		bismarck.ApplyCrewExhaustion()
+		bismarck.ApplyCrewExhaustion()
+
+		if bismarck.GetTacticalEvasion() != 28-models.CrewExhaustionEvasionLoss {
+			t.Errorf("Уклоняемость должна снизиться один раз, получено %d", bismarck.GetTacticalEvasion())
+		}
+		if bismarck.GetFireDRM() != models.CrewExhaustionFireDRM {
+			t.Errorf("Ожидался модификатор стрельбы %d, получено %d", models.CrewExhaustionFireDRM, bismarck.GetFireDRM())
+		}
+	})
+}
@@ -0,0 +1,419 @@
+package services
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"bismarck-game/backend/internal/game/models"
+	"bismarck-game/backend/pkg/database"
+	"bismarck-game/backend/pkg/logger"
+)
+
+// Ошибки фазы развертывания
+var (
+	ErrNotDeploymentPhase = errors.New("action is only allowed in the deployment phase")
+	ErrDeploymentLocked   = errors.New("deployment is already locked")
+	ErrDeploymentNotReady = errors.New("both players must lock their deployment")
+	ErrInvalidPlacement   = errors.New("invalid deployment placement")
+)
+
+// DeploymentService хранит секретную расстановку сторон в фазе развертывания и применяет ее
+// одновременно после того, как оба игрока ее зафиксировали. Расставленные юниты противника
+// видны только по правилам тумана войны
+type DeploymentService struct {
+	db          *database.Database
+	logger      *logger.Logger
+	unitService *UnitService
+	notifier    GameNotifier
+}
+
+// NewDeploymentService создает новый сервис фазы развертывания
+func NewDeploymentService(db *database.Database, logger *logger.Logger, unitService *UnitService, notifier GameNotifier) *DeploymentService {
+	return &DeploymentService{
+		db:          db,
+		logger:      logger,
+		unitService: unitService,
+		notifier:    notifier,
+	}
+}
+
+// deploymentBatchColumns список колонок расстановки в порядке сканирования
+const deploymentBatchColumns = `id, game_id, player_id, side, placements, locked,
+			   locked_at, resolved_at, created_at, updated_at`
+
+// GetDeploymentOptions возвращает юниты, которые игрок расставляет, и их зоны
+func (s *DeploymentService) GetDeploymentOptions(game *models.Game, playerID string) ([]models.DeploymentOption, error) {
+	side := game.GetPlayerRole(playerID)
+	if side == "" {
+		return nil, ErrNotGamePlayer
+	}
+
+	units, err := s.unitService.GetNavalUnitsByGameID(game.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get units: %w", err)
+	}
+	zones, err := s.getDeployableZones(game)
+	if err != nil {
+		return nil, err
+	}
+
+	options := []models.DeploymentOption{}
+	for i := range units {
+		zone, deployable := zones[units[i].ID]
+		if !deployable || zone.Side != side {
+			continue
+		}
+		options = append(options, models.DeploymentOption{
+			UnitID:   units[i].ID,
+			UnitName: units[i].Name,
+			Position: units[i].Position,
+			Zone:     zone,
+		})
+	}
+
+	return options, nil
+}
+
+// GetDeployment возвращает расстановку игрока
+func (s *DeploymentService) GetDeployment(game *models.Game, playerID string) (*models.DeploymentBatch, error) {
+	side := game.GetPlayerRole(playerID)
+	if side == "" {
+		return nil, ErrNotGamePlayer
+	}
+
+	query := `SELECT ` + deploymentBatchColumns + ` FROM deployment_orders WHERE game_id = $1 AND player_id = $2`
+
+	batch, err := scanDeploymentBatch(s.db.QueryRow(query, game.ID, playerID))
+	if err == sql.ErrNoRows {
+		return &models.DeploymentBatch{
+			GameID:     game.ID,
+			PlayerID:   playerID,
+			Side:       side,
+			Placements: []models.DeploymentPlacement{},
+		}, nil
+	}
+	if err != nil {
+		s.logger.Error("Failed to get deployment", "game_id", game.ID, "player_id", playerID, "error", err)
+		return nil, fmt.Errorf("failed to get deployment: %w", err)
+	}
+
+	return batch, nil
+}
+
+// SubmitDeployment сохраняет расстановку игрока, заменяя предыдущую. Расстановку можно
+// изменять, пока игрок ее не зафиксировал
+func (s *DeploymentService) SubmitDeployment(game *models.Game, playerID string, placements []models.DeploymentPlacement) (*models.DeploymentBatch, error) {
+	side, err := checkDeploymentStep(game, playerID)
+	if err != nil {
+		return nil, err
+	}
+
+	units, err := s.unitService.GetNavalUnitsByGameID(game.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get units: %w", err)
+	}
+	zones, err := s.getDeployableZones(game)
+	if err != nil {
+		return nil, err
+	}
+	if err := validatePlacements(game, side, units, zones, placements); err != nil {
+		return nil, err
+	}
+
+	query := `
+		INSERT INTO deployment_orders (game_id, player_id, side, placements)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (game_id, player_id) DO UPDATE SET
+			placements = EXCLUDED.placements, updated_at = CURRENT_TIMESTAMP
+		WHERE deployment_orders.locked = false
+		RETURNING ` + deploymentBatchColumns
+
+	placementsJSON, _ := json.Marshal(placements)
+
+	batch, err := scanDeploymentBatch(s.db.QueryRow(query, game.ID, playerID, side, placementsJSON))
+	if err == sql.ErrNoRows {
+		return nil, ErrDeploymentLocked
+	}
+	if err != nil {
+		s.logger.Error("Failed to submit deployment", "game_id", game.ID, "player_id", playerID, "error", err)
+		return nil, fmt.Errorf("failed to submit deployment: %w", err)
+	}
+
+	s.logger.Info("Deployment submitted", "game_id", game.ID, "side", side, "placements", len(placements))
+	return batch, nil
+}
+
+// LockDeployment фиксирует расстановку игрока. Противник узнает только о том, что расстановка
+// зафиксирована. Когда оба игрока ее зафиксировали, расстановка применяется
+func (s *DeploymentService) LockDeployment(game *models.Game, playerID string) (*models.DeploymentBatch, error) {
+	side, err := checkDeploymentStep(game, playerID)
+	if err != nil {
+		return nil, err
+	}
+
+	query := `
+		INSERT INTO deployment_orders (game_id, player_id, side, locked, locked_at)
+		VALUES ($1, $2, $3, true, CURRENT_TIMESTAMP)
+		ON CONFLICT (game_id, player_id) DO UPDATE SET
+			locked = true, locked_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP
+		WHERE deployment_orders.locked = false
+		RETURNING ` + deploymentBatchColumns
+
+	batch, err := scanDeploymentBatch(s.db.QueryRow(query, game.ID, playerID, side))
+	if err == sql.ErrNoRows {
+		return nil, ErrDeploymentLocked
+	}
+	if err != nil {
+		s.logger.Error("Failed to lock deployment", "game_id", game.ID, "player_id", playerID, "error", err)
+		return nil, fmt.Errorf("failed to lock deployment: %w", err)
+	}
+
+	if s.notifier != nil {
+		s.notifier.BroadcastGameEvent(game.ID, "deployment_locked", batch.Status())
+	}
+
+	s.logger.Info("Deployment locked", "game_id", game.ID, "side", side)
+
+	if _, err := s.ResolveIfReady(game); err != nil {
+		return nil, err
+	}
+	return batch, nil
+}
+
+// GetDeploymentStatus возвращает публичный статус расстановки обоих игроков
+func (s *DeploymentService) GetDeploymentStatus(game *models.Game) ([]models.OrderBatchStatus, error) {
+	batches, err := s.getBatches(game.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := []models.OrderBatchStatus{}
+	for _, side := range []models.PlayerSide{models.PlayerSideGerman, models.PlayerSideAllied} {
+		status := models.OrderBatchStatus{Side: side}
+		for i := range batches {
+			if batches[i].Side == side {
+				status = batches[i].Status()
+			}
+		}
+		statuses = append(statuses, status)
+	}
+
+	return statuses, nil
+}
+
+// ResolveIfReady применяет расстановку, если оба игрока ее зафиксировали или истекло время хода.
+// Нерасставленные юниты остаются в гексах по умолчанию. Возвращает true, если расстановка применена
+func (s *DeploymentService) ResolveIfReady(game *models.Game) (bool, error) {
+	batches, err := s.getBatches(game.ID)
+	if err != nil {
+		return false, err
+	}
+	if len(batches) > 0 && allDeploymentsResolved(batches) {
+		return true, nil
+	}
+	if !models.DeploymentReady(game, batches, time.Now()) {
+		return false, nil
+	}
+
+	tx, err := s.db.BeginTx()
+	if err != nil {
+		return false, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Помечаем расстановку примененной, чтобы она не была применена дважды
+	query := `
+		UPDATE deployment_orders SET
+			locked = true, resolved_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP
+		WHERE game_id = $1 AND resolved_at IS NULL
+		RETURNING ` + deploymentBatchColumns
+
+	claimed, err := queryDeploymentBatches(tx, query, game.ID)
+	if err != nil {
+		return false, err
+	}
+
+	units, err := s.unitService.GetNavalUnitsByGameID(game.ID)
+	if err != nil {
+		return false, fmt.Errorf("failed to get units: %w", err)
+	}
+	unitMap := make(map[string]*models.NavalUnit, len(units))
+	for i := range units {
+		unitMap[units[i].ID] = &units[i]
+	}
+
+	for _, batch := range claimed {
+		for _, placement := range batch.Placements {
+			unit, exists := unitMap[placement.UnitID]
+			if !exists {
+				continue
+			}
+			unit.Position = placement.Hex
+			if err := s.unitService.UpdateNavalUnitTx(tx, unit); err != nil {
+				return false, err
+			}
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, fmt.Errorf("failed to commit deployment: %w", err)
+	}
+
+	if s.notifier != nil {
+		for _, batch := range claimed {
+			s.notifier.SendNotification(batch.PlayerID, map[string]interface{}{
+				"type":    "deployment_resolved",
+				"game_id": game.ID,
+				"data":    batch.Placements,
+			})
+		}
+		s.notifier.BroadcastGameEvent(game.ID, "deployment_resolved", map[string]interface{}{
+			"turn": game.CurrentTurn,
+		})
+	}
+
+	s.logger.Info("Deployment resolved", "game_id", game.ID, "batches", len(claimed))
+	return true, nil
+}
+
+// getDeployableZones возвращает зоны развертывания по ID юнита: юниты сценария
+// и купленные гипотетические юниты, у которых есть зона
+func (s *DeploymentService) getDeployableZones(game *models.Game) (map[string]models.DeploymentZone, error) {
+	deployables := append([]models.DeployableUnit{}, game.Settings.DeployableUnits...)
+
+	if game.Settings.UseOptionalUnits {
+		purchases, err := getOptionalUnitPurchases(s.db, game.ID)
+		if err != nil {
+			return nil, err
+		}
+		for _, purchase := range purchases {
+			option, ok := game.Settings.FindOptionalUnit(purchase.ShipID)
+			if ok && option.DeployZone != "" {
+				deployables = append(deployables, models.DeployableUnit{UnitID: purchase.UnitID, Zone: option.DeployZone})
+			}
+		}
+	}
+
+	return deployableZones(game.Settings, deployables), nil
+}
+
+// getBatches возвращает расстановку обоих игроков
+func (s *DeploymentService) getBatches(gameID string) ([]models.DeploymentBatch, error) {
+	query := `SELECT ` + deploymentBatchColumns + ` FROM deployment_orders WHERE game_id = $1`
+	return queryDeploymentBatches(s.db, query, gameID)
+}
+
+// checkDeploymentStep проверяет, что игрок может расставлять юниты: идет фаза развертывания
+func checkDeploymentStep(game *models.Game, playerID string) (models.PlayerSide, error) {
+	side := game.GetPlayerRole(playerID)
+	if side == "" {
+		return "", ErrNotGamePlayer
+	}
+	if !game.IsDeployment() {
+		return "", ErrNotDeploymentPhase
+	}
+	return side, nil
+}
+
+// deployableZones сопоставляет развертываемые юниты с их зонами. Юниты с неизвестной зоной пропускаются
+func deployableZones(settings models.GameSettings, deployables []models.DeployableUnit) map[string]models.DeploymentZone {
+	zones := make(map[string]models.DeploymentZone, len(deployables))
+	for _, deployable := range deployables {
+		if zone, ok := settings.FindDeploymentZone(deployable.Zone); ok {
+			zones[deployable.UnitID] = zone
+		}
+	}
+	return zones
+}
+
+// validatePlacements проверяет расстановку игрока: каждый юнит развертываемый, принадлежит стороне
+// и ставится в гекс своей зоны на Карте поиска не более одного раза
+func validatePlacements(game *models.Game, side models.PlayerSide, units []models.NavalUnit, zones map[string]models.DeploymentZone, placements []models.DeploymentPlacement) error {
+	unitMap := make(map[string]*models.NavalUnit, len(units))
+	for i := range units {
+		unitMap[units[i].ID] = &units[i]
+	}
+
+	placed := make(map[string]bool)
+	for _, placement := range placements {
+		unit, exists := unitMap[placement.UnitID]
+		if !exists || !unit.IsAlive() || unitSide(game, unit) != side {
+			return fmt.Errorf("%w: unit %s", ErrInvalidPlacement, placement.UnitID)
+		}
+		zone, deployable := zones[placement.UnitID]
+		if !deployable || zone.Side != side {
+			return fmt.Errorf("%w: unit %s is not deployable", ErrInvalidPlacement, placement.UnitID)
+		}
+		if !models.IsValidHex(placement.Hex) {
+			return fmt.Errorf("%w: hex %s is off the map", ErrInvalidPlacement, placement.Hex)
+		}
+		if !zone.Contains(placement.Hex) {
+			return fmt.Errorf("%w: hex %s is outside zone %s", ErrInvalidPlacement, placement.Hex, zone.ID)
+		}
+		if placed[placement.UnitID] {
+			return fmt.Errorf("%w: duplicate placement for %s", ErrInvalidPlacement, placement.UnitID)
+		}
+		placed[placement.UnitID] = true
+	}
+
+	return nil
+}
+
+// allDeploymentsResolved проверяет, применена ли расстановка обоих игроков
+func allDeploymentsResolved(batches []models.DeploymentBatch) bool {
+	for i := range batches {
+		if !batches[i].IsResolved() {
+			return false
+		}
+	}
+	return true
+}
+
+// queryDeploymentBatches выполняет запрос, возвращающий расстановку
+func queryDeploymentBatches(q querier, query string, args ...interface{}) ([]models.DeploymentBatch, error) {
+	rows, err := q.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query deployment: %w", err)
+	}
+	defer rows.Close()
+
+	batches := []models.DeploymentBatch{}
+	for rows.Next() {
+		batch, err := scanDeploymentBatch(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan deployment: %w", err)
+		}
+		batches = append(batches, *batch)
+	}
+
+	return batches, rows.Err()
+}
+
+// scanDeploymentBatch сканирует строку расстановки
+func scanDeploymentBatch(row rowScanner) (*models.DeploymentBatch, error) {
+	var batch models.DeploymentBatch
+	var placementsJSON []byte
+	var lockedAt, resolvedAt sql.NullTime
+
+	err := row.Scan(
+		&batch.ID, &batch.GameID, &batch.PlayerID, &batch.Side, &placementsJSON, &batch.Locked,
+		&lockedAt, &resolvedAt, &batch.CreatedAt, &batch.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	batch.Placements = []models.DeploymentPlacement{}
+	json.Unmarshal(placementsJSON, &batch.Placements)
+	if lockedAt.Valid {
+		batch.LockedAt = &lockedAt.Time
+	}
+	if resolvedAt.Valid {
+		batch.ResolvedAt = &resolvedAt.Time
+	}
+
+	return &batch, nil
+}
@@ -0,0 +1,117 @@
+package services
+
+import (
+	"bismarck-game/backend/internal/game/models"
+	"errors"
+	"testing"
+	"time"
+)
+
+func newDeploymentTestGame() *models.Game {
+	game := newVictoryTestGame()
+	game.CurrentPhase = models.PhaseDeployment
+	game.Settings.DeploymentZones = []models.DeploymentZone{
+		{ID: "norway", Side: models.PlayerSideGerman, Hexes: []string{"P32", "O33", "O32"}},
+		{ID: "iceland", Side: models.PlayerSideAllied, Hexes: []string{"L18", "M18"}},
+	}
+	game.Settings.DeployableUnits = []models.DeployableUnit{
+		{UnitID: "bismarck", Zone: "norway"},
+		{UnitID: "hood", Zone: "iceland"},
+		{UnitID: "lost", Zone: "faroe"},
+	}
+	return game
+}
+
+func TestValidatePlacements(t *testing.T) {
+	game := newDeploymentTestGame()
+	units := []models.NavalUnit{
+		{ID: "bismarck", Owner: "german", Position: "P32", HullBoxes: 12, CurrentHull: 12, Status: models.UnitStatusActive},
+		{ID: "prinz-eugen", Owner: "german", Position: "P32", HullBoxes: 6, CurrentHull: 6, Status: models.UnitStatusActive},
+		{ID: "hood", Owner: "allied", Position: "M18", HullBoxes: 9, CurrentHull: 9, Status: models.UnitStatusActive},
+	}
+	zones := deployableZones(game.Settings, game.Settings.DeployableUnits)
+	if len(zones) != 2 {
+		t.Fatalf("Юнит с неизвестной зоной не развертывается, ожидалось 2 юнита, получено %d", len(zones))
+	}
+
+	valid := []models.DeploymentPlacement{{UnitID: "bismarck", Hex: "O33"}}
+	if err := validatePlacements(game, models.PlayerSideGerman, units, zones, valid); err != nil {
+		t.Errorf("Расстановка в своей зоне должна быть принята, получена ошибка %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		side       models.PlayerSide
+		placements []models.DeploymentPlacement
+	}{
+		{"EnemyUnit", models.PlayerSideGerman, []models.DeploymentPlacement{{UnitID: "hood", Hex: "L18"}}},
+		{"NotDeployable", models.PlayerSideGerman, []models.DeploymentPlacement{{UnitID: "prinz-eugen", Hex: "O33"}}},
+		{"OutsideZone", models.PlayerSideAllied, []models.DeploymentPlacement{{UnitID: "hood", Hex: "K15"}}},
+		{"OffMap", models.PlayerSideAllied, []models.DeploymentPlacement{{UnitID: "hood", Hex: "AZ40"}}},
+		{"Duplicate", models.PlayerSideGerman, []models.DeploymentPlacement{{UnitID: "bismarck", Hex: "O33"}, {UnitID: "bismarck", Hex: "O32"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := validatePlacements(game, tt.side, units, zones, tt.placements); !errors.Is(err, ErrInvalidPlacement) {
+				t.Errorf("Ожидалась ошибка расстановки, получено %v", err)
+			}
+		})
+	}
+}
+
+func TestDeploymentPhase(t *testing.T) {
+	t.Run("NextPhase", func(t *testing.T) {
+		game := newDeploymentTestGame()
+		game.CurrentTurn = 1
+		game.CurrentPhase = models.PhaseWaiting
+		if turn, phase := nextGamePhase(game); turn != 1 || phase != models.PhaseDeployment {
+			t.Errorf("После подготовки ожидалась фаза развертывания, получено %d, %s", turn, phase)
+		}
+
+		game.CurrentPhase = models.PhaseDeployment
+		if turn, phase := nextGamePhase(game); turn != 1 || phase != models.PhaseMovement {
+			t.Errorf("После развертывания ожидалась Фаза движения 1-го хода, получено %d, %s", turn, phase)
+		}
+
+		game.CurrentPhase = models.PhaseWaiting
+		game.Settings.DeploymentZones = nil
+		if _, phase := nextGamePhase(game); phase != models.PhaseMovement {
+			t.Errorf("Без зон развертывания ожидалась Фаза движения, получено %s", phase)
+		}
+	})
+
+	t.Run("LockStep", func(t *testing.T) {
+		game := newDeploymentTestGame()
+		if _, err := checkDeploymentStep(game, "spectator"); !errors.Is(err, ErrNotGamePlayer) {
+			t.Errorf("Ожидалась ошибка ErrNotGamePlayer, получено %v", err)
+		}
+		game.CurrentPhase = models.PhaseMovement
+		if _, err := checkDeploymentStep(game, "german"); !errors.Is(err, ErrNotDeploymentPhase) {
+			t.Errorf("Ожидалась ошибка ErrNotDeploymentPhase, получено %v", err)
+		}
+	})
+
+	t.Run("Ready", func(t *testing.T) {
+		game := newDeploymentTestGame()
+		german := models.DeploymentBatch{Side: models.PlayerSideGerman, Locked: true}
+		allied := models.DeploymentBatch{Side: models.PlayerSideAllied}
+		if models.DeploymentReady(game, []models.DeploymentBatch{german, allied}, time.Now()) {
+			t.Error("Расстановка не применяется, пока один из игроков ее не зафиксировал")
+		}
+		allied.Locked = true
+		if !models.DeploymentReady(game, []models.DeploymentBatch{german, allied}, time.Now()) {
+			t.Error("Расстановка применяется, когда оба игрока ее зафиксировали")
+		}
+	})
+
+	t.Run("HiddenInResponse", func(t *testing.T) {
+		game := newDeploymentTestGame()
+		response := game.ToResponse()
+		if len(response.Settings.DeployableUnits) != 0 || len(response.Settings.DeploymentZones) != 2 {
+			t.Error("Расстановка юнитов сценария не должна попадать в ответ об игре")
+		}
+		if len(game.Settings.DeployableUnits) != 3 {
+			t.Error("Скрытие расстановки в ответе не должно менять настройки игры")
+		}
+	})
+}
@@ -0,0 +1,40 @@
+package services
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// DiceRoller предоставляет броски кубиков и случайный выбор для игровых таблиц
+type DiceRoller interface {
+	// RollD10 возвращает результат броска 1d10 (0-9)
+	RollD10() int
+	// Intn возвращает случайное число в диапазоне [0, n)
+	Intn(n int) int
+}
+
+// randomDice реализует DiceRoller на основе math/rand
+type randomDice struct {
+	mutex sync.Mutex
+	rnd   *rand.Rand
+}
+
+// NewRandomDice создает генератор бросков кубиков
+func NewRandomDice() DiceRoller {
+	return &randomDice{
+		rnd: rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+}
+
+// RollD10 возвращает результат броска 1d10 (0-9)
+func (d *randomDice) RollD10() int {
+	return d.Intn(10)
+}
+
+// Intn возвращает случайное число в диапазоне [0, n)
+func (d *randomDice) Intn(n int) int {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	return d.rnd.Intn(n)
+}
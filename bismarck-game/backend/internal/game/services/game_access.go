@@ -0,0 +1,125 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+
+	"bismarck-game/backend/internal/game/models"
+)
+
+// Ошибки доступа к игре
+var (
+	ErrSpectatorReadOnly = errors.New("spectators have read-only access to this game")
+)
+
+// GameAccess описывает, кем пользователь приходится игре: игроком одной из сторон или зрителем.
+// Все проверки принадлежности юнитов и соединений идут через него, чтобы отказы были одинаковыми
+type GameAccess struct {
+	Game   *models.Game
+	UserID string
+	Side   models.PlayerSide // Пусто у зрителя
+}
+
+// ResolveGameAccess определяет сторону пользователя через Game.GetPlayerRole.
+// Не игрок получает доступ зрителя, только если игра разрешает зрителей
+func ResolveGameAccess(game *models.Game, userID string) (*GameAccess, error) {
+	access := &GameAccess{Game: game, UserID: userID, Side: game.GetPlayerRole(userID)}
+	if access.Side == "" && (userID == "" || !game.Settings.AllowSpectators) {
+		return nil, ErrNotGamePlayer
+	}
+	return access, nil
+}
+
+// IsSpectator проверяет, смотрит ли пользователь игру как зритель
+func (a *GameAccess) IsSpectator() bool {
+	return a.Side == ""
+}
+
+// RequirePlayer проверяет, что пользователь играет в игре и может изменять ее состояние
+func (a *GameAccess) RequirePlayer() error {
+	if a.IsSpectator() {
+		return ErrSpectatorReadOnly
+	}
+	return nil
+}
+
+// CheckNavalUnit проверяет, что игрок может отдавать приказы кораблю
+func (a *GameAccess) CheckNavalUnit(unit *models.NavalUnit) error {
+	return a.checkOwner(unit.GameID, unit.Owner, ErrUnitNotInGame)
+}
+
+// CheckTaskForce проверяет, что игрок может отдавать приказы соединению
+func (a *GameAccess) CheckTaskForce(taskForce *models.TaskForce) error {
+	return a.checkOwner(taskForce.GameID, taskForce.Owner, ErrTaskForceNotInGame)
+}
+
+// checkOwner проверяет игру и сторону владельца объекта
+func (a *GameAccess) checkOwner(gameID, owner string, notInGame error) error {
+	if err := a.RequirePlayer(); err != nil {
+		return err
+	}
+	if gameID != a.Game.ID {
+		return notInGame
+	}
+	if a.Game.GetOwnerSide(owner) != a.Side {
+		return ErrNotOwnUnit
+	}
+	return nil
+}
+
+// IsAccessError проверяет, является ли ошибка отказом в доступе, а не ошибкой запроса
+func IsAccessError(err error) bool {
+	return errors.Is(err, ErrNotGamePlayer) ||
+		errors.Is(err, ErrSpectatorReadOnly) ||
+		errors.Is(err, ErrNotOwnUnit) ||
+		errors.Is(err, ErrUnitNotInGame) ||
+		errors.Is(err, ErrTaskForceNotInGame)
+}
+
+// GetOwnNavalUnit возвращает корабль, которым может командовать игрок
+func (s *UnitService) GetOwnNavalUnit(access *GameAccess, unitID string) (*models.NavalUnit, error) {
+	if err := access.RequirePlayer(); err != nil {
+		return nil, err
+	}
+	unit, err := s.GetNavalUnitByID(unitID)
+	if err != nil {
+		return nil, err
+	}
+	if err := access.CheckNavalUnit(unit); err != nil {
+		return nil, err
+	}
+	return unit, nil
+}
+
+// GetOwnTaskForce возвращает соединение, которым может командовать игрок
+func (s *TaskForceService) GetOwnTaskForce(access *GameAccess, taskForceID string) (*models.TaskForce, error) {
+	if err := access.RequirePlayer(); err != nil {
+		return nil, err
+	}
+	taskForce, err := s.GetTaskForceByID(taskForceID)
+	if err != nil {
+		return nil, err
+	}
+	if err := access.CheckTaskForce(taskForce); err != nil {
+		return nil, err
+	}
+	return taskForce, nil
+}
+
+// GetView возвращает состояние игры для игрока или зрителя
+func (s *ViewService) GetView(access *GameAccess) (*models.PlayerView, error) {
+	if access.IsSpectator() {
+		return s.GetSpectatorView(access.Game)
+	}
+	return s.GetPlayerView(access.Game, access.UserID)
+}
+
+// GetSpectatorView возвращает состояние игры для зрителя: только обнаруженные контакты обеих сторон,
+// без соединений, маркеров и счета конвоев
+func (s *ViewService) GetSpectatorView(game *models.Game) (*models.PlayerView, error) {
+	navalUnits, err := s.unitService.GetNavalUnitsByGameID(game.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get naval units: %w", err)
+	}
+	return models.BuildPlayerView(game, "", navalUnits, nil, nil, nil, nil), nil
+}
@@ -0,0 +1,82 @@
+package services
+
+import (
+	"bismarck-game/backend/internal/game/models"
+	"errors"
+	"testing"
+)
+
+func TestResolveGameAccess(t *testing.T) {
+	game := newVictoryTestGame()
+
+	t.Run("Players", func(t *testing.T) {
+		german, err := ResolveGameAccess(game, "german")
+		if err != nil || german.Side != models.PlayerSideGerman || german.RequirePlayer() != nil {
+			t.Errorf("Первый игрок играет за Германию, получено %v, %v", german, err)
+		}
+		allied, err := ResolveGameAccess(game, "allied")
+		if err != nil || allied.Side != models.PlayerSideAllied {
+			t.Errorf("Второй игрок играет за Союзников, получено %v, %v", allied, err)
+		}
+	})
+
+	t.Run("Spectator", func(t *testing.T) {
+		access, err := ResolveGameAccess(game, "stranger")
+		if err != nil || !access.IsSpectator() {
+			t.Fatalf("Не игрок должен получить доступ зрителя, получено %v, %v", access, err)
+		}
+		if err := access.RequirePlayer(); !errors.Is(err, ErrSpectatorReadOnly) {
+			t.Errorf("Зритель не может изменять игру, получено %v", err)
+		}
+	})
+
+	t.Run("SpectatorsNotAllowed", func(t *testing.T) {
+		closed := newVictoryTestGame()
+		closed.Settings.AllowSpectators = false
+		if _, err := ResolveGameAccess(closed, "stranger"); !errors.Is(err, ErrNotGamePlayer) {
+			t.Errorf("Ожидалась ошибка ErrNotGamePlayer, получено %v", err)
+		}
+	})
+}
+
+func TestGameAccessOwnership(t *testing.T) {
+	game := newVictoryTestGame()
+	german, _ := ResolveGameAccess(game, "german")
+	spectator, _ := ResolveGameAccess(game, "stranger")
+
+	tests := []struct {
+		name   string
+		access *GameAccess
+		unit   models.NavalUnit
+		want   error
+	}{
+		{"OwnUnit", german, models.NavalUnit{GameID: "game", Owner: "german"}, nil},
+		{"OtherSide", german, models.NavalUnit{GameID: "game", Owner: "allied"}, ErrNotOwnUnit},
+		{"OtherGame", german, models.NavalUnit{GameID: "other", Owner: "german"}, ErrUnitNotInGame},
+		{"Spectator", spectator, models.NavalUnit{GameID: "game", Owner: "german"}, ErrSpectatorReadOnly},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.access.CheckNavalUnit(&tt.unit)
+			if !errors.Is(err, tt.want) {
+				t.Errorf("Ожидалась ошибка %v, получено %v", tt.want, err)
+			}
+			if tt.want != nil && !IsAccessError(err) {
+				t.Errorf("Ошибка %v должна считаться отказом в доступе", err)
+			}
+		})
+	}
+
+	t.Run("TaskForce", func(t *testing.T) {
+		taskForce := &models.TaskForce{GameID: "other", Owner: "german"}
+		if err := german.CheckTaskForce(taskForce); !errors.Is(err, ErrTaskForceNotInGame) {
+			t.Errorf("Ожидалась ошибка ErrTaskForceNotInGame, получено %v", err)
+		}
+	})
+
+	t.Run("RequestError", func(t *testing.T) {
+		if IsAccessError(ErrTaskForceEmpty) {
+			t.Error("Ошибка запроса не является отказом в доступе")
+		}
+	})
+}
@@ -0,0 +1,206 @@
+package services
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"bismarck-game/backend/internal/game/models"
+	"bismarck-game/backend/pkg/database"
+	"bismarck-game/backend/pkg/logger"
+)
+
+// GameNotifier рассылает игровые события игрокам (реализуется WebSocket хабом)
+type GameNotifier interface {
+	BroadcastGameEvent(gameID string, eventType string, data interface{})
+	SendNotification(userID string, notification interface{})
+}
+
+// GameService предоставляет методы для работы с состоянием игры и ее завершением
+type GameService struct {
+	db             *database.Database
+	logger         *logger.Logger
+	unitService    *UnitService
+	victoryService *VictoryService
+	notifier       GameNotifier
+}
+
+// NewGameService создает новый сервис игр
+func NewGameService(db *database.Database, logger *logger.Logger, unitService *UnitService, victoryService *VictoryService, notifier GameNotifier) *GameService {
+	return &GameService{
+		db:             db,
+		logger:         logger,
+		unitService:    unitService,
+		victoryService: victoryService,
+		notifier:       notifier,
+	}
+}
+
+// GetGameByID возвращает игру по ID
+func (s *GameService) GetGameByID(gameID string) (*models.Game, error) {
+	query := `
+		SELECT id, name, player1_id, player2_id, current_turn, current_phase, weather, status,
+		       settings, created_at, updated_at, completed_at, winner, victory_type,
+		       started_at, last_action_at
+		FROM games
+		WHERE id = $1`
+
+	var game models.Game
+	var settingsJSON []byte
+	var player1ID, player2ID, winner, victoryType sql.NullString
+	var completedAt, startedAt, lastActionAt sql.NullTime
+
+	err := s.db.QueryRow(query, gameID).Scan(
+		&game.ID, &game.Name, &player1ID, &player2ID,
+		&game.CurrentTurn, &game.CurrentPhase, &game.Weather, &game.Status,
+		&settingsJSON, &game.CreatedAt, &game.UpdatedAt,
+		&completedAt, &winner, &victoryType,
+		&startedAt, &lastActionAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("game not found")
+		}
+		s.logger.Error("Failed to get game", "game_id", gameID, "error", err)
+		return nil, fmt.Errorf("failed to get game: %w", err)
+	}
+
+	// Обрабатываем nullable поля
+	game.Player1ID = player1ID.String
+	game.Player2ID = player2ID.String
+	game.VictoryType = models.VictoryType(victoryType.String)
+	if winner.Valid {
+		game.Winner = &winner.String
+	}
+	if completedAt.Valid {
+		game.CompletedAt = &completedAt.Time
+	}
+	if startedAt.Valid {
+		game.StartedAt = &startedAt.Time
+	}
+	if lastActionAt.Valid {
+		game.LastActionAt = &lastActionAt.Time
+	}
+
+	if err := json.Unmarshal(settingsJSON, &game.Settings); err != nil {
+		return nil, fmt.Errorf("failed to parse game settings: %w", err)
+	}
+
+	return &game, nil
+}
+
+// CheckGameEnd проверяет условия немедленного окончания игры и завершает игру,
+// если одно из них выполнено. Возвращает nil, если игра продолжается.
+// Вызывается после каждого действия, изменяющего состояние, и на границах фаз
+func (s *GameService) CheckGameEnd(gameID string) (*models.VictoryBreakdown, error) {
+	game, err := s.GetGameByID(gameID)
+	if err != nil {
+		return nil, err
+	}
+	if !game.IsActive() {
+		return nil, nil
+	}
+
+	units, err := s.unitService.GetNavalUnitsByGameID(gameID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get units: %w", err)
+	}
+
+	reason, ended := detectGameEnd(game, units)
+	if !ended {
+		return nil, nil
+	}
+
+	return s.CompleteGame(game, reason)
+}
+
+// CompleteGame подсчитывает очки, сохраняет результат игры и уведомляет обоих игроков
+func (s *GameService) CompleteGame(game *models.Game, reason models.GameEndReason) (*models.VictoryBreakdown, error) {
+	breakdown, err := s.victoryService.AdjudicateGame(game, reason)
+	if err != nil {
+		return nil, fmt.Errorf("failed to adjudicate game: %w", err)
+	}
+
+	now := time.Now()
+	game.Status = models.GameStatusCompleted
+	game.CompletedAt = &now
+	game.UpdatedAt = now
+
+	query := `
+		UPDATE games SET
+			status = $2, winner = $3, victory_type = $4, victory_breakdown = $5,
+			completed_at = $6, updated_at = $6, last_action_at = $6
+		WHERE id = $1 AND status = $7`
+
+	breakdownJSON, _ := json.Marshal(breakdown)
+
+	result, err := s.db.Exec(query,
+		game.ID, game.Status, game.Winner, game.VictoryType, breakdownJSON,
+		now, models.GameStatusActive,
+	)
+	if err != nil {
+		s.logger.Error("Failed to complete game", "game_id", game.ID, "error", err)
+		return nil, fmt.Errorf("failed to complete game: %w", err)
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		// Игра уже завершена параллельным запросом
+		return nil, fmt.Errorf("game is not active")
+	}
+
+	s.logger.Info("Game completed", "game_id", game.ID, "end_reason", reason, "victory_type", game.VictoryType)
+
+	s.notifyGameCompleted(game, breakdown)
+	return breakdown, nil
+}
+
+// notifyGameCompleted уведомляет обоих игроков о завершении игры
+func (s *GameService) notifyGameCompleted(game *models.Game, breakdown *models.VictoryBreakdown) {
+	if s.notifier == nil {
+		return
+	}
+
+	data := map[string]interface{}{
+		"end_reason":   breakdown.EndReason,
+		"winner":       game.Winner,
+		"victory_type": game.VictoryType,
+		"breakdown":    breakdown,
+	}
+
+	s.notifier.BroadcastGameEvent(game.ID, "game_completed", data)
+	for _, playerID := range []string{game.Player1ID, game.Player2ID} {
+		if playerID != "" {
+			s.notifier.SendNotification(playerID, map[string]interface{}{
+				"type":    "game_completed",
+				"game_id": game.ID,
+				"data":    data,
+			})
+		}
+	}
+}
+
+// detectGameEnd проверяет условия немедленного окончания игры для "Бисмарка"
+func detectGameEnd(game *models.Game, units []models.NavalUnit) (models.GameEndReason, bool) {
+	cfg := game.Settings.VictoryConditions
+
+	for i := range units {
+		unit := &units[i]
+		if !unit.IsBismarck() {
+			continue
+		}
+
+		switch {
+		case !unit.IsAlive():
+			return models.GameEndBismarckSunk, true
+		case cfg.IsFrancePort(unit.Position):
+			return models.GameEndBismarckFrance, true
+		case cfg.IsNorwayPort(unit.Position):
+			return models.GameEndBismarckNorway, true
+		case unit.IsEmergencyFuelExpired(game.CurrentTurn):
+			return models.GameEndBismarckNoFuel, true
+		}
+		return "", false
+	}
+
+	return "", false
+}
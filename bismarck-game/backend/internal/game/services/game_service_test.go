@@ -0,0 +1,85 @@
+package services
+
+import (
+	"bismarck-game/backend/internal/game/models"
+	"testing"
+)
+
+func TestDetectGameEnd(t *testing.T) {
+	newBismarck := func() models.NavalUnit {
+		return models.NavalUnit{
+			ID: "bismarck", Name: "BISMARCK", Type: models.UnitTypeBattleship, Owner: "german",
+			HullBoxes: 12, CurrentHull: 12, Fuel: 12, Position: "K10", Status: models.UnitStatusActive,
+		}
+	}
+
+	t.Run("GameContinues", func(t *testing.T) {
+		game := newVictoryTestGame()
+		if _, ended := detectGameEnd(game, []models.NavalUnit{newBismarck()}); ended {
+			t.Error("Игра не должна заканчиваться")
+		}
+	})
+
+	t.Run("BismarckSunk", func(t *testing.T) {
+		game := newVictoryTestGame()
+		unit := newBismarck()
+		unit.CurrentHull = 0
+		unit.Status = models.UnitStatusSunk
+
+		reason, ended := detectGameEnd(game, []models.NavalUnit{unit})
+		if !ended || reason != models.GameEndBismarckSunk {
+			t.Errorf("Ожидалось окончание из-за потопления Бисмарка, получено %s", reason)
+		}
+	})
+
+	t.Run("BismarckInPort", func(t *testing.T) {
+		game := newVictoryTestGame()
+		game.Settings.VictoryConditions.FrancePortHexes = []string{"K10"}
+
+		reason, ended := detectGameEnd(game, []models.NavalUnit{newBismarck()})
+		if !ended || reason != models.GameEndBismarckFrance {
+			t.Errorf("Ожидалось окончание из-за прибытия во Францию, получено %s", reason)
+		}
+	})
+
+	t.Run("EmergencyFuelExpired", func(t *testing.T) {
+		game := newVictoryTestGame()
+		unit := newBismarck()
+		unit.Fuel = 0
+		unit.StartEmergencyFuel(5)
+
+		game.CurrentTurn = 14
+		if _, ended := detectGameEnd(game, []models.NavalUnit{unit}); ended {
+			t.Error("Аварийное топливо еще не исчерпано")
+		}
+
+		game.CurrentTurn = 15
+		reason, ended := detectGameEnd(game, []models.NavalUnit{unit})
+		if !ended || reason != models.GameEndBismarckNoFuel {
+			t.Errorf("Ожидалось окончание из-за аварийного топлива, получено %s", reason)
+		}
+	})
+}
+
+func TestNextPhase(t *testing.T) {
+	tests := []struct {
+		turn      int
+		phase     models.GamePhase
+		wantTurn  int
+		wantPhase models.GamePhase
+	}{
+		{0, models.PhaseWaiting, 1, models.PhaseMovement},
+		{1, models.PhaseMovement, 1, models.PhaseSearch},
+		{1, models.PhaseAdmin, 2, models.PhaseVisibility},
+		{2, models.PhaseVisibility, 2, models.PhaseShadow},
+		{2, models.PhaseChance, 2, models.PhaseAdmin},
+	}
+
+	for _, tt := range tests {
+		turn, phase := nextPhase(tt.turn, tt.phase)
+		if turn != tt.wantTurn || phase != tt.wantPhase {
+			t.Errorf("nextPhase(%d, %s) = %d, %s; ожидалось %d, %s",
+				tt.turn, tt.phase, turn, phase, tt.wantTurn, tt.wantPhase)
+		}
+	}
+}
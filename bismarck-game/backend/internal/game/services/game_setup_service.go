@@ -0,0 +1,152 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"bismarck-game/backend/internal/config"
+	"bismarck-game/backend/internal/game/models"
+	"bismarck-game/backend/pkg/database"
+	"bismarck-game/backend/pkg/logger"
+)
+
+// GameSetupService начинает игру, когда к ней присоединяется второй игрок
+type GameSetupService struct {
+	db              *database.Database
+	logger          *logger.Logger
+	scenarioService *ScenarioService
+	convoyService   *ConvoyVPService
+	specialRules    *SpecialRulesService
+	viewService     *ViewService
+	notifier        GameNotifier
+}
+
+// NewGameSetupService создает новый сервис начала игры
+func NewGameSetupService(db *database.Database, logger *logger.Logger, scenarioService *ScenarioService, convoyService *ConvoyVPService, specialRules *SpecialRulesService, viewService *ViewService, notifier GameNotifier) *GameSetupService {
+	return &GameSetupService{
+		db:              db,
+		logger:          logger,
+		scenarioService: scenarioService,
+		convoyService:   convoyService,
+		specialRules:    specialRules,
+		viewService:     viewService,
+		notifier:        notifier,
+	}
+}
+
+// Ошибки начала игры
+var (
+	ErrGameNotJoinable = errors.New("game is not available for joining")
+)
+
+// GetScenario возвращает сценарий по ID; пустой ID означает сценарий по умолчанию
+func (s *GameSetupService) GetScenario(id string) (*config.ScenarioConfig, error) {
+	return s.scenarioService.GetScenario(id)
+}
+
+// JoinGame присоединяет игрока к свободной стороне и начинает игру в рамках одной транзакции:
+// расставляет юниты и соединения сценария, создает секретную коробку VP конвоя,
+// устанавливает погоду, Трек хода и первую фазу,
+// затем загружает специальные правила юнитов игры.
+// После начала игры каждый игрок получает свое представление игры
+func (s *GameSetupService) JoinGame(game *models.Game, userID string) error {
+	scenario, err := s.scenarioService.GetScenario(game.Settings.Scenario)
+	if err != nil {
+		return fmt.Errorf("failed to get scenario %q: %w", game.Settings.Scenario, err)
+	}
+
+	tx, err := s.db.BeginTx()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Блокируем игру: два игрока не должны занять одну сторону
+	var status models.GameStatus
+	var player1ID, player2ID *string
+	query := `SELECT status, player1_id, player2_id FROM games WHERE id = $1 FOR UPDATE`
+	if err := tx.QueryRow(query, game.ID).Scan(&status, &player1ID, &player2ID); err != nil {
+		return fmt.Errorf("failed to lock game: %w", err)
+	}
+	if status != models.GameStatusWaiting || (player1ID != nil && player2ID != nil) {
+		return ErrGameNotJoinable
+	}
+
+	if player1ID == nil {
+		game.Player1ID = userID
+	} else {
+		game.Player2ID = userID
+	}
+	startGame(game, scenario, time.Now())
+
+	if err := s.scenarioService.InstantiateScenarioTx(tx, game, scenario); err != nil {
+		return fmt.Errorf("failed to instantiate scenario: %w", err)
+	}
+	if _, err := s.convoyService.InitializeBoxTx(tx, game); err != nil {
+		return err
+	}
+
+	settingsJSON, err := json.Marshal(game.Settings)
+	if err != nil {
+		return fmt.Errorf("failed to marshal game settings: %w", err)
+	}
+
+	query = `
+		UPDATE games SET
+			player1_id = $2, player2_id = $3, status = $4,
+			current_turn = $5, current_phase = $6, weather = $7, settings = $8,
+			started_at = $9, updated_at = $9, last_action_at = $9
+		WHERE id = $1`
+
+	_, err = tx.Exec(query,
+		game.ID, game.Player1ID, game.Player2ID, game.Status,
+		game.CurrentTurn, game.CurrentPhase, game.Weather, settingsJSON,
+		game.StartedAt,
+	)
+	if err != nil {
+		s.logger.Error("Failed to start game", "game_id", game.ID, "error", err)
+		return fmt.Errorf("failed to start game: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit game start: %w", err)
+	}
+
+	// Специальные правила кораблей сценария сохранены по ID юнитов
+	if err := s.specialRules.LoadGameRules(game.ID); err != nil {
+		return err
+	}
+
+	s.logger.Info("Game started", "game_id", game.ID, "scenario", scenario.ID, "turn", game.CurrentTurn, "phase", game.CurrentPhase)
+
+	if s.notifier != nil {
+		s.notifier.BroadcastGameEvent(game.ID, "game_started", map[string]interface{}{
+			"scenario": scenario.ID,
+			"turn":     game.CurrentTurn,
+			"phase":    game.CurrentPhase,
+		})
+	}
+	s.viewService.PushPlayerViews(game)
+
+	return nil
+}
+
+// startGame переводит игру в активное состояние по Треку хода сценария.
+// Если в игре используются гипотетические юниты, игра остается в подготовке до их покупки;
+// иначе она начинается с фазы развертывания или с первой фазы первого хода
+func startGame(game *models.Game, scenario *config.ScenarioConfig, now time.Time) {
+	applyScenarioSettings(&game.Settings, scenario)
+
+	game.Status = models.GameStatusActive
+	game.Weather = scenario.TurnTrack.StartWeather
+	game.CurrentTurn = scenario.TurnTrack.FirstTurn
+	game.CurrentPhase = models.PhaseWaiting
+	if !game.Settings.UseOptionalUnits {
+		game.CurrentTurn, game.CurrentPhase = nextGamePhase(game)
+	}
+	game.StartedAt = &now
+	game.UpdatedAt = now
+	game.LastActionAt = &now
+}
@@ -0,0 +1,71 @@
+package services
+
+import (
+	"bismarck-game/backend/internal/config"
+	"bismarck-game/backend/internal/game/models"
+	"testing"
+	"time"
+)
+
+func TestStartGame(t *testing.T) {
+	_, scenario := newScenarioTestService(t)
+
+	newGame := func() *models.Game {
+		return &models.Game{
+			ID:           "game-1",
+			Player1ID:    "german",
+			Player2ID:    "allied",
+			CurrentTurn:  1,
+			CurrentPhase: models.PhaseWaiting,
+			Status:       models.GameStatusWaiting,
+			Settings:     models.GetDefaultGameSettings(),
+		}
+	}
+
+	t.Run("FirstPhase", func(t *testing.T) {
+		game := newGame()
+		now := time.Now()
+		startGame(game, scenario, now)
+
+		if !game.IsActive() {
+			t.Error("Игра должна стать активной")
+		}
+		if game.CurrentTurn != scenario.TurnTrack.FirstTurn || game.CurrentPhase != models.PhaseMovement {
+			t.Errorf("Ожидалась Фаза движения хода %d, получено %d, %s", scenario.TurnTrack.FirstTurn, game.CurrentTurn, game.CurrentPhase)
+		}
+		if game.Weather != scenario.TurnTrack.StartWeather {
+			t.Errorf("Ожидалась погода %d, получено %d", scenario.TurnTrack.StartWeather, game.Weather)
+		}
+		if game.StartedAt == nil || !game.StartedAt.Equal(now) {
+			t.Error("Должно быть установлено время начала игры")
+		}
+		if game.Settings.Scenario != scenario.ID || game.Settings.GetMaxTurns() != scenario.TurnTrack.LastTurn {
+			t.Error("Трек хода сценария должен попасть в настройки игры")
+		}
+	})
+
+	t.Run("OptionalUnits", func(t *testing.T) {
+		game := newGame()
+		game.Settings.UseOptionalUnits = true
+		startGame(game, scenario, time.Now())
+
+		if !game.IsActive() || !game.IsPreGame() {
+			t.Error("С гипотетическими юнитами игра должна остаться в подготовке до покупки")
+		}
+	})
+
+	t.Run("Deployment", func(t *testing.T) {
+		variant := *scenario
+		variant.Allied.DeploymentZones = []config.ScenarioDeploymentZone{{ID: "iceland", Hexes: []string{"L18", "M18"}}}
+		game := newGame()
+		startGame(game, &variant, time.Now())
+
+		if game.CurrentPhase != models.PhaseDeployment {
+			t.Errorf("С зонами развертывания ожидалась фаза развертывания, получено %s", game.CurrentPhase)
+		}
+		zone, ok := game.Settings.FindDeploymentZone("iceland")
+		if !ok || zone.Side != models.PlayerSideAllied {
+			t.Error("Зоны развертывания сценария должны попасть в настройки игры со своей стороной")
+		}
+	})
+}
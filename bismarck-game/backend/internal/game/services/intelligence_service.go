@@ -0,0 +1,276 @@
+package services
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"bismarck-game/backend/internal/game/models"
+	"bismarck-game/backend/pkg/database"
+	"bismarck-game/backend/pkg/logger"
+)
+
+// Ошибки журнала контактов
+var (
+	ErrInvalidContact        = errors.New("invalid contact report")
+	ErrContactTargetNotFound = errors.New("contact target not found")
+)
+
+// IntelligenceService ведет журнал контактов каждой стороны с кораблями противника
+// и поддерживает последние известные позиции кораблей
+type IntelligenceService struct {
+	db          *database.Database
+	logger      *logger.Logger
+	unitService *UnitService
+	notifier    GameNotifier
+}
+
+// NewIntelligenceService создает новый сервис разведки
+func NewIntelligenceService(db *database.Database, logger *logger.Logger, unitService *UnitService, notifier GameNotifier) *IntelligenceService {
+	return &IntelligenceService{
+		db:          db,
+		logger:      logger,
+		unitService: unitService,
+		notifier:    notifier,
+	}
+}
+
+// contactReportColumns список колонок донесения в порядке сканирования
+const contactReportColumns = `id, game_id, side, unit_id, task_force_id, turn, phase, hex,
+			   class, count, source, detection_level, created_at`
+
+// RecordContact записывает донесение о контакте и сообщает о нем получившей его стороне
+func (s *IntelligenceService) RecordContact(game *models.Game, report *models.ContactReport) error {
+	tx, err := s.db.BeginTx()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := s.RecordContactTx(tx, game, report); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit contact report: %w", err)
+	}
+
+	if s.notifier != nil {
+		if playerID := game.GetPlayerIDBySide(report.Side); playerID != "" {
+			s.notifier.SendNotification(playerID, map[string]interface{}{
+				"type":    "contact_reported",
+				"game_id": game.ID,
+				"data":    report,
+			})
+		}
+	}
+
+	return nil
+}
+
+// RecordContactTx записывает донесение о контакте в рамках транзакции.
+// Обнаруженные корабли получают уровень обнаружения и последнюю известную позицию из донесения
+func (s *IntelligenceService) RecordContactTx(tx *sql.Tx, game *models.Game, report *models.ContactReport) error {
+	units, err := s.unitService.GetNavalUnitsByGameID(game.ID)
+	if err != nil {
+		return fmt.Errorf("failed to get naval units: %w", err)
+	}
+
+	report.GameID = game.ID
+	report.Turn = game.CurrentTurn
+	report.Phase = game.CurrentPhase
+	targets, err := validateContactReport(game, report, units)
+	if err != nil {
+		return err
+	}
+
+	if err := s.LogContactTx(tx, report); err != nil {
+		return err
+	}
+
+	for i := range targets {
+		targets[i].ApplyContact(report)
+		if err := s.unitService.UpdateNavalUnitTx(tx, &targets[i]); err != nil {
+			return err
+		}
+	}
+
+	s.logger.Info("Contact recorded", "game_id", game.ID, "side", report.Side, "target", report.TargetID(), "source", report.Source)
+	return nil
+}
+
+// LogContactTx добавляет донесение в журнал контактов, не изменяя корабли.
+// Используется, когда уровень обнаружения кораблей уже обновлен вызывающим
+func (s *IntelligenceService) LogContactTx(tx *sql.Tx, report *models.ContactReport) error {
+	query := `
+		INSERT INTO contact_reports (
+			game_id, side, unit_id, task_force_id, turn, phase, hex,
+			class, count, source, detection_level
+		) VALUES (
+			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11
+		) RETURNING id, created_at`
+
+	err := tx.QueryRow(query,
+		report.GameID, report.Side, report.UnitID, report.TaskForceID, report.Turn, report.Phase, report.Hex,
+		report.Class, report.Count, report.Source, report.DetectionLevel,
+	).Scan(&report.ID, &report.CreatedAt)
+	if err != nil {
+		s.logger.Error("Failed to record contact", "game_id", report.GameID, "error", err)
+		return fmt.Errorf("failed to record contact: %w", err)
+	}
+
+	return nil
+}
+
+// GetIntelligence возвращает сведения игрока о кораблях противника
+func (s *IntelligenceService) GetIntelligence(game *models.Game, userID string) ([]models.IntelligenceContact, error) {
+	side := game.GetPlayerRole(userID)
+	if side == "" {
+		return nil, ErrNotGamePlayer
+	}
+
+	query := `
+		SELECT ` + contactReportColumns + `
+		FROM contact_reports
+		WHERE game_id = $1 AND side = $2
+		ORDER BY turn, created_at`
+
+	rows, err := s.db.Query(query, game.ID, side)
+	if err != nil {
+		s.logger.Error("Failed to get contact reports", "game_id", game.ID, "error", err)
+		return nil, fmt.Errorf("failed to get contact reports: %w", err)
+	}
+	defer rows.Close()
+
+	reports := []models.ContactReport{}
+	for rows.Next() {
+		report, err := scanContactReport(rows)
+		if err != nil {
+			s.logger.Error("Failed to scan contact report", "error", err)
+			continue
+		}
+		reports = append(reports, *report)
+	}
+
+	return models.BuildIntelligence(reports, game.CurrentTurn), nil
+}
+
+// GetLastContactTurns возвращает ход последнего донесения о каждом корабле и Task Force игры
+func (s *IntelligenceService) GetLastContactTurns(tx *sql.Tx, gameID string) (map[string]int, error) {
+	query := `
+		SELECT COALESCE(task_force_id, unit_id), MAX(turn)
+		FROM contact_reports
+		WHERE game_id = $1
+		GROUP BY COALESCE(task_force_id, unit_id)`
+
+	rows, err := tx.Query(query, gameID)
+	if err != nil {
+		s.logger.Error("Failed to get last contact turns", "game_id", gameID, "error", err)
+		return nil, fmt.Errorf("failed to get last contact turns: %w", err)
+	}
+	defer rows.Close()
+
+	lastReports := make(map[string]int)
+	for rows.Next() {
+		var targetID string
+		var turn int
+		if err := rows.Scan(&targetID, &turn); err != nil {
+			return nil, fmt.Errorf("failed to scan last contact turn: %w", err)
+		}
+		lastReports[targetID] = turn
+	}
+
+	return lastReports, rows.Err()
+}
+
+// loseStaleContacts переводит в потерянные контакты, не обновленные на ходу turn.
+// Последнее донесение о корабле - более позднее из донесений о нем и о его Task Force.
+// Возвращает измененные корабли
+func loseStaleContacts(units []models.NavalUnit, lastReports map[string]int, turn int) []*models.NavalUnit {
+	lost := []*models.NavalUnit{}
+	for i := range units {
+		unit := &units[i]
+		lastTurn := lastReports[unit.ID]
+		if unit.TaskForceID != nil && lastReports[*unit.TaskForceID] > lastTurn {
+			lastTurn = lastReports[*unit.TaskForceID]
+		}
+		if unit.LoseContact(lastTurn, turn) {
+			lost = append(lost, unit)
+		}
+	}
+	return lost
+}
+
+// validateContactReport проверяет донесение, заполняет значения по умолчанию
+// и возвращает обнаруженные корабли
+func validateContactReport(game *models.Game, report *models.ContactReport, units []models.NavalUnit) ([]models.NavalUnit, error) {
+	if report.Side != models.PlayerSideGerman && report.Side != models.PlayerSideAllied {
+		return nil, fmt.Errorf("%w: unknown side %q", ErrInvalidContact, report.Side)
+	}
+	if !report.Source.IsValid() {
+		return nil, fmt.Errorf("%w: unknown source %q", ErrInvalidContact, report.Source)
+	}
+	if report.Hex == "" {
+		return nil, fmt.Errorf("%w: hex is required", ErrInvalidContact)
+	}
+	switch report.DetectionLevel {
+	case "":
+		report.DetectionLevel = models.DetectionLevelSighted
+	case models.DetectionLevelSighted, models.DetectionLevelShadowed:
+	default:
+		return nil, fmt.Errorf("%w: detection level %q", ErrInvalidContact, report.DetectionLevel)
+	}
+
+	targetID := report.TargetID()
+	if targetID == "" {
+		return nil, fmt.Errorf("%w: unit or task force is required", ErrInvalidContact)
+	}
+
+	targets := []models.NavalUnit{}
+	for _, unit := range units {
+		inTaskForce := report.TaskForceID != nil && unit.TaskForceID != nil && *unit.TaskForceID == *report.TaskForceID
+		if unit.ID != targetID && !inTaskForce {
+			continue
+		}
+		// Донесения бывают только о кораблях противника
+		if game.GetOwnerSide(unit.Owner) != report.Side.Opponent() {
+			return nil, fmt.Errorf("%w: %s is not an enemy unit", ErrInvalidContact, unit.ID)
+		}
+		targets = append(targets, unit)
+	}
+	if len(targets) == 0 {
+		return nil, ErrContactTargetNotFound
+	}
+
+	if report.Count <= 0 {
+		report.Count = len(targets)
+	}
+	if report.Class == "" && len(targets) == 1 {
+		report.Class = targets[0].Class
+	}
+
+	return targets, nil
+}
+
+// scanContactReport сканирует строку донесения о контакте
+func scanContactReport(row rowScanner) (*models.ContactReport, error) {
+	var report models.ContactReport
+	var unitID, taskForceID, class sql.NullString
+
+	err := row.Scan(
+		&report.ID, &report.GameID, &report.Side, &unitID, &taskForceID, &report.Turn, &report.Phase,
+		&report.Hex, &class, &report.Count, &report.Source, &report.DetectionLevel, &report.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	report.Class = class.String
+	if unitID.Valid {
+		report.UnitID = &unitID.String
+	}
+	if taskForceID.Valid {
+		report.TaskForceID = &taskForceID.String
+	}
+
+	return &report, nil
+}
@@ -0,0 +1,108 @@
+package services
+
+import (
+	"bismarck-game/backend/internal/game/models"
+	"errors"
+	"testing"
+)
+
+func newIntelligenceTestUnits() []models.NavalUnit {
+	tf := "force-h"
+	return []models.NavalUnit{
+		{ID: "bismarck", Name: "BISMARCK", Class: "Bismarck", Owner: "german", Position: "AB20", Status: models.UnitStatusActive},
+		{ID: "renown", Name: "RENOWN", Class: "Renown", Owner: "allied", Position: "AE15", Status: models.UnitStatusActive, TaskForceID: &tf},
+		{ID: "ark-royal", Name: "ARK ROYAL", Class: "Ark Royal", Owner: "allied", Position: "AE15", Status: models.UnitStatusActive, TaskForceID: &tf},
+	}
+}
+
+func TestValidateContactReport(t *testing.T) {
+	game := newVictoryTestGame()
+	units := newIntelligenceTestUnits()
+
+	t.Run("TaskForce", func(t *testing.T) {
+		tf := "force-h"
+		report := &models.ContactReport{Side: models.PlayerSideGerman, TaskForceID: &tf, Hex: "AE15", Source: models.ContactSourceSubmarine}
+
+		targets, err := validateContactReport(game, report, units)
+		if err != nil {
+			t.Fatalf("Донесение о Task Force должно быть принято: %v", err)
+		}
+		if len(targets) != 2 || report.Count != 2 {
+			t.Errorf("Ожидалось 2 обнаруженных корабля, получено %d (count %d)", len(targets), report.Count)
+		}
+		if report.DetectionLevel != models.DetectionLevelSighted {
+			t.Errorf("Уровень обнаружения по умолчанию - sighted, получено %s", report.DetectionLevel)
+		}
+	})
+
+	t.Run("OwnUnit", func(t *testing.T) {
+		unitID := "bismarck"
+		report := &models.ContactReport{Side: models.PlayerSideGerman, UnitID: &unitID, Hex: "AB20", Source: models.ContactSourceSearch}
+
+		if _, err := validateContactReport(game, report, units); !errors.Is(err, ErrInvalidContact) {
+			t.Errorf("Донесение о своем корабле должно быть отклонено, получено %v", err)
+		}
+	})
+
+	t.Run("UnknownSource", func(t *testing.T) {
+		unitID := "bismarck"
+		report := &models.ContactReport{Side: models.PlayerSideAllied, UnitID: &unitID, Hex: "AB20", Source: "radio"}
+
+		if _, err := validateContactReport(game, report, units); !errors.Is(err, ErrInvalidContact) {
+			t.Errorf("Неизвестный источник должен быть отклонен, получено %v", err)
+		}
+	})
+
+	t.Run("SingleUnitClass", func(t *testing.T) {
+		unitID := "bismarck"
+		report := &models.ContactReport{Side: models.PlayerSideAllied, UnitID: &unitID, Hex: "AB19", Source: models.ContactSourceRandomSpot}
+
+		targets, err := validateContactReport(game, report, units)
+		if err != nil {
+			t.Fatalf("Донесение должно быть принято: %v", err)
+		}
+		targets[0].ApplyContact(report)
+		if report.Class != "Bismarck" || *targets[0].LastKnownPos != "AB19" {
+			t.Error("Корабль должен получить последнюю известную позицию из донесения")
+		}
+	})
+}
+
+func TestContactAging(t *testing.T) {
+	units := newIntelligenceTestUnits()
+	for i := range units {
+		units[i].DetectionLevel = models.DetectionLevelSighted
+	}
+
+	// О BISMARCK донесение на 4-м ходу, о Force H - на 5-м
+	lastReports := map[string]int{"bismarck": 4, "force-h": 5}
+	lost := loseStaleContacts(units, lastReports, 5)
+
+	if len(lost) != 1 || lost[0].ID != "bismarck" {
+		t.Fatalf("В Фазе администрирования 5-го хода должен быть потерян только BISMARCK, получено %d", len(lost))
+	}
+	if units[0].DetectionLevel != models.DetectionLevelLost || units[1].DetectionLevel != models.DetectionLevelSighted {
+		t.Error("Контакт, обновленный на текущем ходу, не теряется")
+	}
+
+	t.Run("Intelligence", func(t *testing.T) {
+		unitID := "bismarck"
+		reports := []models.ContactReport{
+			{Side: models.PlayerSideAllied, UnitID: &unitID, Turn: 3, Hex: "AA10", Source: models.ContactSourceSearch, DetectionLevel: models.DetectionLevelSighted},
+			{Side: models.PlayerSideAllied, UnitID: &unitID, Turn: 4, Hex: "AB14", Source: models.ContactSourceShadow, DetectionLevel: models.DetectionLevelShadowed},
+		}
+
+		current := models.BuildIntelligence(reports, 5)
+		if len(current) != 1 || len(current[0].History) != 2 {
+			t.Fatalf("Ожидался один контакт с историей из 2 донесений, получено %d", len(current))
+		}
+		if current[0].LastKnownPos != "AB14" || current[0].DetectionLevel != models.DetectionLevelShadowed {
+			t.Errorf("Контакт должен показывать последнее донесение, получено %s/%s", current[0].LastKnownPos, current[0].DetectionLevel)
+		}
+
+		aged := models.BuildIntelligence(reports, 6)
+		if aged[0].DetectionLevel != models.DetectionLevelLost {
+			t.Errorf("Необновленный контакт должен стать потерянным, получено %s", aged[0].DetectionLevel)
+		}
+	})
+}
@@ -0,0 +1,244 @@
+package services
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"bismarck-game/backend/internal/game/models"
+	"bismarck-game/backend/pkg/database"
+	"bismarck-game/backend/pkg/logger"
+)
+
+// MarkerService предоставляет методы для работы с игровыми маркерами, общие для всех фаз
+type MarkerService struct {
+	db     *database.Database
+	logger *logger.Logger
+}
+
+// NewMarkerService создает новый сервис маркеров
+func NewMarkerService(db *database.Database, logger *logger.Logger) *MarkerService {
+	return &MarkerService{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// querier выполняет запросы как в базе данных, так и в транзакции
+type querier interface {
+	Query(query string, args ...interface{}) (*sql.Rows, error)
+	QueryRow(query string, args ...interface{}) *sql.Row
+	Exec(query string, args ...interface{}) (sql.Result, error)
+}
+
+// markerColumns список колонок маркера в порядке сканирования
+const markerColumns = `id, game_id, type, owner, unit_id, hex, value, visible_to,
+			   expiry, created_turn, created_phase, created_at, updated_at`
+
+// AddMarker размещает маркер
+func (s *MarkerService) AddMarker(marker *models.Marker) error {
+	return s.addMarker(s.db, marker)
+}
+
+// AddMarkerTx размещает маркер в рамках транзакции
+func (s *MarkerService) AddMarkerTx(tx *sql.Tx, marker *models.Marker) error {
+	return s.addMarker(tx, marker)
+}
+
+// addMarker сохраняет новый маркер
+func (s *MarkerService) addMarker(q querier, marker *models.Marker) error {
+	if marker.Expiry == "" {
+		marker.Expiry = models.DefaultMarkerExpiry(marker.Type)
+	}
+	if len(marker.VisibleTo) == 0 {
+		marker.VisibleTo = models.DefaultMarkerVisibility(marker.Type, marker.Owner)
+	}
+
+	query := `
+		INSERT INTO game_markers (
+			game_id, type, owner, unit_id, hex, value, visible_to,
+			expiry, created_turn, created_phase
+		) VALUES (
+			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10
+		) RETURNING id, created_at, updated_at`
+
+	visibleToJSON, _ := json.Marshal(marker.VisibleTo)
+
+	err := q.QueryRow(query,
+		marker.GameID, marker.Type, marker.Owner, marker.UnitID, marker.Hex, marker.Value,
+		visibleToJSON, marker.Expiry, marker.CreatedTurn, marker.CreatedPhase,
+	).Scan(&marker.ID, &marker.CreatedAt, &marker.UpdatedAt)
+	if err != nil {
+		s.logger.Error("Failed to add marker", "game_id", marker.GameID, "type", marker.Type, "error", err)
+		return fmt.Errorf("failed to add marker: %w", err)
+	}
+
+	return nil
+}
+
+// GetMarkers возвращает маркеры игры, подходящие под фильтр
+func (s *MarkerService) GetMarkers(gameID string, filter models.MarkerFilter) ([]models.Marker, error) {
+	return s.getMarkers(s.db, gameID, filter)
+}
+
+// GetMarkersForSide возвращает маркеры, которые видит сторона
+func (s *MarkerService) GetMarkersForSide(gameID string, side models.PlayerSide) ([]models.Marker, error) {
+	return s.getMarkers(s.db, gameID, models.MarkerFilter{VisibleTo: side})
+}
+
+// getMarkers загружает маркеры игры и применяет фильтр
+func (s *MarkerService) getMarkers(q querier, gameID string, filter models.MarkerFilter) ([]models.Marker, error) {
+	query := `
+		SELECT ` + markerColumns + `
+		FROM game_markers
+		WHERE game_id = $1
+		ORDER BY created_at`
+
+	rows, err := q.Query(query, gameID)
+	if err != nil {
+		s.logger.Error("Failed to get markers", "game_id", gameID, "error", err)
+		return nil, fmt.Errorf("failed to get markers: %w", err)
+	}
+	defer rows.Close()
+
+	markers := []models.Marker{}
+	for rows.Next() {
+		marker, err := scanMarker(rows)
+		if err != nil {
+			s.logger.Error("Failed to scan marker", "error", err)
+			continue
+		}
+		if filter.Matches(marker) {
+			markers = append(markers, *marker)
+		}
+	}
+
+	return markers, nil
+}
+
+// FlipMarker переворачивает маркер с обратным отсчетом и убирает его, когда отсчет закончился.
+// Возвращает true, если маркер убран
+func (s *MarkerService) FlipMarker(markerID string) (bool, error) {
+	marker, err := scanMarker(s.db.QueryRow(`SELECT `+markerColumns+` FROM game_markers WHERE id = $1`, markerID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return false, fmt.Errorf("marker not found")
+		}
+		return false, fmt.Errorf("failed to get marker: %w", err)
+	}
+
+	if marker.Flip() {
+		return true, s.RemoveMarker(markerID)
+	}
+	return false, s.updateMarkerValue(s.db, marker)
+}
+
+// RemoveMarker убирает маркер
+func (s *MarkerService) RemoveMarker(markerID string) error {
+	return s.removeMarker(s.db, markerID)
+}
+
+// RemoveMarkers убирает маркеры игры, подходящие под фильтр. Возвращает количество убранных маркеров
+func (s *MarkerService) RemoveMarkers(gameID string, filter models.MarkerFilter) (int, error) {
+	markers, err := s.GetMarkers(gameID, filter)
+	if err != nil {
+		return 0, err
+	}
+	for _, marker := range markers {
+		if err := s.RemoveMarker(marker.ID); err != nil {
+			return 0, err
+		}
+	}
+	return len(markers), nil
+}
+
+// ExpireAdminPhaseMarkers убирает маркеры, снимаемые в Фазе администрирования,
+// и переворачивает маркеры с обратным отсчетом. Возвращает измененные маркеры
+func (s *MarkerService) ExpireAdminPhaseMarkers(tx *sql.Tx, gameID string) ([]models.Marker, error) {
+	markers, err := s.getMarkers(tx, gameID, models.MarkerFilter{})
+	if err != nil {
+		return nil, err
+	}
+
+	changed := []models.Marker{}
+	for i := range markers {
+		marker := &markers[i]
+		switch {
+		case marker.ExpiresInAdminPhase():
+			err = s.removeMarker(tx, marker.ID)
+		case marker.Expiry == models.MarkerExpiryCountdown:
+			if marker.Flip() {
+				err = s.removeMarker(tx, marker.ID)
+			} else {
+				err = s.updateMarkerValue(tx, marker)
+			}
+		default:
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		changed = append(changed, *marker)
+	}
+
+	return changed, nil
+}
+
+// ExpirePhaseMarkers убирает маркеры, размещенные на время указанной фазы
+func (s *MarkerService) ExpirePhaseMarkers(tx *sql.Tx, gameID string, turn int, phase models.GamePhase) (int, error) {
+	query := `
+		DELETE FROM game_markers
+		WHERE game_id = $1 AND expiry = $2 AND created_turn = $3 AND created_phase = $4`
+
+	result, err := tx.Exec(query, gameID, models.MarkerExpiryEndOfPhase, turn, phase)
+	if err != nil {
+		s.logger.Error("Failed to expire phase markers", "game_id", gameID, "error", err)
+		return 0, fmt.Errorf("failed to expire phase markers: %w", err)
+	}
+
+	removed, _ := result.RowsAffected()
+	return int(removed), nil
+}
+
+// removeMarker удаляет маркер
+func (s *MarkerService) removeMarker(q querier, markerID string) error {
+	if _, err := q.Exec(`DELETE FROM game_markers WHERE id = $1`, markerID); err != nil {
+		s.logger.Error("Failed to remove marker", "marker_id", markerID, "error", err)
+		return fmt.Errorf("failed to remove marker: %w", err)
+	}
+	return nil
+}
+
+// updateMarkerValue сохраняет значение маркера
+func (s *MarkerService) updateMarkerValue(q querier, marker *models.Marker) error {
+	query := `UPDATE game_markers SET value = $2, updated_at = CURRENT_TIMESTAMP WHERE id = $1`
+	if _, err := q.Exec(query, marker.ID, marker.Value); err != nil {
+		s.logger.Error("Failed to update marker", "marker_id", marker.ID, "error", err)
+		return fmt.Errorf("failed to update marker: %w", err)
+	}
+	return nil
+}
+
+// scanMarker сканирует строку маркера
+func scanMarker(row rowScanner) (*models.Marker, error) {
+	var marker models.Marker
+	var unitID, hex sql.NullString
+	var visibleToJSON []byte
+
+	err := row.Scan(
+		&marker.ID, &marker.GameID, &marker.Type, &marker.Owner, &unitID, &hex, &marker.Value,
+		&visibleToJSON, &marker.Expiry, &marker.CreatedTurn, &marker.CreatedPhase,
+		&marker.CreatedAt, &marker.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	json.Unmarshal(visibleToJSON, &marker.VisibleTo)
+	marker.Hex = hex.String
+	if unitID.Valid {
+		marker.UnitID = &unitID.String
+	}
+
+	return &marker, nil
+}
@@ -0,0 +1,108 @@
+package services
+
+import (
+	"bismarck-game/backend/internal/game/models"
+	"testing"
+)
+
+func TestMarker(t *testing.T) {
+	t.Run("Defaults", func(t *testing.T) {
+		patrol := models.NewMarker("game", models.MarkerPatrol, models.PlayerSideAllied, 3, models.PhaseMovement)
+		if patrol.Expiry != models.MarkerExpiryAdminPhase {
+			t.Errorf("Маркер Патруля должен убираться в Фазе администрирования, получено %s", patrol.Expiry)
+		}
+		if patrol.IsVisibleTo(models.PlayerSideGerman) {
+			t.Error("Маркер Патруля не должен быть виден противнику")
+		}
+
+		sighted := models.NewMarker("game", models.MarkerSighted, models.PlayerSideAllied, 3, models.PhaseSearch)
+		if !sighted.IsVisibleTo(models.PlayerSideGerman) || sighted.Expiry != models.MarkerExpiryManual {
+			t.Error("Маркер \"Обнаружено\" должен быть виден обеим сторонам и сниматься по событию")
+		}
+
+		path := models.NewMarker("game", models.MarkerSearchFlightPath, models.PlayerSideAllied, 3, models.PhaseSearch)
+		if !path.ExpiresAtEndOf(3, models.PhaseSearch) || path.ExpiresAtEndOf(3, models.PhaseAirAttack) {
+			t.Error("Путь полета должен убираться в конце фазы, в которой размещен")
+		}
+	})
+
+	t.Run("CountdownFlip", func(t *testing.T) {
+		marker := models.NewMarker("game", models.MarkerNoMovement, models.PlayerSideGerman, 5, models.PhaseMovement)
+		marker.Value = 2
+
+		if marker.Flip() || marker.Value != 1 {
+			t.Errorf("Маркер должен перевернуться на 1, получено %d", marker.Value)
+		}
+		if !marker.Flip() {
+			t.Error("Маркер должен быть убран после последнего переворота")
+		}
+
+		event := markerAdminEvent(marker)
+		if event.Type != models.AdminEventNoMovementEnd || event.Side != models.PlayerSideGerman {
+			t.Errorf("Ожидалось событие снятия маркера немецкой стороны, получено %s/%s", event.Type, event.Side)
+		}
+	})
+
+	t.Run("Filter", func(t *testing.T) {
+		unitID := "bismarck"
+		marker := models.NewMarker("game", models.MarkerShadowed, models.PlayerSideAllied, 4, models.PhaseShadow)
+		marker.UnitID = &unitID
+		marker.Hex = "K20"
+
+		if !(models.MarkerFilter{UnitID: "bismarck", VisibleTo: models.PlayerSideGerman}).Matches(marker) {
+			t.Error("Маркер должен подходить под фильтр по юниту")
+		}
+		if (models.MarkerFilter{Type: models.MarkerSighted}).Matches(marker) {
+			t.Error("Маркер не должен подходить под фильтр другого типа")
+		}
+		if (models.MarkerFilter{Hex: "K21"}).Matches(marker) {
+			t.Error("Маркер не должен подходить под фильтр другого гекса")
+		}
+	})
+	t.Run("UnitMarkers", func(t *testing.T) {
+		game := newVictoryTestGame()
+		taskForce := "tf-1"
+		lastKnown := "K20"
+		units := []models.NavalUnit{
+			{ID: "bismarck", Owner: "german", Position: "K21", HullBoxes: 12, CurrentHull: 12,
+				Status: models.UnitStatusPatrolling, TaskForceID: &taskForce,
+				DetectionLevel: models.DetectionLevelShadowed, LastKnownPos: &lastKnown},
+			{ID: "prinz", Owner: "german", Position: "K21", HullBoxes: 8, CurrentHull: 8,
+				Status: models.UnitStatusPatrolling, TaskForceID: &taskForce},
+			{ID: "tanker", Owner: "german", Position: "M10", HullBoxes: 1, CurrentHull: 1,
+				Status: models.UnitStatusActive, NoMovement: 3},
+		}
+
+		markers := models.UnitMarkers(game, units)
+		patrols := 0
+		for i := range markers {
+			marker := &markers[i]
+			switch marker.Type {
+			case models.MarkerPatrol:
+				patrols++
+			case models.MarkerShadowed:
+				if marker.Hex != "K20" || marker.Owner != models.PlayerSideAllied {
+					t.Errorf("Маркер \"Преследуется\" ставит противник на последней известной позиции, получено %s/%s", marker.Owner, marker.Hex)
+				}
+			case models.MarkerNoMovement:
+				if marker.Value != 3 || *marker.UnitID != "tanker" {
+					t.Errorf("Ожидался маркер \"Нет движения 3\" танкера, получено %d", marker.Value)
+				}
+			}
+		}
+		if patrols != 1 {
+			t.Errorf("Соединение должно получить один маркер Патруля, получено %d", patrols)
+		}
+		if len(markers) != 3 {
+			t.Errorf("Ожидалось 3 маркера, получено %d", len(markers))
+		}
+
+		units[0].ClearMovementMarker()
+		units[1].ClearMovementMarker()
+		for _, marker := range models.UnitMarkers(game, units) {
+			if marker.Type == models.MarkerPatrol {
+				t.Error("Снятый в Фазе администрирования Патруль не должен оставаться маркером")
+			}
+		}
+	})
+}
@@ -0,0 +1,456 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+
+	"bismarck-game/backend/internal/game/models"
+	"bismarck-game/backend/pkg/database"
+	"bismarck-game/backend/pkg/logger"
+)
+
+// Ошибки Фазы движения
+var (
+	ErrNotMovementPhase = errors.New("action is only allowed in the movement phase")
+	ErrNotSearchPhase   = errors.New("action is only allowed in the search phase")
+	ErrUnitNotInGame    = errors.New("unit does not belong to this game")
+	ErrCannotRepair     = errors.New("unit cannot repair at sea")
+	ErrUnitAlreadyMoved = errors.New("unit has already moved this turn")
+	ErrCannotRefuel     = errors.New("unit cannot refuel this turn")
+	ErrNotInPort        = errors.New("unit is not in a port hex")
+	ErrRefuelNotGerman  = errors.New("only the German player may refuel at sea")
+	ErrTankerNotFound   = errors.New("no tanker available in the same hex")
+	ErrCannotPatrol     = errors.New("unit cannot patrol this turn")
+	ErrPatrolInFog      = errors.New("patrol is not allowed in fog hexes")
+	ErrPatrolVisibility = errors.New("patrol is not allowed at visibility X")
+
+	ErrShadowedMoveFirst     = errors.New("shadowed units must move first")
+	ErrNotShadowedMover      = errors.New("unit has no pending shadowed movement order")
+	ErrShadowedOrdersPending = errors.New("shadowed units have not given their movement orders")
+	ErrMoveByOrders          = errors.New("hidden movement must be submitted as locked orders")
+)
+
+// MovementPhaseService выполняет шаги Фазы движения (ремонт, заправка, патрулирование)
+type MovementPhaseService struct {
+	db           *database.Database
+	logger       *logger.Logger
+	unitService  *UnitService
+	intelligence *IntelligenceService
+	dice         DiceRoller
+	notifier     GameNotifier
+}
+
+// NewMovementPhaseService создает новый сервис Фазы движения
+func NewMovementPhaseService(db *database.Database, logger *logger.Logger, unitService *UnitService, intelligence *IntelligenceService, dice DiceRoller, notifier GameNotifier) *MovementPhaseService {
+	if dice == nil {
+		dice = NewRandomDice()
+	}
+	return &MovementPhaseService{
+		db:           db,
+		logger:       logger,
+		unitService:  unitService,
+		intelligence: intelligence,
+		dice:         dice,
+		notifier:     notifier,
+	}
+}
+
+// RepairAtSea отмечает корабль маркером Ремонт в море и выполняет бросок по Таблице ремонта в море (7.3)
+func (s *MovementPhaseService) RepairAtSea(game *models.Game, unitID string) (*models.RepairAtSeaResult, error) {
+	unit, err := s.getMovementPhaseUnit(game, unitID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !unit.CanRepairAtSea() {
+		return nil, ErrCannotRepair
+	}
+
+	// Корабль не должен двигаться в эту Фазу движения
+	moved, err := s.unitService.HasMovedInTurn(unit.ID, game.CurrentTurn)
+	if err != nil {
+		return nil, err
+	}
+	if moved {
+		return nil, ErrUnitAlreadyMoved
+	}
+
+	result := unit.ResolveRepairAtSea(s.dice.RollD10(), game.Weather, game.CurrentTurn)
+
+	if err := s.unitService.UpdateNavalUnit(unit); err != nil {
+		return nil, fmt.Errorf("failed to save repair at sea: %w", err)
+	}
+
+	s.logger.Info("Repair at sea resolved",
+		"game_id", game.ID,
+		"unit_id", unit.ID,
+		"modified_roll", result.ModifiedRoll,
+		"evasion_restored", result.EvasionRestored,
+		"rudder_repaired", result.RudderRepaired)
+
+	return &result, nil
+}
+
+// RefuelInPort отмечает корабль маркером В порту, заправляет его и пополняет торпеды (7.4)
+func (s *MovementPhaseService) RefuelInPort(game *models.Game, unitID string) (int, error) {
+	unit, err := s.getMovementPhaseUnit(game, unitID)
+	if err != nil {
+		return 0, err
+	}
+	if !game.Settings.IsPortHex(unit.Position) {
+		return 0, ErrNotInPort
+	}
+	if err := s.checkCanRefuel(game, unit); err != nil {
+		return 0, err
+	}
+
+	fuel := unit.RefuelInPort()
+
+	if err := s.unitService.UpdateNavalUnit(unit); err != nil {
+		return 0, fmt.Errorf("failed to save refuel in port: %w", err)
+	}
+
+	s.logger.Info("Unit refuelled in port", "game_id", game.ID, "unit_id", unit.ID, "fuel", fuel)
+	return fuel, nil
+}
+
+// RefuelAtSea заправляет немецкий корабль от танкера в том же гексе (7.5).
+// Каждый танкер может заправить только один корабль за ход
+func (s *MovementPhaseService) RefuelAtSea(game *models.Game, unitID string, tankerID string) (int, error) {
+	unit, err := s.getMovementPhaseUnit(game, unitID)
+	if err != nil {
+		return 0, err
+	}
+	if unitSide(game, unit) != models.PlayerSideGerman {
+		return 0, ErrRefuelNotGerman
+	}
+	if err := s.checkCanRefuel(game, unit); err != nil {
+		return 0, err
+	}
+
+	tanker, err := s.unitService.GetNavalUnitByID(tankerID)
+	if err != nil {
+		return 0, ErrTankerNotFound
+	}
+	if !isAvailableTanker(game, unit, tanker) {
+		return 0, ErrTankerNotFound
+	}
+
+	fuel := unit.RefuelAtSea(tanker)
+
+	tx, err := s.db.BeginTx()
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := s.unitService.UpdateNavalUnitTx(tx, unit); err != nil {
+		return 0, fmt.Errorf("failed to save refuel at sea: %w", err)
+	}
+	if err := s.unitService.UpdateNavalUnitTx(tx, tanker); err != nil {
+		return 0, fmt.Errorf("failed to save tanker: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit refuel at sea: %w", err)
+	}
+
+	s.logger.Info("Unit refuelled at sea", "game_id", game.ID, "unit_id", unit.ID, "tanker_id", tanker.ID, "fuel", fuel)
+	return fuel, nil
+}
+
+// checkCanRefuel проверяет, что корабль может заправиться и еще не двигался в этот ход
+func (s *MovementPhaseService) checkCanRefuel(game *models.Game, unit *models.NavalUnit) error {
+	if !unit.CanRefuel() {
+		return ErrCannotRefuel
+	}
+	moved, err := s.unitService.HasMovedInTurn(unit.ID, game.CurrentTurn)
+	if err != nil {
+		return err
+	}
+	if moved {
+		return ErrUnitAlreadyMoved
+	}
+	return nil
+}
+
+// isAvailableTanker проверяет, может ли танкер заправить корабль в этот ход
+func isAvailableTanker(game *models.Game, unit *models.NavalUnit, tanker *models.NavalUnit) bool {
+	return tanker.IsTanker() &&
+		tanker.IsAlive() &&
+		tanker.GameID == unit.GameID &&
+		tanker.Position == unit.Position &&
+		!tanker.HasMovementMarker() &&
+		unitSide(game, tanker) == models.PlayerSideGerman
+}
+
+// Patrol отмечает корабль маркером Морского патруля (7.6). Если корабль входит в ТФ,
+// патрулирует все соединение, но маркер ставится один и дает бонус к поиску только один раз
+func (s *MovementPhaseService) Patrol(game *models.Game, unitID string) ([]models.NavalUnit, error) {
+	unit, err := s.getMovementPhaseUnit(game, unitID)
+	if err != nil {
+		return nil, err
+	}
+	if game.IsVisibilityX() {
+		return nil, ErrPatrolVisibility
+	}
+	if game.IsFogHex(unit.Position) {
+		return nil, ErrPatrolInFog
+	}
+
+	patrolUnits := []models.NavalUnit{*unit}
+	if unit.TaskForceID != nil {
+		units, err := s.unitService.GetNavalUnitsByGameID(game.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get units: %w", err)
+		}
+		patrolUnits = patrolUnits[:0]
+		for _, member := range units {
+			if member.TaskForceID != nil && *member.TaskForceID == *unit.TaskForceID && member.IsAlive() {
+				patrolUnits = append(patrolUnits, member)
+			}
+		}
+	}
+
+	for i := range patrolUnits {
+		if !patrolUnits[i].CanPatrol() {
+			return nil, ErrCannotPatrol
+		}
+		// Юнит с маркером Патруля не может двигаться
+		moved, err := s.unitService.HasMovedInTurn(patrolUnits[i].ID, game.CurrentTurn)
+		if err != nil {
+			return nil, err
+		}
+		if moved {
+			return nil, ErrUnitAlreadyMoved
+		}
+	}
+
+	tx, err := s.db.BeginTx()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	for i := range patrolUnits {
+		patrolUnits[i].Status = models.UnitStatusPatrolling
+		if err := s.unitService.UpdateNavalUnitTx(tx, &patrolUnits[i]); err != nil {
+			return nil, fmt.Errorf("failed to save patrol marker: %w", err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit patrol: %w", err)
+	}
+
+	s.logger.Info("Patrol marker placed", "game_id", game.ID, "unit_id", unit.ID, "hex", unit.Position, "units", len(patrolUnits))
+	return patrolUnits, nil
+}
+
+// GetMovementStep возвращает текущий шаг Фазы движения и преследуемые юниты,
+// которые еще должны отдать приказ на движение
+func (s *MovementPhaseService) GetMovementStep(game *models.Game) (models.MovementStep, []models.NavalUnit, error) {
+	units, err := s.unitService.GetNavalUnitsByGameID(game.ID)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to get units: %w", err)
+	}
+	ordered, err := s.unitService.GetMovedUnitIDs(game.ID, game.CurrentTurn)
+	if err != nil {
+		return "", nil, err
+	}
+
+	pending := models.PendingShadowedUnits(units, ordered)
+	return models.CurrentMovementStep(units, ordered), pending, nil
+}
+
+// CheckMoveOrder проверяет, могут ли юниты двигаться напрямую на текущем шаге Фазы движения.
+// Пока преследуемые юниты не отдали приказы, двигаться могут только они (7.1).
+// Остальные юниты двигаются одновременно по зафиксированным приказам (OrderService)
+func (s *MovementPhaseService) CheckMoveOrder(game *models.Game, units []models.NavalUnit) error {
+	if game.CurrentPhase != models.PhaseMovement {
+		return nil
+	}
+
+	step, pending, err := s.GetMovementStep(game)
+	if err != nil {
+		return err
+	}
+	if step == models.MovementStepHidden {
+		// Преследуемые юниты, которые не могли двигаться, раскрываются до скрытого движения
+		if _, err := s.RevealShadowedMoves(game); err != nil {
+			return err
+		}
+		return ErrMoveByOrders
+	}
+
+	if !containsAllUnits(pending, units) {
+		return ErrShadowedMoveFirst
+	}
+	return nil
+}
+
+// HoldPosition записывает приказ преследуемого юнита (или его ТФ) остаться в гексе
+func (s *MovementPhaseService) HoldPosition(game *models.Game, unitID string) ([]models.ShadowedMoveReport, error) {
+	unit, err := s.getMovementPhaseUnit(game, unitID)
+	if err != nil {
+		return nil, err
+	}
+
+	_, pending, err := s.GetMovementStep(game)
+	if err != nil {
+		return nil, err
+	}
+
+	holding := []models.NavalUnit{}
+	for _, member := range pending {
+		if member.ID == unit.ID || (unit.TaskForceID != nil && member.TaskForceID != nil && *member.TaskForceID == *unit.TaskForceID) {
+			holding = append(holding, member)
+		}
+	}
+	if len(holding) == 0 {
+		return nil, ErrNotShadowedMover
+	}
+
+	tx, err := s.db.BeginTx()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	for _, member := range holding {
+		movement := models.UnitMovement{
+			GameID: game.ID,
+			UnitID: member.ID,
+			From:   member.Position,
+			To:     member.Position,
+			Path:   []string{member.Position},
+			Turn:   game.CurrentTurn,
+			Phase:  game.CurrentPhase,
+		}
+		if err := s.unitService.RecordMovementTx(tx, &movement); err != nil {
+			return nil, fmt.Errorf("failed to record hold order: %w", err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit hold order: %w", err)
+	}
+
+	s.logger.Info("Shadowed unit holds position", "game_id", game.ID, "unit_id", unit.ID, "hex", unit.Position)
+	return s.RevealShadowedMoves(game)
+}
+
+// RevealShadowedMoves сообщает противнику новые позиции преследуемых юнитов, когда все они
+// отдали приказы, и переворачивает их маркеры на "Обнаружено" (7.8)
+func (s *MovementPhaseService) RevealShadowedMoves(game *models.Game) ([]models.ShadowedMoveReport, error) {
+	units, err := s.unitService.GetNavalUnitsByGameID(game.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get units: %w", err)
+	}
+	ordered, err := s.unitService.GetMovedUnitIDs(game.ID, game.CurrentTurn)
+	if err != nil {
+		return nil, err
+	}
+	if models.CurrentMovementStep(units, ordered) == models.MovementStepShadowed {
+		return nil, nil
+	}
+
+	revealed, reports := revealShadowedUnits(game, units)
+	if len(revealed) == 0 {
+		return reports, nil
+	}
+
+	tx, err := s.db.BeginTx()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	for i := range revealed {
+		if err := s.unitService.UpdateNavalUnitTx(tx, &revealed[i]); err != nil {
+			return nil, fmt.Errorf("failed to reveal shadowed unit: %w", err)
+		}
+		// Преследующая сторона заносит новую позицию в журнал контактов
+		observer := unitSide(game, &revealed[i]).Opponent()
+		if err := s.intelligence.LogContactTx(tx, models.NewShadowContactReport(game, observer, &revealed[i])); err != nil {
+			return nil, err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit shadowed reveal: %w", err)
+	}
+
+	s.notifyShadowedMoves(game, reports)
+
+	s.logger.Info("Shadowed units revealed", "game_id", game.ID, "turn", game.CurrentTurn, "units", len(reports))
+	return reports, nil
+}
+
+// notifyShadowedMoves отправляет каждому игроку новые позиции преследуемых юнитов противника
+func (s *MovementPhaseService) notifyShadowedMoves(game *models.Game, reports []models.ShadowedMoveReport) {
+	if s.notifier == nil {
+		return
+	}
+
+	bySide := make(map[models.PlayerSide][]models.ShadowedMoveReport)
+	for _, report := range reports {
+		bySide[report.Side] = append(bySide[report.Side], report)
+	}
+
+	for side, sideReports := range bySide {
+		opponentID := game.GetOpponentID(game.GetPlayerIDBySide(side))
+		if opponentID == "" {
+			continue
+		}
+		s.notifier.SendNotification(opponentID, map[string]interface{}{
+			"type":    "shadowed_units_moved",
+			"game_id": game.ID,
+			"data":    sideReports,
+		})
+	}
+}
+
+// revealShadowedUnits раскрывает позиции всех преследуемых юнитов.
+// Возвращает измененные юниты и сообщения для противника
+func revealShadowedUnits(game *models.Game, units []models.NavalUnit) ([]models.NavalUnit, []models.ShadowedMoveReport) {
+	revealed := []models.NavalUnit{}
+	reports := []models.ShadowedMoveReport{}
+	for _, unit := range units {
+		if !unit.IsShadowed() {
+			continue
+		}
+		report := unit.RevealShadowedMove()
+		report.Side = unitSide(game, &unit)
+		revealed = append(revealed, unit)
+		reports = append(reports, report)
+	}
+	return revealed, reports
+}
+
+// containsAllUnits проверяет, что все юниты входят в список
+func containsAllUnits(list []models.NavalUnit, units []models.NavalUnit) bool {
+	ids := make(map[string]bool, len(list))
+	for _, unit := range list {
+		ids[unit.ID] = true
+	}
+	for _, unit := range units {
+		if !ids[unit.ID] {
+			return false
+		}
+	}
+	return len(units) > 0
+}
+
+// getMovementPhaseUnit возвращает корабль игры, проверяя, что идет Фаза движения
+func (s *MovementPhaseService) getMovementPhaseUnit(game *models.Game, unitID string) (*models.NavalUnit, error) {
+	if game.CurrentPhase != models.PhaseMovement {
+		return nil, ErrNotMovementPhase
+	}
+
+	unit, err := s.unitService.GetNavalUnitByID(unitID)
+	if err != nil {
+		return nil, err
+	}
+	if unit.GameID != game.ID {
+		return nil, ErrUnitNotInGame
+	}
+
+	return unit, nil
+}
@@ -0,0 +1,238 @@
+package services
+
+import (
+	"bismarck-game/backend/internal/game/models"
+	"testing"
+)
+
+func TestRepairAtSea(t *testing.T) {
+	newDamagedShip := func() *models.NavalUnit {
+		return &models.NavalUnit{
+			ID: "pe", Name: "PRINZ EUGEN", Type: models.UnitTypeHeavyCruiser,
+			HullBoxes: 6, CurrentHull: 5, Evasion: 31, BaseEvasion: 31,
+			EvasionEffects: []int{5, 2}, Status: models.UnitStatusActive,
+		}
+	}
+
+	t.Run("RestoreEvasion", func(t *testing.T) {
+		unit := newDamagedShip()
+		if !unit.CanRepairAtSea() {
+			t.Fatal("Корабль с Эффектами уклонения должен иметь возможность ремонта")
+		}
+
+		result := unit.ResolveRepairAtSea(1, 0, 3)
+		if !result.Success || result.EvasionRestored != 3 {
+			t.Errorf("Ожидалось восстановление 3 факторов, получено %d", result.EvasionRestored)
+		}
+		if unit.GetEvasionLoss() != 4 {
+			t.Errorf("Ожидалась потеря уклонения 4, получено %d", unit.GetEvasionLoss())
+		}
+		if unit.CanSearch() || unit.CanMove() {
+			t.Error("Корабль на ремонте не может двигаться и искать")
+		}
+
+		unit.ClearMovementMarker()
+		if unit.Status != models.UnitStatusActive {
+			t.Errorf("Маркер ремонта должен быть снят, статус %s", unit.Status)
+		}
+	})
+
+	t.Run("RudderRepairedFirst", func(t *testing.T) {
+		unit := newDamagedShip()
+		unit.Damage = []models.Damage{{Type: models.DamageTypeRudder, Severity: 1}}
+
+		result := unit.ResolveRepairAtSea(0, 0, 3)
+		if !result.RudderRepaired || unit.HasRudderDamage() {
+			t.Error("Результат 0 должен отремонтировать руль")
+		}
+		if result.EvasionRestored != 0 {
+			t.Error("При ремонте руля факторы уклонения не восстанавливаются")
+		}
+	})
+
+	t.Run("WeatherModifier", func(t *testing.T) {
+		unit := newDamagedShip()
+
+		// Погода 7: бросок 1 модифицируется до 3 - провал
+		result := unit.ResolveRepairAtSea(1, 7, 3)
+		if result.ModifiedRoll != 3 || result.Success {
+			t.Errorf("Ожидался провал с модифицированным броском 3, получено %d", result.ModifiedRoll)
+		}
+		if unit.GetEvasionLoss() != 7 {
+			t.Error("При провале Эффекты уклонения не меняются")
+		}
+	})
+
+	t.Run("EvasionLossSurvivesCombat", func(t *testing.T) {
+		unit := newDamagedShip()
+		unit.EnterTacticalCombat("zone-1", "closing")
+		unit.ApplyCrewExhaustion()
+		if unit.GetTacticalEvasion() != 31-7-models.CrewExhaustionEvasionLoss {
+			t.Errorf("Ожидалась уклоняемость в бою %d, получено %d", 31-7-models.CrewExhaustionEvasionLoss, unit.GetTacticalEvasion())
+		}
+		unit.ExitTacticalCombat()
+
+		if unit.GetEvasionLoss() != 7 {
+			t.Errorf("Потеря уклонения должна сохраниться после боя, получено %d", unit.GetEvasionLoss())
+		}
+		if !unit.CanRepairAtSea() {
+			t.Error("После боя корабль должен иметь возможность ремонта в море")
+		}
+	})
+}
+
+func TestRefuel(t *testing.T) {
+	game := newVictoryTestGame()
+
+	t.Run("InPort", func(t *testing.T) {
+		unit := &models.NavalUnit{
+			ID: "rodney", Type: models.UnitTypeBattleship, SpeedRating: models.SpeedTypeMedium,
+			HullBoxes: 12, CurrentHull: 12, Fuel: 0, MaxFuel: 10, Torpedoes: 0, MaxTorpedoes: 2,
+		}
+		unit.StartEmergencyFuel(4)
+
+		if fuel := unit.RefuelInPort(); fuel != 4 {
+			t.Errorf("Ожидалось 4 FP, получено %d", fuel)
+		}
+		if unit.Torpedoes != 2 {
+			t.Error("Торпеды должны быть пополнены в порту")
+		}
+		if unit.IsOnEmergencyFuel() {
+			t.Error("Заправка снимает маркер Аварийное топливо")
+		}
+		if unit.CanSearch() || unit.GetAirAttackDRM() != models.AirAttackDRMInPort {
+			t.Error("Корабль в порту не ищет и получает модификатор воздушной атаки")
+		}
+	})
+
+	t.Run("AtSeaTankerLimit", func(t *testing.T) {
+		dd := &models.NavalUnit{
+			ID: "z23", GameID: game.ID, Type: models.UnitTypeDestroyer, Owner: "german", Position: "F12",
+			SpeedRating: models.SpeedTypeFast, HullBoxes: 2, CurrentHull: 2, Fuel: 9, MaxFuel: 10,
+			Torpedoes: 1, MaxTorpedoes: 4,
+		}
+		tanker := &models.NavalUnit{
+			ID: "weissenburg", GameID: game.ID, Type: models.UnitTypeTanker, Owner: "german", Position: "F12",
+			SpeedRating: models.SpeedTypeSlow, HullBoxes: 1, CurrentHull: 1,
+		}
+
+		if !isAvailableTanker(game, dd, tanker) {
+			t.Fatal("Танкер в том же гексе должен быть доступен")
+		}
+		// Немецкие эсминцы получают только 2 FP, но не больше лимита
+		if fuel := dd.RefuelAtSea(tanker); fuel != 1 {
+			t.Errorf("Ожидался 1 FP до лимита топлива, получено %d", fuel)
+		}
+		if dd.Torpedoes != 1 {
+			t.Error("Торпеды пополняются только в порту")
+		}
+		if isAvailableTanker(game, dd, tanker) {
+			t.Error("Танкер может заправить только один корабль за ход")
+		}
+
+		tanker.ClearMovementMarker()
+		dd.ClearMovementMarker()
+		if !isAvailableTanker(game, dd, tanker) || dd.Status != models.UnitStatusActive {
+			t.Error("Маркеры Заправки в море снимаются в Фазе администрирования")
+		}
+	})
+}
+
+func TestPatrol(t *testing.T) {
+	t.Run("PatrolFactorsOncePerHexSearch", func(t *testing.T) {
+		markers := []models.Marker{
+			{Type: models.MarkerPatrol, Owner: models.PlayerSideAllied, Hex: "I28"},
+			{Type: models.MarkerPatrol, Owner: models.PlayerSideAllied, Hex: "I28"},
+			{Type: models.MarkerPatrol, Owner: models.PlayerSideAllied, Hex: "I29"},
+			{Type: models.MarkerPatrol, Owner: models.PlayerSideGerman, Hex: "I28"},
+			{Type: models.MarkerSighted, Owner: models.PlayerSideAllied, Hex: "I28"},
+		}
+
+		if markers := models.CountPatrolMarkers(markers, models.PlayerSideAllied, "I28"); markers != 2 {
+			t.Errorf("Ожидалось 2 маркера патруля, получено %d", markers)
+		}
+		if factors := patrolSearchFactors(markers, models.PlayerSideAllied, "I28", false); factors != 2*models.PatrolSearchFactors {
+			t.Errorf("Первый поиск гекса должен получить %d факторов патруля, получено %d", 2*models.PatrolSearchFactors, factors)
+		}
+		if factors := patrolSearchFactors(markers, models.PlayerSideAllied, "I28", true); factors != 0 {
+			t.Errorf("Факторы патруля добавляются один раз за поиск гекса, получено %d", factors)
+		}
+	})
+
+	t.Run("PatrollingUnitSearchesButCannotMove", func(t *testing.T) {
+		unit := models.NavalUnit{HullBoxes: 6, CurrentHull: 6, Fuel: 5, Status: models.UnitStatusPatrolling}
+		if unit.CanMove() || !unit.CanSearch() || unit.CanPatrol() {
+			t.Error("Юнит с маркером Патруля ищет, но не двигается")
+		}
+	})
+
+	t.Run("VisibilityAndFog", func(t *testing.T) {
+		game := newVictoryTestGame()
+		game.CurrentTurn = 3 // ночной ход
+		game.Weather = 4
+		if game.GetVisibility() != 7 || game.IsVisibilityX() || game.IsFogHex("I28") {
+			t.Errorf("Статус погоды 4 ночью дает видимость 7, получено %d", game.GetVisibility())
+		}
+
+		game.Weather = 8
+		if !game.IsVisibilityX() || !game.IsFogHex("I28") {
+			t.Error("Статус погоды 8 ночью - туман и видимость X")
+		}
+
+		game.Settings.FogHexes = []string{"A1"}
+		if game.IsFogHex("I28") {
+			t.Error("Туман действует только в туманных гексах")
+		}
+	})
+}
+
+func TestShadowedMovement(t *testing.T) {
+	game := newVictoryTestGame()
+	game.CurrentPhase = models.PhaseMovement
+
+	known := "K20"
+	units := []models.NavalUnit{
+		{ID: "bismarck", Name: "BISMARCK", Owner: "german", Position: "K22", Fuel: 10, HullBoxes: 12, CurrentHull: 12,
+			Status: models.UnitStatusActive, DetectionLevel: models.DetectionLevelShadowed, LastKnownPos: &known},
+		{ID: "hood", Name: "HOOD", Owner: "allied", Position: "M20", Fuel: 10, HullBoxes: 10, CurrentHull: 10,
+			Status: models.UnitStatusActive, DetectionLevel: models.DetectionLevelNone},
+		{ID: "prinz-eugen", Name: "PRINZ EUGEN", Owner: "german", Position: "K21", Fuel: 10, HullBoxes: 6, CurrentHull: 6,
+			Status: models.UnitStatusRepairing, DetectionLevel: models.DetectionLevelShadowed},
+	}
+
+	pending := models.PendingShadowedUnits(units, map[string]bool{})
+	if len(pending) != 1 || pending[0].ID != "bismarck" {
+		t.Fatalf("Приказ должен отдать только преследуемый BISMARCK, получено %d юнитов", len(pending))
+	}
+	if step := models.CurrentMovementStep(units, map[string]bool{}); step != models.MovementStepShadowed {
+		t.Errorf("Ожидался шаг движения преследуемых юнитов, получено %s", step)
+	}
+	if containsAllUnits(pending, units[1:2]) {
+		t.Error("Непреследуемый юнит не может двигаться до преследуемых")
+	}
+	if !containsAllUnits(pending, units[:1]) {
+		t.Error("Преследуемый юнит должен двигаться первым")
+	}
+
+	ordered := map[string]bool{"bismarck": true}
+	if step := models.CurrentMovementStep(units, ordered); step != models.MovementStepHidden {
+		t.Errorf("После приказов преследуемых юнитов начинается скрытое движение, получено %s", step)
+	}
+
+	t.Run("Reveal", func(t *testing.T) {
+		units[0].Position = "K24"
+		revealed, reports := revealShadowedUnits(game, units)
+		if len(revealed) != 2 || len(reports) != 2 {
+			t.Fatalf("Ожидалось раскрытие 2 юнитов, получено %d", len(revealed))
+		}
+		report := reports[0]
+		if report.Side != models.PlayerSideGerman || report.From != "K20" || report.Position != "K24" {
+			t.Errorf("Неверное сообщение о движении: %+v", report)
+		}
+		for _, unit := range revealed {
+			if unit.DetectionLevel != models.DetectionLevelSighted {
+				t.Errorf("Маркер %s должен перевернуться на \"Обнаружено\", получено %s", unit.Name, unit.DetectionLevel)
+			}
+		}
+	})
+}
@@ -0,0 +1,300 @@
+package services
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"bismarck-game/backend/internal/game/models"
+	"bismarck-game/backend/pkg/database"
+	"bismarck-game/backend/pkg/logger"
+)
+
+// Ошибки покупки гипотетических юнитов
+var (
+	ErrOptionalUnitsDisabled  = errors.New("optional units are not used in this game")
+	ErrNotPreGame             = errors.New("action is only allowed before the first turn")
+	ErrUnknownOptionalUnit    = errors.New("unknown optional unit")
+	ErrOptionalUnitWrongSide  = errors.New("optional unit belongs to the other side")
+	ErrOptionalUnitPurchased  = errors.New("optional unit has already been purchased")
+	ErrOptionalUnitNoSetupHex = errors.New("optional unit has no setup hex configured")
+)
+
+// OptionalUnitService проводит покупку гипотетических юнитов до начала игры (13.1).
+// Бросок раскрытия выполняется секретно; противнику сообщается о покупке, только если этого требует бросок
+type OptionalUnitService struct {
+	db                *database.Database
+	logger            *logger.Logger
+	unitService       *UnitService
+	shipConfigService *ShipConfigService
+	dice              DiceRoller
+	notifier          GameNotifier
+}
+
+// NewOptionalUnitService создает новый сервис гипотетических юнитов
+func NewOptionalUnitService(db *database.Database, logger *logger.Logger, unitService *UnitService, shipConfigService *ShipConfigService, dice DiceRoller, notifier GameNotifier) *OptionalUnitService {
+	if dice == nil {
+		dice = NewRandomDice()
+	}
+	return &OptionalUnitService{
+		db:                db,
+		logger:            logger,
+		unitService:       unitService,
+		shipConfigService: shipConfigService,
+		dice:              dice,
+		notifier:          notifier,
+	}
+}
+
+// optionalUnitPurchaseColumns список колонок покупки в порядке сканирования
+const optionalUnitPurchaseColumns = `id, game_id, side, ship_id, unit_id, vp, reveal_roll, revealed, created_at`
+
+// GetOptions возвращает гипотетические юниты стороны игрока и отметки о покупке
+func (s *OptionalUnitService) GetOptions(game *models.Game, userID string) ([]models.OptionalUnitOption, error) {
+	side := game.GetPlayerRole(userID)
+	if side == "" {
+		return nil, ErrNotGamePlayer
+	}
+
+	purchases, err := getOptionalUnitPurchases(s.db, game.ID)
+	if err != nil {
+		return nil, err
+	}
+	purchased := make(map[string]bool, len(purchases))
+	for _, purchase := range purchases {
+		purchased[purchase.ShipID] = true
+	}
+
+	names := make(map[string]string)
+	if ships, err := s.shipConfigService.GetAvailableShips(string(side)); err == nil {
+		for _, ship := range ships {
+			names[ship.ID] = ship.Name
+		}
+	}
+
+	options := []models.OptionalUnitOption{}
+	for _, option := range game.Settings.GetOptionalUnits() {
+		if option.Side != side {
+			continue
+		}
+		options = append(options, models.OptionalUnitOption{
+			OptionalUnit: option,
+			Name:         names[option.ShipID],
+			Purchased:    purchased[option.ShipID],
+		})
+	}
+
+	return options, nil
+}
+
+// GetPurchases возвращает покупки игрока. После окончания игры видны покупки обеих сторон
+func (s *OptionalUnitService) GetPurchases(game *models.Game, userID string) ([]models.OptionalUnitPurchase, error) {
+	side := game.GetPlayerRole(userID)
+	if side == "" {
+		return nil, ErrNotGamePlayer
+	}
+
+	purchases, err := getOptionalUnitPurchases(s.db, game.ID)
+	if err != nil {
+		return nil, err
+	}
+	if game.IsCompleted() {
+		return purchases, nil
+	}
+
+	own := []models.OptionalUnitPurchase{}
+	for _, purchase := range purchases {
+		if purchase.Side == side {
+			own = append(own, purchase)
+		}
+	}
+	return own, nil
+}
+
+// PurchaseOptionalUnits покупает гипотетические юниты для стороны игрока, расставляет их
+// в гексах расстановки и секретно бросает кубик раскрытия для каждого
+func (s *OptionalUnitService) PurchaseOptionalUnits(game *models.Game, userID string, shipIDs []string) ([]models.OptionalUnitPurchase, error) {
+	side := game.GetPlayerRole(userID)
+	if side == "" {
+		return nil, ErrNotGamePlayer
+	}
+
+	tx, err := s.db.BeginTx()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Блокируем игру, чтобы одновременные покупки не создали один юнит дважды
+	// и покупка не прошла после начала первого хода
+	if err := lockGamePhase(tx, game); err != nil {
+		return nil, err
+	}
+	purchases, err := getOptionalUnitPurchases(tx, game.ID)
+	if err != nil {
+		return nil, err
+	}
+	purchased := make(map[string]bool, len(purchases))
+	for _, purchase := range purchases {
+		purchased[purchase.ShipID] = true
+	}
+
+	options, err := selectOptionalUnits(game, side, shipIDs, purchased)
+	if err != nil {
+		return nil, err
+	}
+
+	version := game.Settings.ShipCatalogVersion
+	if err := s.shipConfigService.EnsureVersion(tx, version); err != nil {
+		return nil, err
+	}
+
+	bought := []models.OptionalUnitPurchase{}
+	for _, option := range options {
+		unit, err := s.shipConfigService.CreateNavalUnitFromVersion(version, option.ShipID, game.ID, string(side), option.SetupHex)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create optional unit %s: %w", option.ShipID, err)
+		}
+		if err := s.unitService.CreateNavalUnitTx(tx, unit); err != nil {
+			return nil, err
+		}
+
+		roll := s.dice.RollD10()
+		purchase := models.OptionalUnitPurchase{
+			GameID:     game.ID,
+			Side:       side,
+			ShipID:     option.ShipID,
+			UnitID:     unit.ID,
+			VP:         option.VP,
+			RevealRoll: roll,
+			Revealed:   option.IsRevealedBy(roll),
+		}
+		if err := s.insertPurchase(tx, &purchase); err != nil {
+			return nil, err
+		}
+		bought = append(bought, purchase)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit optional units: %w", err)
+	}
+	if err := s.shipConfigService.GetSpecialRulesService().LoadGameRules(game.ID); err != nil {
+		return nil, err
+	}
+
+	s.notifyRevealed(game, userID, bought)
+
+	s.logger.Info("Optional units purchased", "game_id", game.ID, "side", side, "units", len(bought))
+	return bought, nil
+}
+
+// notifyRevealed сообщает противнику о покупках, раскрытых броском
+func (s *OptionalUnitService) notifyRevealed(game *models.Game, userID string, purchases []models.OptionalUnitPurchase) {
+	if s.notifier == nil {
+		return
+	}
+	opponentID := game.GetOpponentID(userID)
+	if opponentID == "" {
+		return
+	}
+
+	for _, purchase := range purchases {
+		if !purchase.Revealed {
+			continue
+		}
+		s.notifier.SendNotification(opponentID, map[string]interface{}{
+			"type":    "optional_unit_revealed",
+			"game_id": game.ID,
+			"data": map[string]interface{}{
+				"side":    purchase.Side,
+				"ship_id": purchase.ShipID,
+			},
+		})
+	}
+}
+
+// insertPurchase сохраняет покупку гипотетического юнита
+func (s *OptionalUnitService) insertPurchase(tx *sql.Tx, purchase *models.OptionalUnitPurchase) error {
+	query := `
+		INSERT INTO optional_unit_purchases (game_id, side, ship_id, unit_id, vp, reveal_roll, revealed)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id, created_at`
+
+	err := tx.QueryRow(query,
+		purchase.GameID, purchase.Side, purchase.ShipID, purchase.UnitID,
+		purchase.VP, purchase.RevealRoll, purchase.Revealed,
+	).Scan(&purchase.ID, &purchase.CreatedAt)
+	if err != nil {
+		s.logger.Error("Failed to record optional unit purchase", "game_id", purchase.GameID, "ship_id", purchase.ShipID, "error", err)
+		return fmt.Errorf("failed to record optional unit purchase: %w", err)
+	}
+
+	return nil
+}
+
+// selectOptionalUnits проверяет выбор гипотетических юнитов стороной
+func selectOptionalUnits(game *models.Game, side models.PlayerSide, shipIDs []string, purchased map[string]bool) ([]models.OptionalUnit, error) {
+	if !game.Settings.UseOptionalUnits {
+		return nil, ErrOptionalUnitsDisabled
+	}
+	if !game.IsPreGame() {
+		return nil, ErrNotPreGame
+	}
+	if len(shipIDs) == 0 {
+		return nil, fmt.Errorf("%w: no units selected", ErrUnknownOptionalUnit)
+	}
+
+	selected := make(map[string]bool, len(shipIDs))
+	options := []models.OptionalUnit{}
+	for _, shipID := range shipIDs {
+		option, ok := game.Settings.FindOptionalUnit(shipID)
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", ErrUnknownOptionalUnit, shipID)
+		}
+		if option.Side != side {
+			return nil, fmt.Errorf("%w: %s", ErrOptionalUnitWrongSide, shipID)
+		}
+		if purchased[shipID] || selected[shipID] {
+			return nil, fmt.Errorf("%w: %s", ErrOptionalUnitPurchased, shipID)
+		}
+		if option.SetupHex == "" {
+			return nil, fmt.Errorf("%w: %s", ErrOptionalUnitNoSetupHex, shipID)
+		}
+		selected[shipID] = true
+		options = append(options, option)
+	}
+
+	return options, nil
+}
+
+// getOptionalUnitPurchases загружает покупки гипотетических юнитов игры
+func getOptionalUnitPurchases(q querier, gameID string) ([]models.OptionalUnitPurchase, error) {
+	query := `
+		SELECT ` + optionalUnitPurchaseColumns + `
+		FROM optional_unit_purchases
+		WHERE game_id = $1
+		ORDER BY created_at`
+
+	rows, err := q.Query(query, gameID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get optional unit purchases: %w", err)
+	}
+	defer rows.Close()
+
+	purchases := []models.OptionalUnitPurchase{}
+	for rows.Next() {
+		var purchase models.OptionalUnitPurchase
+		var unitID sql.NullString
+		err := rows.Scan(
+			&purchase.ID, &purchase.GameID, &purchase.Side, &purchase.ShipID, &unitID,
+			&purchase.VP, &purchase.RevealRoll, &purchase.Revealed, &purchase.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan optional unit purchase: %w", err)
+		}
+		purchase.UnitID = unitID.String
+		purchases = append(purchases, purchase)
+	}
+
+	return purchases, rows.Err()
+}
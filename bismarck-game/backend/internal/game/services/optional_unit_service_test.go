@@ -0,0 +1,84 @@
+package services
+
+import (
+	"bismarck-game/backend/internal/game/models"
+	"errors"
+	"testing"
+)
+
+func newOptionalUnitTestGame() *models.Game {
+	game := newVictoryTestGame()
+	game.CurrentPhase = models.PhaseWaiting
+	game.Settings.UseOptionalUnits = true
+	game.Settings.OptionalUnits = []models.OptionalUnit{
+		{ShipID: "tirpitz", Side: models.PlayerSideGerman, VP: -9, SetupHex: "AR04", RevealBelow: 5},
+		{ShipID: "koln", Side: models.PlayerSideGerman, VP: -3, RevealBelow: 3},
+		{ShipID: "new_york", Side: models.PlayerSideAllied, VP: 5, SetupHex: "BD28", RevealBelow: 5},
+	}
+	return game
+}
+
+func TestSelectOptionalUnits(t *testing.T) {
+	game := newOptionalUnitTestGame()
+
+	options, err := selectOptionalUnits(game, models.PlayerSideGerman, []string{"tirpitz"}, nil)
+	if err != nil || len(options) != 1 || options[0].VP != -9 {
+		t.Fatalf("Покупка TIRPITZ должна быть принята, получено %v", err)
+	}
+
+	cases := []struct {
+		name      string
+		side      models.PlayerSide
+		shipIDs   []string
+		purchased map[string]bool
+		want      error
+	}{
+		{"WrongSide", models.PlayerSideGerman, []string{"new_york"}, nil, ErrOptionalUnitWrongSide},
+		{"Unknown", models.PlayerSideAllied, []string{"hood"}, nil, ErrUnknownOptionalUnit},
+		{"AlreadyPurchased", models.PlayerSideGerman, []string{"tirpitz"}, map[string]bool{"tirpitz": true}, ErrOptionalUnitPurchased},
+		{"Duplicate", models.PlayerSideGerman, []string{"tirpitz", "tirpitz"}, nil, ErrOptionalUnitPurchased},
+		{"NoSetupHex", models.PlayerSideGerman, []string{"koln"}, nil, ErrOptionalUnitNoSetupHex},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := selectOptionalUnits(game, tc.side, tc.shipIDs, tc.purchased); !errors.Is(err, tc.want) {
+				t.Errorf("Ожидалась ошибка %v, получено %v", tc.want, err)
+			}
+		})
+	}
+
+	t.Run("AfterStart", func(t *testing.T) {
+		started := newOptionalUnitTestGame()
+		started.CurrentPhase = models.PhaseMovement
+		if _, err := selectOptionalUnits(started, models.PlayerSideGerman, []string{"tirpitz"}, nil); !errors.Is(err, ErrNotPreGame) {
+			t.Errorf("После начала игры покупка запрещена, получено %v", err)
+		}
+	})
+
+	t.Run("Disabled", func(t *testing.T) {
+		disabled := newOptionalUnitTestGame()
+		disabled.Settings.UseOptionalUnits = false
+		if _, err := selectOptionalUnits(disabled, models.PlayerSideGerman, []string{"tirpitz"}, nil); !errors.Is(err, ErrOptionalUnitsDisabled) {
+			t.Errorf("Без гипотетических юнитов покупка запрещена, получено %v", err)
+		}
+	})
+
+	t.Run("RevealRoll", func(t *testing.T) {
+		if !options[0].IsRevealedBy(4) || options[0].IsRevealedBy(5) {
+			t.Error("Покупка раскрывается только при броске меньше 5")
+		}
+	})
+}
+
+func TestOptionalUnitVictoryLines(t *testing.T) {
+	game := newVictoryTestGame()
+	purchases := []models.OptionalUnitPurchase{
+		{Side: models.PlayerSideGerman, ShipID: "tirpitz", VP: -9},
+		{Side: models.PlayerSideAllied, ShipID: "new_york", VP: 5},
+	}
+
+	breakdown := calculateVictory(game, nil, &models.ConvoyVPBox{}, purchases, models.GameEndTurnTrack)
+	if breakdown.OptionalUnitVP != -4 {
+		t.Errorf("Ожидалось -4 VP за гипотетические юниты, получено %.1f", breakdown.OptionalUnitVP)
+	}
+}
@@ -0,0 +1,370 @@
+package services
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"bismarck-game/backend/internal/game/models"
+	"bismarck-game/backend/pkg/database"
+	"bismarck-game/backend/pkg/logger"
+)
+
+// Ошибки приказов на движение
+var (
+	ErrNotGamePlayer  = errors.New("user is not a player in this game")
+	ErrNotOwnUnit     = errors.New("unit belongs to the other side")
+	ErrOrdersLocked   = errors.New("orders are already locked for this turn")
+	ErrOrdersNotReady = errors.New("both players must lock their movement orders")
+	ErrInvalidOrder   = errors.New("invalid movement order")
+)
+
+// OrderService хранит секретные приказы на движение и выполняет их одновременно
+// после того, как оба игрока их зафиксировали
+type OrderService struct {
+	db               *database.Database
+	logger           *logger.Logger
+	unitService      *UnitService
+	taskForceService *TaskForceService
+	movementService  *MovementPhaseService
+	notifier         GameNotifier
+}
+
+// NewOrderService создает новый сервис приказов
+func NewOrderService(db *database.Database, logger *logger.Logger, unitService *UnitService, taskForceService *TaskForceService, movementService *MovementPhaseService, notifier GameNotifier) *OrderService {
+	return &OrderService{
+		db:               db,
+		logger:           logger,
+		unitService:      unitService,
+		taskForceService: taskForceService,
+		movementService:  movementService,
+		notifier:         notifier,
+	}
+}
+
+// orderBatchColumns список колонок приказов в порядке сканирования
+const orderBatchColumns = `id, game_id, player_id, side, turn, orders, locked,
+			   locked_at, resolved_at, created_at, updated_at`
+
+// GetOrders возвращает приказы игрока на текущий ход
+func (s *OrderService) GetOrders(game *models.Game, playerID string) (*models.OrderBatch, error) {
+	side := game.GetPlayerRole(playerID)
+	if side == "" {
+		return nil, ErrNotGamePlayer
+	}
+
+	query := `SELECT ` + orderBatchColumns + ` FROM movement_orders WHERE game_id = $1 AND turn = $2 AND player_id = $3`
+
+	batch, err := scanOrderBatch(s.db.QueryRow(query, game.ID, game.CurrentTurn, playerID))
+	if err == sql.ErrNoRows {
+		return &models.OrderBatch{
+			GameID:   game.ID,
+			PlayerID: playerID,
+			Side:     side,
+			Turn:     game.CurrentTurn,
+			Orders:   []models.MovementOrder{},
+		}, nil
+	}
+	if err != nil {
+		s.logger.Error("Failed to get orders", "game_id", game.ID, "player_id", playerID, "error", err)
+		return nil, fmt.Errorf("failed to get orders: %w", err)
+	}
+
+	return batch, nil
+}
+
+// SubmitOrders сохраняет приказы игрока на ход, заменяя предыдущие. Приказы можно
+// изменять, пока игрок их не зафиксировал
+func (s *OrderService) SubmitOrders(game *models.Game, playerID string, orders []models.MovementOrder) (*models.OrderBatch, error) {
+	side, err := s.checkOrderStep(game, playerID)
+	if err != nil {
+		return nil, err
+	}
+
+	units, err := s.unitService.GetNavalUnitsByGameID(game.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get units: %w", err)
+	}
+	if err := validateOrders(game, side, units, orders); err != nil {
+		return nil, err
+	}
+
+	query := `
+		INSERT INTO movement_orders (game_id, player_id, side, turn, orders)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (game_id, turn, player_id) DO UPDATE SET
+			orders = EXCLUDED.orders, updated_at = CURRENT_TIMESTAMP
+		WHERE movement_orders.locked = false
+		RETURNING ` + orderBatchColumns
+
+	ordersJSON, _ := json.Marshal(orders)
+
+	batch, err := scanOrderBatch(s.db.QueryRow(query, game.ID, playerID, side, game.CurrentTurn, ordersJSON))
+	if err == sql.ErrNoRows {
+		return nil, ErrOrdersLocked
+	}
+	if err != nil {
+		s.logger.Error("Failed to submit orders", "game_id", game.ID, "player_id", playerID, "error", err)
+		return nil, fmt.Errorf("failed to submit orders: %w", err)
+	}
+
+	s.logger.Info("Orders submitted", "game_id", game.ID, "turn", game.CurrentTurn, "side", side, "orders", len(orders))
+	return batch, nil
+}
+
+// LockOrders фиксирует приказы игрока. Противник узнает только о том, что приказы
+// зафиксированы. Когда оба игрока зафиксировали приказы, они выполняются
+func (s *OrderService) LockOrders(game *models.Game, playerID string) (*models.OrderBatch, error) {
+	side, err := s.checkOrderStep(game, playerID)
+	if err != nil {
+		return nil, err
+	}
+
+	query := `
+		INSERT INTO movement_orders (game_id, player_id, side, turn, locked, locked_at)
+		VALUES ($1, $2, $3, $4, true, CURRENT_TIMESTAMP)
+		ON CONFLICT (game_id, turn, player_id) DO UPDATE SET
+			locked = true, locked_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP
+		WHERE movement_orders.locked = false
+		RETURNING ` + orderBatchColumns
+
+	batch, err := scanOrderBatch(s.db.QueryRow(query, game.ID, playerID, side, game.CurrentTurn))
+	if err == sql.ErrNoRows {
+		return nil, ErrOrdersLocked
+	}
+	if err != nil {
+		s.logger.Error("Failed to lock orders", "game_id", game.ID, "player_id", playerID, "error", err)
+		return nil, fmt.Errorf("failed to lock orders: %w", err)
+	}
+
+	if s.notifier != nil {
+		s.notifier.BroadcastGameEvent(game.ID, "orders_locked", batch.Status())
+	}
+
+	s.logger.Info("Orders locked", "game_id", game.ID, "turn", game.CurrentTurn, "side", side)
+
+	if _, err := s.ResolveIfReady(game); err != nil {
+		return nil, err
+	}
+	return batch, nil
+}
+
+// GetOrderStatus возвращает публичный статус приказов обоих игроков
+func (s *OrderService) GetOrderStatus(game *models.Game) ([]models.OrderBatchStatus, error) {
+	batches, err := s.getBatches(game.ID, game.CurrentTurn)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := []models.OrderBatchStatus{}
+	for _, side := range []models.PlayerSide{models.PlayerSideGerman, models.PlayerSideAllied} {
+		status := models.OrderBatchStatus{Side: side}
+		for i := range batches {
+			if batches[i].Side == side {
+				status = batches[i].Status()
+			}
+		}
+		statuses = append(statuses, status)
+	}
+
+	return statuses, nil
+}
+
+// ResolveIfReady выполняет приказы хода, если оба игрока их зафиксировали или истекло
+// время хода. Возвращает true, если приказы хода выполнены
+func (s *OrderService) ResolveIfReady(game *models.Game) (bool, error) {
+	batches, err := s.getBatches(game.ID, game.CurrentTurn)
+	if err != nil {
+		return false, err
+	}
+	if len(batches) > 0 && allBatchesResolved(batches) {
+		return true, nil
+	}
+	if !models.OrdersReady(game, batches, time.Now()) {
+		return false, nil
+	}
+
+	// Помечаем приказы выполненными, чтобы они не были выполнены дважды
+	query := `
+		UPDATE movement_orders SET
+			locked = true, resolved_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP
+		WHERE game_id = $1 AND turn = $2 AND resolved_at IS NULL
+		RETURNING ` + orderBatchColumns
+
+	claimed, err := s.queryBatches(query, game.ID, game.CurrentTurn)
+	if err != nil {
+		return false, err
+	}
+
+	for i := range claimed {
+		results := s.executeOrders(game, &claimed[i])
+		if s.notifier != nil {
+			s.notifier.SendNotification(claimed[i].PlayerID, map[string]interface{}{
+				"type":    "orders_resolved",
+				"game_id": game.ID,
+				"data":    results,
+			})
+		}
+	}
+
+	if s.notifier != nil {
+		s.notifier.BroadcastGameEvent(game.ID, "movement_resolved", map[string]interface{}{
+			"turn": game.CurrentTurn,
+		})
+	}
+
+	s.logger.Info("Movement orders resolved", "game_id", game.ID, "turn", game.CurrentTurn, "batches", len(claimed))
+	return true, nil
+}
+
+// executeOrders выполняет приказы игрока. Невыполнимые приказы пропускаются
+func (s *OrderService) executeOrders(game *models.Game, batch *models.OrderBatch) []models.OrderResult {
+	results := make([]models.OrderResult, 0, len(batch.Orders))
+	for _, order := range batch.Orders {
+		var err error
+		if order.TaskForceID != "" {
+			err = s.taskForceService.MoveTaskForce(game, order.TaskForceID, order.To, order.Speed, order.Path)
+		} else {
+			// 1 топливо за 1 скорость
+			err = s.unitService.MoveUnit(order.UnitID, order.To, order.Speed, order.Speed, order.Path, game.CurrentTurn, game.CurrentPhase)
+		}
+
+		result := models.OrderResult{Order: order}
+		if err != nil {
+			s.logger.Warn("Movement order failed", "game_id", game.ID, "side", batch.Side, "error", err)
+			result.Error = err.Error()
+		}
+		results = append(results, result)
+	}
+	return results
+}
+
+// checkOrderStep проверяет, что игрок может отдавать приказы: идет скрытое движение Фазы движения
+func (s *OrderService) checkOrderStep(game *models.Game, playerID string) (models.PlayerSide, error) {
+	side := game.GetPlayerRole(playerID)
+	if side == "" {
+		return "", ErrNotGamePlayer
+	}
+	if game.CurrentPhase != models.PhaseMovement {
+		return "", ErrNotMovementPhase
+	}
+
+	step, _, err := s.movementService.GetMovementStep(game)
+	if err != nil {
+		return "", err
+	}
+	if step == models.MovementStepShadowed {
+		return "", ErrShadowedMoveFirst
+	}
+
+	return side, nil
+}
+
+// getBatches возвращает приказы обоих игроков на ход
+func (s *OrderService) getBatches(gameID string, turn int) ([]models.OrderBatch, error) {
+	query := `SELECT ` + orderBatchColumns + ` FROM movement_orders WHERE game_id = $1 AND turn = $2`
+	return s.queryBatches(query, gameID, turn)
+}
+
+// queryBatches выполняет запрос, возвращающий приказы
+func (s *OrderService) queryBatches(query string, args ...interface{}) ([]models.OrderBatch, error) {
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		s.logger.Error("Failed to query orders", "error", err)
+		return nil, fmt.Errorf("failed to query orders: %w", err)
+	}
+	defer rows.Close()
+
+	batches := []models.OrderBatch{}
+	for rows.Next() {
+		batch, err := scanOrderBatch(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan orders: %w", err)
+		}
+		batches = append(batches, *batch)
+	}
+
+	return batches, rows.Err()
+}
+
+// allBatchesResolved проверяет, выполнены ли все приказы
+func allBatchesResolved(batches []models.OrderBatch) bool {
+	for i := range batches {
+		if !batches[i].IsResolved() {
+			return false
+		}
+	}
+	return true
+}
+
+// validateOrders проверяет приказы игрока: каждый приказ относится к собственному кораблю
+// вне ТФ или к собственной ТФ, и каждый юнит получает не более одного приказа
+func validateOrders(game *models.Game, side models.PlayerSide, units []models.NavalUnit, orders []models.MovementOrder) error {
+	unitMap := make(map[string]*models.NavalUnit, len(units))
+	taskForceSides := make(map[string]models.PlayerSide)
+	for i := range units {
+		unit := &units[i]
+		unitMap[unit.ID] = unit
+		if unit.TaskForceID != nil && unit.IsOnMap() {
+			taskForceSides[*unit.TaskForceID] = unitSide(game, unit)
+		}
+	}
+
+	ordered := make(map[string]bool)
+	for _, order := range orders {
+		if order.To == "" || order.Speed < 1 || order.Speed > 6 || (order.UnitID == "") == (order.TaskForceID == "") {
+			return ErrInvalidOrder
+		}
+
+		id := order.UnitID
+		if order.TaskForceID != "" {
+			id = order.TaskForceID
+			if taskForceSides[id] != side {
+				return fmt.Errorf("%w: task force %s", ErrInvalidOrder, id)
+			}
+		} else {
+			unit, exists := unitMap[id]
+			if !exists || !unit.IsOnMap() || unitSide(game, unit) != side {
+				return fmt.Errorf("%w: unit %s", ErrInvalidOrder, id)
+			}
+			if unit.TaskForceID != nil {
+				return ErrUnitMovesWithTaskForce
+			}
+		}
+
+		if ordered[id] {
+			return fmt.Errorf("%w: duplicate order for %s", ErrInvalidOrder, id)
+		}
+		ordered[id] = true
+	}
+
+	return nil
+}
+
+// scanOrderBatch сканирует строку приказов
+func scanOrderBatch(row rowScanner) (*models.OrderBatch, error) {
+	var batch models.OrderBatch
+	var ordersJSON []byte
+	var lockedAt, resolvedAt sql.NullTime
+
+	err := row.Scan(
+		&batch.ID, &batch.GameID, &batch.PlayerID, &batch.Side, &batch.Turn, &ordersJSON, &batch.Locked,
+		&lockedAt, &resolvedAt, &batch.CreatedAt, &batch.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	batch.Orders = []models.MovementOrder{}
+	json.Unmarshal(ordersJSON, &batch.Orders)
+	if lockedAt.Valid {
+		batch.LockedAt = &lockedAt.Time
+	}
+	if resolvedAt.Valid {
+		batch.ResolvedAt = &resolvedAt.Time
+	}
+
+	return &batch, nil
+}
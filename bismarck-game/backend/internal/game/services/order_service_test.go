@@ -0,0 +1,77 @@
+package services
+
+import (
+	"bismarck-game/backend/internal/game/models"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestValidateOrders(t *testing.T) {
+	game := newVictoryTestGame()
+	units := newTaskForceTestUnits()
+	units = append(units, models.NavalUnit{ID: "bismarck", Name: "BISMARCK", Owner: "german", Position: "K20",
+		Fuel: 10, HullBoxes: 12, CurrentHull: 12, Status: models.UnitStatusActive})
+
+	valid := []models.MovementOrder{{TaskForceID: "force-h", To: "AC20", Speed: 2}}
+	if err := validateOrders(game, models.PlayerSideAllied, units, valid); err != nil {
+		t.Errorf("Приказ собственной ТФ должен быть принят, получена ошибка %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		side   models.PlayerSide
+		orders []models.MovementOrder
+		err    error
+	}{
+		{"EnemyUnit", models.PlayerSideAllied, []models.MovementOrder{{UnitID: "bismarck", To: "K21", Speed: 1}}, ErrInvalidOrder},
+		{"UnitInTaskForce", models.PlayerSideAllied, []models.MovementOrder{{UnitID: "renown", To: "AC20", Speed: 1}}, ErrUnitMovesWithTaskForce},
+		{"Duplicate", models.PlayerSideGerman, []models.MovementOrder{{UnitID: "bismarck", To: "K21", Speed: 1}, {UnitID: "bismarck", To: "K22", Speed: 1}}, ErrInvalidOrder},
+		{"BothTargets", models.PlayerSideAllied, []models.MovementOrder{{UnitID: "renown", TaskForceID: "force-h", To: "AC20", Speed: 1}}, ErrInvalidOrder},
+		{"NoSpeed", models.PlayerSideGerman, []models.MovementOrder{{UnitID: "bismarck", To: "K21"}}, ErrInvalidOrder},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := validateOrders(game, tt.side, units, tt.orders); !errors.Is(err, tt.err) {
+				t.Errorf("Ожидалась ошибка %v, получено %v", tt.err, err)
+			}
+		})
+	}
+}
+
+func TestOrdersReady(t *testing.T) {
+	game := newVictoryTestGame()
+	started := time.Now().Add(-10 * time.Minute)
+	game.LastActionAt = &started
+
+	german := models.OrderBatch{Side: models.PlayerSideGerman, Locked: true}
+	allied := models.OrderBatch{Side: models.PlayerSideAllied}
+
+	if models.OrdersReady(game, []models.OrderBatch{german, allied}, time.Now()) {
+		t.Error("Приказы не выполняются, пока один из игроков их не зафиксировал")
+	}
+
+	allied.Locked = true
+	if !models.OrdersReady(game, []models.OrderBatch{german, allied}, time.Now()) {
+		t.Error("Приказы выполняются, когда оба игрока их зафиксировали")
+	}
+
+	t.Run("TurnTimer", func(t *testing.T) {
+		expired := time.Now().Add(time.Duration(game.Settings.MaxTurnTime) * time.Minute)
+		if !models.OrdersReady(game, nil, expired) {
+			t.Error("Приказы выполняются по истечении времени хода")
+		}
+	})
+
+	t.Run("StatusHidesOrders", func(t *testing.T) {
+		batch := models.OrderBatch{Side: models.PlayerSideGerman, Locked: true,
+			Orders: []models.MovementOrder{{UnitID: "bismarck", To: "K21", Speed: 1}}}
+		status := batch.Status()
+		if status.Side != models.PlayerSideGerman || !status.Locked {
+			t.Errorf("Неверный статус приказов: %+v", status)
+		}
+		if batch.CanEdit() {
+			t.Error("Зафиксированные приказы нельзя изменять")
+		}
+	})
+}
@@ -0,0 +1,350 @@
+package services
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"bismarck-game/backend/internal/game/models"
+	"bismarck-game/backend/pkg/database"
+	"bismarck-game/backend/pkg/logger"
+)
+
+// Ошибки смены фазы
+var (
+	ErrPhaseChanged  = errors.New("game phase has already changed")
+	ErrPhaseNotReady = errors.New("waiting for the opponent to end the phase")
+)
+
+// phaseOrder порядок фаз внутри хода
+var phaseOrder = []models.GamePhase{
+	models.PhaseVisibility,
+	models.PhaseShadow,
+	models.PhaseMovement,
+	models.PhaseSearch,
+	models.PhaseAirAttack,
+	models.PhaseNavalCombat,
+	models.PhaseChance,
+	models.PhaseAdmin,
+}
+
+// PhaseService управляет последовательностью хода
+type PhaseService struct {
+	db              *database.Database
+	logger          *logger.Logger
+	gameService     *GameService
+	adminService    *AdminPhaseService
+	markerService   *MarkerService
+	movementService *MovementPhaseService
+	orderService    *OrderService
+	deployment      *DeploymentService
+	viewService     *ViewService
+}
+
+// NewPhaseService создает новый сервис фаз
+func NewPhaseService(db *database.Database, logger *logger.Logger, gameService *GameService, adminService *AdminPhaseService, markerService *MarkerService, movementService *MovementPhaseService, orderService *OrderService, deployment *DeploymentService, viewService *ViewService) *PhaseService {
+	return &PhaseService{
+		db:              db,
+		logger:          logger,
+		gameService:     gameService,
+		adminService:    adminService,
+		markerService:   markerService,
+		movementService: movementService,
+		orderService:    orderService,
+		deployment:      deployment,
+		viewService:     viewService,
+	}
+}
+
+// AdvancePhase завершает текущую фазу за сторону и переводит игру в следующую фазу,
+// когда ее закончили оба игрока. Условия окончания игры проверяются до и после смены фазы;
+// после Фазы администрирования последнего хода игра завершается.
+// При выходе из Фазы администрирования возвращается ее полный итог
+func (s *PhaseService) AdvancePhase(gameID string, side models.PlayerSide) (*models.Game, *models.AdminPhaseSummary, error) {
+	// Проверка на границе фазы
+	if _, err := s.gameService.CheckGameEnd(gameID); err != nil {
+		return nil, nil, err
+	}
+
+	game, err := s.gameService.GetGameByID(gameID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !game.IsActive() {
+		return game, nil, nil
+	}
+	if side == "" {
+		return nil, nil, ErrNotGamePlayer
+	}
+
+	// Фазы без приказов, включая покупку гипотетических юнитов до первого хода,
+	// заканчиваются, когда их завершили оба игрока
+	if !phaseHasOrders(game.CurrentPhase) {
+		readySides, err := s.markPhaseReady(game, side)
+		if err != nil {
+			return nil, nil, err
+		}
+		if !models.PhaseReady(game, readySides, time.Now()) {
+			return nil, nil, ErrPhaseNotReady
+		}
+	}
+
+	// Фаза движения не заканчивается, пока не выполнены приказы обоих игроков
+	if game.CurrentPhase == models.PhaseMovement {
+		if err := s.completeShadowedMovement(game); err != nil {
+			return nil, nil, err
+		}
+		resolved, err := s.orderService.ResolveIfReady(game)
+		if err != nil {
+			return nil, nil, err
+		}
+		if !resolved {
+			return nil, nil, ErrOrdersNotReady
+		}
+	}
+
+	// Фаза развертывания не заканчивается, пока не применена расстановка обоих игроков
+	if game.CurrentPhase == models.PhaseDeployment {
+		resolved, err := s.deployment.ResolveIfReady(game)
+		if err != nil {
+			return nil, nil, err
+		}
+		if !resolved {
+			return nil, nil, ErrDeploymentNotReady
+		}
+	}
+
+	tx, err := s.db.BeginTx()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Блокируем игру, чтобы фаза не была сменена дважды
+	if err := lockGamePhase(tx, game); err != nil {
+		return nil, nil, err
+	}
+
+	// Маркеры, размещенные на время фазы (Пути полета), убираются в ее конце
+	if _, err := s.markerService.ExpirePhaseMarkers(tx, game.ID, game.CurrentTurn, game.CurrentPhase); err != nil {
+		return nil, nil, err
+	}
+
+	var summary *models.AdminPhaseSummary
+	if game.CurrentPhase == models.PhaseAdmin {
+		summary, err = s.adminService.ProcessAdminPhase(tx, game)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to process admin phase: %w", err)
+		}
+	}
+
+	turn, phase, lastTurn := advanceTurnTrack(game)
+
+	query := `
+		UPDATE games SET
+			current_turn = $2, current_phase = $3,
+			updated_at = $4, last_action_at = $4
+		WHERE id = $1`
+
+	now := time.Now()
+	if _, err := tx.Exec(query, game.ID, turn, phase, now); err != nil {
+		s.logger.Error("Failed to advance phase", "game_id", game.ID, "error", err)
+		return nil, nil, fmt.Errorf("failed to advance phase: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, nil, fmt.Errorf("failed to commit phase change: %w", err)
+	}
+
+	if summary != nil {
+		if !lastTurn {
+			// D. Маркер Трека хода перемещается на следующий ход
+			summary.AddEvent(models.AdminPhaseEvent{
+				Type:  models.AdminEventTurnAdvanced,
+				Value: turn,
+			})
+		}
+		s.notifyAdminSummary(game, summary)
+	}
+
+	if lastTurn {
+		if _, err := s.gameService.CompleteGame(game, models.GameEndTurnTrack); err != nil {
+			return nil, nil, err
+		}
+		return game, summary, nil
+	}
+
+	game.CurrentTurn = turn
+	game.CurrentPhase = phase
+	game.UpdatedAt = now
+	game.LastActionAt = &now
+
+	s.logger.Info("Advanced phase", "game_id", game.ID, "turn", turn, "phase", phase)
+
+	if s.gameService.notifier != nil {
+		s.gameService.notifier.BroadcastGameEvent(game.ID, "phase_changed", map[string]interface{}{
+			"turn":  turn,
+			"phase": phase,
+		})
+	}
+	s.viewService.PushPlayerViews(game)
+
+	// Новый ход может исчерпать аварийное топливо
+	breakdown, err := s.gameService.CheckGameEnd(gameID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if breakdown != nil {
+		game.Status = models.GameStatusCompleted
+	}
+
+	return game, summary, nil
+}
+
+// markPhaseReady отмечает, что сторона закончила текущую фазу,
+// и возвращает стороны, закончившие ее
+func (s *PhaseService) markPhaseReady(game *models.Game, side models.PlayerSide) ([]models.PlayerSide, error) {
+	query := `
+		INSERT INTO phase_readiness (game_id, turn, phase, side)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (game_id, turn, phase, side) DO NOTHING`
+
+	result, err := s.db.Exec(query, game.ID, game.CurrentTurn, game.CurrentPhase, side)
+	if err != nil {
+		s.logger.Error("Failed to mark phase ready", "game_id", game.ID, "side", side, "error", err)
+		return nil, fmt.Errorf("failed to mark phase ready: %w", err)
+	}
+
+	rows, err := s.db.Query(`SELECT side FROM phase_readiness WHERE game_id = $1 AND turn = $2 AND phase = $3`,
+		game.ID, game.CurrentTurn, game.CurrentPhase)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get phase readiness: %w", err)
+	}
+	defer rows.Close()
+
+	var readySides []models.PlayerSide
+	for rows.Next() {
+		var ready models.PlayerSide
+		if err := rows.Scan(&ready); err != nil {
+			return nil, fmt.Errorf("failed to scan phase readiness: %w", err)
+		}
+		readySides = append(readySides, ready)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// Противник узнает, что сторона закончила фазу
+	if affected, _ := result.RowsAffected(); affected > 0 && s.gameService.notifier != nil {
+		s.gameService.notifier.BroadcastGameEvent(game.ID, "phase_ready", map[string]interface{}{
+			"turn":  game.CurrentTurn,
+			"phase": game.CurrentPhase,
+			"side":  side,
+		})
+	}
+
+	return readySides, nil
+}
+
+// phaseHasOrders проверяет, заканчивается ли фаза по приказам игроков: движение
+// и развертывание завершаются, когда оба игрока зафиксировали приказы или расстановку
+func phaseHasOrders(phase models.GamePhase) bool {
+	return phase == models.PhaseMovement || phase == models.PhaseDeployment
+}
+
+// completeShadowedMovement проверяет приказы преследуемых юнитов и раскрывает их позиции
+func (s *PhaseService) completeShadowedMovement(game *models.Game) error {
+	step, _, err := s.movementService.GetMovementStep(game)
+	if err != nil {
+		return err
+	}
+	if step == models.MovementStepShadowed {
+		return ErrShadowedOrdersPending
+	}
+	_, err = s.movementService.RevealShadowedMoves(game)
+	return err
+}
+
+// notifyAdminSummary отправляет каждому игроку итог Фазы администрирования,
+// содержащий только то, что этот игрок может знать
+func (s *PhaseService) notifyAdminSummary(game *models.Game, summary *models.AdminPhaseSummary) {
+	if s.gameService.notifier == nil {
+		return
+	}
+
+	for _, playerID := range []string{game.Player1ID, game.Player2ID} {
+		side := game.GetPlayerRole(playerID)
+		if playerID == "" || side == "" {
+			continue
+		}
+		s.gameService.notifier.SendNotification(playerID, map[string]interface{}{
+			"type":    "admin_phase_summary",
+			"game_id": game.ID,
+			"data":    summary.ForSide(side),
+		})
+	}
+}
+
+// lockGamePhase блокирует строку игры и проверяет, что фаза не изменилась
+func lockGamePhase(tx *sql.Tx, game *models.Game) error {
+	var turn int
+	var phase models.GamePhase
+
+	query := `SELECT current_turn, current_phase FROM games WHERE id = $1 FOR UPDATE`
+	if err := tx.QueryRow(query, game.ID).Scan(&turn, &phase); err != nil {
+		return fmt.Errorf("failed to lock game: %w", err)
+	}
+	if turn != game.CurrentTurn || phase != game.CurrentPhase {
+		return ErrPhaseChanged
+	}
+
+	return nil
+}
+
+// advanceTurnTrack возвращает следующие ход и фазу игры. Игра заканчивается после
+// Фазы администрирования последнего хода: тогда ended = true, а ход и фаза не меняются
+func advanceTurnTrack(game *models.Game) (turn int, phase models.GamePhase, ended bool) {
+	if game.CurrentPhase == models.PhaseAdmin && game.CurrentTurn >= game.Settings.GetMaxTurns() {
+		return game.CurrentTurn, game.CurrentPhase, true
+	}
+	turn, phase = nextGamePhase(game)
+	return turn, phase, false
+}
+
+// nextGamePhase возвращает следующие ход и фазу игры. После подготовки игра проходит
+// фазу развертывания, если в сценарии есть зоны развертывания
+func nextGamePhase(game *models.Game) (int, models.GamePhase) {
+	if game.CurrentPhase == models.PhaseWaiting && game.Settings.HasDeployment() {
+		return game.CurrentTurn, models.PhaseDeployment
+	}
+	return nextPhase(game.CurrentTurn, game.CurrentPhase)
+}
+
+// nextPhase возвращает следующие ход и фазу.
+// Игра начинается с Фазы движения первого хода сценария (ход 1, если он не задан).
+// Фазы видимости и преследования пропускаются на 1-м ходу
+func nextPhase(turn int, phase models.GamePhase) (int, models.GamePhase) {
+	if phase == models.PhaseWaiting || phase == models.PhaseDeployment {
+		if turn < 1 {
+			turn = 1
+		}
+		return turn, models.PhaseMovement
+	}
+
+	for i, p := range phaseOrder {
+		if p != phase {
+			continue
+		}
+		if i == len(phaseOrder)-1 {
+			return turn + 1, phaseOrder[0]
+		}
+		next := phaseOrder[i+1]
+		if turn == 1 && (next == models.PhaseVisibility || next == models.PhaseShadow) {
+			return nextPhase(turn, next)
+		}
+		return turn, next
+	}
+
+	return turn, phase
+}
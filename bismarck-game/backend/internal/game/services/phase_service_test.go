@@ -0,0 +1,69 @@
+package services
+
+import (
+	"bismarck-game/backend/internal/game/models"
+	"testing"
+	"time"
+)
+
+func TestAdvanceTurnTrack(t *testing.T) {
+	game := newVictoryTestGame()
+	game.Settings.MaxTurns = 3
+	game.CurrentTurn = 1
+	game.CurrentPhase = models.PhaseWaiting
+
+	adminPhases := 0
+	for step := 0; step < 100; step++ {
+		if game.CurrentPhase == models.PhaseAdmin {
+			adminPhases++
+		}
+
+		turn, phase, ended := advanceTurnTrack(game)
+		if ended {
+			if game.CurrentTurn != 3 || game.CurrentPhase != models.PhaseAdmin {
+				t.Errorf("Игра должна закончиться после Фазы администрирования 3-го хода, получено %d/%s",
+					game.CurrentTurn, game.CurrentPhase)
+			}
+			if turn != game.CurrentTurn || phase != game.CurrentPhase {
+				t.Errorf("После окончания игры ход и фаза не меняются, получено %d/%s", turn, phase)
+			}
+			if adminPhases != 3 {
+				t.Errorf("Ожидалось 3 Фазы администрирования, пройдено %d", adminPhases)
+			}
+			return
+		}
+		if turn > game.Settings.GetMaxTurns() {
+			t.Fatalf("Игра перешла за последний ход: %d/%s", turn, phase)
+		}
+		game.CurrentTurn, game.CurrentPhase = turn, phase
+	}
+
+	t.Fatal("Игра не закончилась по Треку ходов")
+}
+
+func TestPhaseReady(t *testing.T) {
+	game := newVictoryTestGame()
+	game.CurrentPhase = models.PhaseWaiting
+	now := time.Now()
+
+	if phaseHasOrders(models.PhaseWaiting) || phaseHasOrders(models.PhaseAirAttack) {
+		t.Error("Покупка гипотетических юнитов и воздушные атаки заканчиваются без приказов")
+	}
+	if !phaseHasOrders(models.PhaseMovement) || !phaseHasOrders(models.PhaseDeployment) {
+		t.Error("Движение и развертывание заканчиваются по приказам игроков")
+	}
+
+	if models.PhaseReady(game, []models.PlayerSide{models.PlayerSideGerman}, now) {
+		t.Error("Фаза не заканчивается, пока ее не завершил противник")
+	}
+	if !models.PhaseReady(game, []models.PlayerSide{models.PlayerSideGerman, models.PlayerSideAllied}, now) {
+		t.Error("Фаза заканчивается, когда ее завершили оба игрока")
+	}
+
+	started := now.Add(-2 * time.Minute)
+	game.Settings.MaxTurnTime = 1
+	game.LastActionAt = &started
+	if !models.PhaseReady(game, nil, now) {
+		t.Error("По истечении времени хода фаза заканчивается без ожидания противника")
+	}
+}
@@ -0,0 +1,381 @@
+package services
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"bismarck-game/backend/internal/config"
+	"bismarck-game/backend/internal/game/models"
+	"bismarck-game/backend/pkg/database"
+	"bismarck-game/backend/pkg/logger"
+)
+
+// ScenarioService загружает сценарии и расставляет их юниты в начале игры
+type ScenarioService struct {
+	db                *database.Database
+	logger            *logger.Logger
+	unitService       *UnitService
+	taskForceService  *TaskForceService
+	shipConfigService *ShipConfigService
+	scenarios         *config.ScenarioManager
+}
+
+// NewScenarioService создает новый сервис сценариев
+func NewScenarioService(db *database.Database, logger *logger.Logger, unitService *UnitService, taskForceService *TaskForceService, shipConfigService *ShipConfigService) *ScenarioService {
+	return &ScenarioService{
+		db:                db,
+		logger:            logger,
+		unitService:       unitService,
+		taskForceService:  taskForceService,
+		shipConfigService: shipConfigService,
+		scenarios:         config.NewScenarioManager(),
+	}
+}
+
+// Ошибки сценариев
+var (
+	ErrInvalidScenario = errors.New("invalid scenario")
+)
+
+// LoadScenarios загружает сценарии из директории и проверяет их по каталогу кораблей
+func (s *ScenarioService) LoadScenarios(dir string) error {
+	scenarios, err := s.scenarios.LoadScenarios(dir)
+	if err != nil {
+		s.logger.Error("Failed to load scenarios", "dir", dir, "error", err)
+		return fmt.Errorf("failed to load scenarios: %w", err)
+	}
+
+	for _, scenario := range scenarios {
+		if err := s.ValidateScenario(scenario); err != nil {
+			return fmt.Errorf("scenario %s: %w", scenario.ID, err)
+		}
+	}
+
+	s.logger.Info("Loaded scenarios", "dir", dir, "count", len(scenarios))
+	return nil
+}
+
+// GetScenario возвращает сценарий по ID; пустой ID означает сценарий по умолчанию
+func (s *ScenarioService) GetScenario(id string) (*config.ScenarioConfig, error) {
+	return s.scenarios.GetScenario(id)
+}
+
+// GetScenarios возвращает все загруженные сценарии
+func (s *ScenarioService) GetScenarios() []*config.ScenarioConfig {
+	return s.scenarios.GetAllScenarios()
+}
+
+// ValidateScenario проверяет сценарий по каталогу кораблей и Карте поиска
+func (s *ScenarioService) ValidateScenario(scenario *config.ScenarioConfig) error {
+	if err := scenario.Validate(s.shipConfigService.GetConfigManager()); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidScenario, err)
+	}
+	return validateScenarioMap(scenario)
+}
+
+// InstantiateScenarioTx расставляет корабли, соединения, маркеры и воздушные юниты сценария
+// в рамках транзакции. Корабли, расставляемые в фазе развертывания, добавляются в настройки игры.
+// Игра запоминает текущую версию каталога кораблей
+func (s *ScenarioService) InstantiateScenarioTx(tx *sql.Tx, game *models.Game, scenario *config.ScenarioConfig) error {
+	version := s.shipConfigService.CurrentVersion()
+	if err := s.shipConfigService.SaveVersionTx(tx, version); err != nil {
+		return err
+	}
+	game.Settings.ShipCatalogVersion = version
+
+	for _, side := range []models.PlayerSide{models.PlayerSideGerman, models.PlayerSideAllied} {
+		setup := scenario.GetSide(string(side))
+
+		ships, err := s.createScenarioShips(tx, game, side, setup)
+		if err != nil {
+			return err
+		}
+		if err := s.createScenarioTaskForces(tx, game, side, setup, ships); err != nil {
+			return err
+		}
+		if err := s.createScenarioAirUnits(tx, game, side, setup, ships); err != nil {
+			return err
+		}
+	}
+
+	s.logger.Info("Scenario instantiated", "game_id", game.ID, "scenario", scenario.ID, "ship_catalog", version)
+	return nil
+}
+
+// createScenarioShips создает корабли стороны. Возвращает юниты по ID корабля в каталоге
+func (s *ScenarioService) createScenarioShips(tx *sql.Tx, game *models.Game, side models.PlayerSide, setup *config.ScenarioSide) (map[string]*models.NavalUnit, error) {
+	ships := make(map[string]*models.NavalUnit)
+
+	for _, placement := range setup.Ships {
+		unit, err := s.shipConfigService.CreateNavalUnitFromVersion(game.Settings.ShipCatalogVersion, placement.ShipID, game.ID, string(side), placement.Hex)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create scenario unit %s: %w", placement.ShipID, err)
+		}
+		applyScenarioShip(unit, placement)
+
+		if err := s.unitService.CreateNavalUnitTx(tx, unit); err != nil {
+			return nil, err
+		}
+		if placement.DeployZone != "" {
+			game.Settings.DeployableUnits = append(game.Settings.DeployableUnits, models.DeployableUnit{
+				UnitID: unit.ID,
+				Zone:   placement.DeployZone,
+			})
+		}
+		// Маркер "Нет движения" и последняя известная позиция не входят в создание юнита.
+		// Маркеры сценария хранятся в полях юнита и строятся из них (models.UnitMarkers)
+		if unit.NoMovement > 0 || unit.LastKnownPos != nil {
+			if err := s.unitService.UpdateNavalUnitTx(tx, unit); err != nil {
+				return nil, err
+			}
+		}
+
+		ships[placement.ShipID] = unit
+	}
+
+	return ships, nil
+}
+
+// createScenarioTaskForces формирует соединения стороны из созданных кораблей
+func (s *ScenarioService) createScenarioTaskForces(tx *sql.Tx, game *models.Game, side models.PlayerSide, setup *config.ScenarioSide, ships map[string]*models.NavalUnit) error {
+	for _, scenarioTF := range setup.TaskForces {
+		var members []*models.NavalUnit
+		var memberValues []models.NavalUnit
+		for _, placement := range setup.Ships {
+			if placement.TaskForce == scenarioTF.ID {
+				members = append(members, ships[placement.ShipID])
+				memberValues = append(memberValues, *ships[placement.ShipID])
+			}
+		}
+		if len(members) == 0 {
+			continue
+		}
+
+		taskForce := &models.TaskForce{
+			GameID:   game.ID,
+			Name:     scenarioTF.Name,
+			Owner:    string(side),
+			Position: members[0].Position,
+			Speed:    taskForceSpeed(memberValues),
+		}
+		for _, unit := range members {
+			taskForce.Units = append(taskForce.Units, unit.ID)
+		}
+		if err := s.taskForceService.insertTaskForce(tx, taskForce); err != nil {
+			return err
+		}
+
+		for _, unit := range members {
+			unit.TaskForceID = &taskForce.ID
+			if err := s.unitService.UpdateNavalUnitTx(tx, unit); err != nil {
+				return fmt.Errorf("failed to update unit: %w", err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// createScenarioAirUnits размещает воздушные юниты стороны на авиабазах и авианосцах
+func (s *ScenarioService) createScenarioAirUnits(tx *sql.Tx, game *models.Game, side models.PlayerSide, setup *config.ScenarioSide, ships map[string]*models.NavalUnit) error {
+	bases := make(map[string]string)
+	for _, base := range setup.AirBases {
+		bases[base.ID] = base.Hex
+	}
+
+	for _, scenarioAir := range setup.AirUnits {
+		hex, isAirBase := bases[scenarioAir.Base]
+		if !isAirBase {
+			hex = ships[scenarioAir.Base].Position
+		}
+
+		count := scenarioAir.Count
+		if count == 0 {
+			count = 1
+		}
+		for i := 0; i < count; i++ {
+			unit := &models.AirUnit{
+				GameID:       game.ID,
+				Type:         models.UnitType(scenarioAir.Type),
+				Owner:        string(side),
+				Position:     hex,
+				BasePosition: hex,
+				MaxSpeed:     scenarioAir.MaxSpeed,
+				Endurance:    scenarioAir.Endurance,
+				Status:       models.AirUnitStatusOperational,
+			}
+			if err := s.unitService.CreateAirUnitTx(tx, unit); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// applyScenarioShip применяет к юниту топливо, национальность, ход входа и маркеры расстановки
+func applyScenarioShip(unit *models.NavalUnit, placement config.ScenarioShip) {
+	if placement.Fuel != nil {
+		unit.Fuel = *placement.Fuel
+	}
+	if placement.Nationality != "" {
+		unit.Nationality = placement.Nationality
+	}
+
+	for _, marker := range placement.Markers {
+		switch models.MarkerType(marker.Type) {
+		case models.MarkerPatrol:
+			unit.Status = models.UnitStatusPatrolling
+		case models.MarkerInPort:
+			unit.Status = models.UnitStatusInPort
+		case models.MarkerRefuelAtSea:
+			unit.Status = models.UnitStatusRefueling
+		case models.MarkerRepairAtSea:
+			unit.Status = models.UnitStatusRepairing
+		case models.MarkerNoMovement:
+			unit.NoMovement = marker.Value
+		case models.MarkerSighted:
+			unit.DetectionLevel = models.DetectionLevelSighted
+			position := unit.Position
+			unit.LastKnownPos = &position
+		case models.MarkerShadowed:
+			unit.DetectionLevel = models.DetectionLevelShadowed
+			position := unit.Position
+			unit.LastKnownPos = &position
+		}
+	}
+
+	// Подкрепление ждет на Треке хода
+	if placement.ArrivalTurn != 0 {
+		arrival := placement.ArrivalTurn
+		unit.ArrivalTurn = &arrival
+		unit.Status = models.UnitStatusReinforcement
+	}
+}
+
+// isScenarioMarker проверяет, может ли корабль начинать игру с маркером
+func isScenarioMarker(markerType models.MarkerType) bool {
+	switch markerType {
+	case models.MarkerPatrol, models.MarkerInPort, models.MarkerRefuelAtSea, models.MarkerRepairAtSea,
+		models.MarkerNoMovement, models.MarkerSighted, models.MarkerShadowed:
+		return true
+	}
+	return false
+}
+
+// validateScenarioMap проверяет, что гексы сценария находятся на Карте поиска, а маркеры допустимы
+func validateScenarioMap(scenario *config.ScenarioConfig) error {
+	hexes := append(append([]string{}, scenario.PortHexes...), scenario.FogHexes...)
+	if scenario.Victory != nil {
+		hexes = append(hexes, scenario.Victory.FrancePortHexes...)
+		hexes = append(hexes, scenario.Victory.NorwayPortHexes...)
+	}
+
+	for _, side := range []models.PlayerSide{models.PlayerSideGerman, models.PlayerSideAllied} {
+		setup := scenario.GetSide(string(side))
+		for _, placement := range setup.Ships {
+			hexes = append(hexes, placement.Hex)
+			for _, marker := range placement.Markers {
+				if !isScenarioMarker(models.MarkerType(marker.Type)) {
+					return fmt.Errorf("%w: unknown marker %s on %s", ErrInvalidScenario, marker.Type, placement.ShipID)
+				}
+				// Позиция развертываемого корабля секретна, пока ее не раскроет поиск
+				detected := models.MarkerType(marker.Type) == models.MarkerSighted || models.MarkerType(marker.Type) == models.MarkerShadowed
+				if detected && placement.DeployZone != "" {
+					return fmt.Errorf("%w: deployable unit %s cannot start detected", ErrInvalidScenario, placement.ShipID)
+				}
+				if models.MarkerType(marker.Type) == models.MarkerNoMovement && (marker.Value < 1 || marker.Value > models.NoMovementVerySlow) {
+					return fmt.Errorf("%w: invalid no movement value on %s", ErrInvalidScenario, placement.ShipID)
+				}
+			}
+		}
+		for _, base := range setup.AirBases {
+			hexes = append(hexes, base.Hex)
+		}
+		for _, optional := range setup.OptionalUnits {
+			hexes = append(hexes, optional.SetupHex)
+		}
+		for _, zone := range setup.DeploymentZones {
+			hexes = append(hexes, zone.Hexes...)
+		}
+	}
+
+	for _, hex := range hexes {
+		if !models.IsValidHex(hex) {
+			return fmt.Errorf("%w: hex %s is off the map", ErrInvalidScenario, hex)
+		}
+	}
+
+	return nil
+}
+
+// applyScenarioSettings переносит в настройки игры Трек хода, гексы карты, зоны развертывания,
+// гипотетические юниты и изменения условий победы сценария
+func applyScenarioSettings(settings *models.GameSettings, scenario *config.ScenarioConfig) {
+	settings.Scenario = scenario.ID
+	settings.MaxTurns = scenario.TurnTrack.LastTurn
+	settings.DeploymentZones = nil
+	settings.DeployableUnits = nil
+	for _, side := range []models.PlayerSide{models.PlayerSideGerman, models.PlayerSideAllied} {
+		for _, zone := range scenario.GetSide(string(side)).DeploymentZones {
+			settings.DeploymentZones = append(settings.DeploymentZones, models.DeploymentZone{
+				ID:    zone.ID,
+				Name:  zone.Name,
+				Side:  side,
+				Hexes: zone.Hexes,
+			})
+		}
+	}
+	if len(scenario.PortHexes) > 0 {
+		settings.PortHexes = scenario.PortHexes
+	}
+	if len(scenario.FogHexes) > 0 {
+		settings.FogHexes = scenario.FogHexes
+	}
+
+	// Гипотетические юниты, заданные в настройках игры, имеют приоритет
+	if len(settings.OptionalUnits) == 0 {
+		for _, side := range []models.PlayerSide{models.PlayerSideGerman, models.PlayerSideAllied} {
+			for _, optional := range scenario.GetSide(string(side)).OptionalUnits {
+				settings.OptionalUnits = append(settings.OptionalUnits, models.OptionalUnit{
+					ShipID:      optional.ShipID,
+					Side:        side,
+					VP:          optional.VP,
+					SetupHex:    optional.SetupHex,
+					RevealBelow: optional.RevealBelow,
+					DeployZone:  optional.DeployZone,
+				})
+			}
+		}
+	}
+
+	victory := scenario.Victory
+	if victory == nil {
+		return
+	}
+	conditions := &settings.VictoryConditions
+	overrides := []struct {
+		value  *int
+		target **int
+	}{
+		{victory.BismarckSunkVP, &conditions.BismarckSunkVP},
+		{victory.BismarckUndamagedAtSeaVP, &conditions.BismarckUndamagedAtSeaVP},
+		{victory.BismarckFranceVP, &conditions.BismarckFranceVP},
+		{victory.BismarckNorwayVP, &conditions.BismarckNorwayVP},
+		{victory.BismarckEndGameVP, &conditions.BismarckEndGameVP},
+		{victory.BismarckNoFuelVP, &conditions.BismarckNoFuelVP},
+	}
+	for _, override := range overrides {
+		if override.value != nil {
+			value := *override.value
+			*override.target = &value
+		}
+	}
+	if len(victory.FrancePortHexes) > 0 {
+		conditions.FrancePortHexes = victory.FrancePortHexes
+	}
+	if len(victory.NorwayPortHexes) > 0 {
+		conditions.NorwayPortHexes = victory.NorwayPortHexes
+	}
+}
@@ -0,0 +1,163 @@
+package services
+
+import (
+	"bismarck-game/backend/internal/config"
+	"bismarck-game/backend/internal/game/models"
+	"errors"
+	"testing"
+)
+
+func newScenarioTestService(t *testing.T) (*ScenarioService, *config.ScenarioConfig) {
+	shipConfigService := NewShipConfigService(NewSpecialRulesService())
+	if err := shipConfigService.LoadConfig("../../../config/ships.json"); err != nil {
+		t.Fatalf("Ошибка загрузки конфигурации: %v", err)
+	}
+
+	service := &ScenarioService{shipConfigService: shipConfigService, scenarios: config.NewScenarioManager()}
+	if _, err := service.scenarios.LoadScenarios("../../../config/scenarios"); err != nil {
+		t.Fatalf("Ошибка загрузки сценариев: %v", err)
+	}
+	scenario, err := service.GetScenario("")
+	if err != nil {
+		t.Fatalf("Сценарий по умолчанию не найден: %v", err)
+	}
+	return service, scenario
+}
+
+func TestDefaultScenario(t *testing.T) {
+	service, scenario := newScenarioTestService(t)
+
+	if scenario.ID != config.DefaultScenarioID {
+		t.Errorf("Ожидался сценарий %s, получен %s", config.DefaultScenarioID, scenario.ID)
+	}
+	if err := service.ValidateScenario(scenario); err != nil {
+		t.Fatalf("Сценарий по умолчанию должен быть корректным: %v", err)
+	}
+
+	settings := models.GetDefaultGameSettings()
+	applyScenarioSettings(&settings, scenario)
+	if settings.Scenario != scenario.ID || settings.GetMaxTurns() != scenario.TurnTrack.LastTurn {
+		t.Error("Трек хода сценария должен попасть в настройки игры")
+	}
+	if !settings.VictoryConditions.IsNorwayPort("P32") || !settings.IsPortHex("U26") {
+		t.Error("Порты сценария должны попасть в условия победы")
+	}
+	tirpitz, found := settings.FindOptionalUnit("tirpitz")
+	if len(settings.OptionalUnits) != 8 || !found || tirpitz.SetupHex != "O33" {
+		t.Errorf("Ожидалось 8 гипотетических юнитов сценария, получено %d", len(settings.OptionalUnits))
+	}
+}
+
+func TestValidateScenario(t *testing.T) {
+	service, _ := newScenarioTestService(t)
+
+	newScenario := func() *config.ScenarioConfig {
+		return &config.ScenarioConfig{
+			Version:   config.ScenarioFormatVersion,
+			ID:        "test",
+			TurnTrack: config.ScenarioTurnTrack{FirstTurn: 1, LastTurn: 10},
+			German: config.ScenarioSide{
+				TaskForces: []config.ScenarioTaskForce{{ID: "tf", Name: "Kampfgruppe"}},
+				Ships: []config.ScenarioShip{
+					{ShipID: "bismarck", Hex: "P32", TaskForce: "tf"},
+					{ShipID: "prinz_eugen", Hex: "P32", TaskForce: "tf"},
+				},
+			},
+			Allied: config.ScenarioSide{
+				Ships: []config.ScenarioShip{{ShipID: "hood", Hex: "M18"}},
+			},
+		}
+	}
+
+	if err := service.ValidateScenario(newScenario()); err != nil {
+		t.Fatalf("Сценарий должен быть корректным: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		modify func(sc *config.ScenarioConfig)
+	}{
+		{"OffMapHex", func(sc *config.ScenarioConfig) { sc.Allied.Ships[0].Hex = "AZ40" }},
+		{"WrongSide", func(sc *config.ScenarioConfig) { sc.Allied.Ships[0].ShipID = "tirpitz" }},
+		{"DuplicateShip", func(sc *config.ScenarioConfig) {
+			sc.German.Ships = append(sc.German.Ships, config.ScenarioShip{ShipID: "bismarck", Hex: "O33"})
+		}},
+		{"SplitTaskForce", func(sc *config.ScenarioConfig) { sc.German.Ships[1].Hex = "O33" }},
+		{"UnknownMarker", func(sc *config.ScenarioConfig) {
+			sc.Allied.Ships[0].Markers = []config.ScenarioMarker{{Type: "flight_deck"}}
+		}},
+		{"ArrivalOffTrack", func(sc *config.ScenarioConfig) { sc.Allied.Ships[0].ArrivalTurn = 11 }},
+		{"UnknownAirBase", func(sc *config.ScenarioConfig) {
+			sc.Allied.AirUnits = []config.ScenarioAirUnit{{Type: "R", Base: "hood", MaxSpeed: 10, Endurance: 1}}
+		}},
+		{"UnknownDeployZone", func(sc *config.ScenarioConfig) { sc.Allied.Ships[0].DeployZone = "iceland" }},
+		{"HexOutsideDeployZone", func(sc *config.ScenarioConfig) {
+			sc.Allied.DeploymentZones = []config.ScenarioDeploymentZone{{ID: "iceland", Hexes: []string{"L18", "M19"}}}
+			sc.Allied.Ships[0].DeployZone = "iceland"
+		}},
+		{"DeployZoneOffMap", func(sc *config.ScenarioConfig) {
+			sc.Allied.DeploymentZones = []config.ScenarioDeploymentZone{{ID: "iceland", Hexes: []string{"M18", "AZ40"}}}
+		}},
+		{"DeployableInTaskForce", func(sc *config.ScenarioConfig) {
+			sc.German.DeploymentZones = []config.ScenarioDeploymentZone{{ID: "norway", Hexes: []string{"P32", "O33"}}}
+			sc.German.Ships[0].DeployZone = "norway"
+		}},
+		{"DeployableDetected", func(sc *config.ScenarioConfig) {
+			sc.Allied.DeploymentZones = []config.ScenarioDeploymentZone{{ID: "iceland", Hexes: []string{"M18"}}}
+			sc.Allied.Ships[0].DeployZone = "iceland"
+			sc.Allied.Ships[0].Markers = []config.ScenarioMarker{{Type: "sighted"}}
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scenario := newScenario()
+			tt.modify(scenario)
+			if err := service.ValidateScenario(scenario); !errors.Is(err, ErrInvalidScenario) {
+				t.Errorf("Ожидалась ошибка некорректного сценария, получено %v", err)
+			}
+		})
+	}
+
+	t.Run("UnsupportedVersion", func(t *testing.T) {
+		scenario := newScenario()
+		scenario.Version = 2
+		if err := scenario.Validate(service.shipConfigService.GetConfigManager()); !errors.Is(err, config.ErrUnsupportedScenario) {
+			t.Errorf("Ожидалась ошибка версии формата, получено %v", err)
+		}
+	})
+}
+
+func TestApplyScenarioShip(t *testing.T) {
+	fuel := 5
+	unit := &models.NavalUnit{Position: "T9", Fuel: 7, Status: models.UnitStatusActive}
+	applyScenarioShip(unit, config.ScenarioShip{
+		ShipID:  "ramillies",
+		Fuel:    &fuel,
+		Markers: []config.ScenarioMarker{{Type: "no_movement", Value: 4}, {Type: "sighted"}},
+	})
+	if unit.Fuel != 5 || unit.NoMovement != 4 {
+		t.Errorf("Ожидались топливо 5 и \"Нет движения-4\", получено %d/%d", unit.Fuel, unit.NoMovement)
+	}
+	if unit.DetectionLevel != models.DetectionLevelSighted || unit.LastKnownPos == nil || *unit.LastKnownPos != "T9" {
+		t.Error("Обнаруженный корабль должен иметь последнюю известную позицию")
+	}
+	unit.Position = "T10"
+	if *unit.LastKnownPos != "T9" {
+		t.Error("Последняя известная позиция не должна меняться при движении корабля")
+	}
+
+	reinforcement := &models.NavalUnit{Status: models.UnitStatusActive}
+	applyScenarioShip(reinforcement, config.ScenarioShip{ShipID: "dorsetshire", ArrivalTurn: 13})
+	if !reinforcement.IsReinforcementDue(13) || reinforcement.IsReinforcementDue(12) {
+		t.Error("Подкрепление должно войти в игру на 13-м ходу")
+	}
+
+	t.Run("Hexes", func(t *testing.T) {
+		for hex, valid := range map[string]bool{"A1": true, "AH35": true, "ah13": true, "AI1": false, "A36": false, "13": false, "AH": false} {
+			if models.IsValidHex(hex) != valid {
+				t.Errorf("Гекс %s: ожидалось %v", hex, valid)
+			}
+		}
+	})
+}
@@ -0,0 +1,202 @@
+package services
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"bismarck-game/backend/internal/config"
+	"bismarck-game/backend/internal/game/models"
+)
+
+// MaxRadarLevel максимальный уровень радара корабля (RADAR II*)
+const MaxRadarLevel = 2
+
+// CatalogIssue представляет ошибку в каталоге кораблей
+type CatalogIssue struct {
+	ShipID  string `json:"ship_id"`
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// String возвращает ошибку одной строкой, удобной для сравнения отчетов
+func (i CatalogIssue) String() string {
+	return fmt.Sprintf("%s: %s: %s", i.ShipID, i.Field, i.Message)
+}
+
+// CheckShipCatalog проверяет весь каталог кораблей. Ошибки отсортированы по ID корабля и полю,
+// поэтому отчет не зависит от порядка кораблей в файле
+func CheckShipCatalog(ships []config.ShipConfig) []CatalogIssue {
+	var issues []CatalogIssue
+	seen := make(map[string]int)
+	for i := range ships {
+		ship := &ships[i]
+		shipID := catalogShipID(ship, i)
+		issues = append(issues, checkShipConfig(ship, shipID)...)
+
+		if ship.ID == "" {
+			continue
+		}
+		if first, ok := seen[ship.ID]; ok {
+			issues = append(issues, CatalogIssue{shipID, "id", fmt.Sprintf("duplicate id, first defined at entry %d", first)})
+			continue
+		}
+		seen[ship.ID] = i
+	}
+
+	sort.SliceStable(issues, func(i, j int) bool {
+		if issues[i].ShipID != issues[j].ShipID {
+			return issues[i].ShipID < issues[j].ShipID
+		}
+		return issues[i].Field < issues[j].Field
+	})
+	return issues
+}
+
+// checkShipConfig проверяет одну запись каталога
+func checkShipConfig(ship *config.ShipConfig, shipID string) []CatalogIssue {
+	var issues []CatalogIssue
+	add := func(field, format string, args ...interface{}) {
+		issues = append(issues, CatalogIssue{shipID, field, fmt.Sprintf(format, args...)})
+	}
+
+	if ship.ID == "" {
+		add("id", "must not be empty")
+	}
+	if ship.Name == "" {
+		add("name", "must not be empty")
+	}
+	if side := models.PlayerSide(ship.Side); side != models.PlayerSideGerman && side != models.PlayerSideAllied {
+		add("side", "unknown side %q, expected %s", ship.Side, strings.Join(catalogSides(), ", "))
+	}
+	unitType := models.UnitType(ship.Type)
+	if !isNavalUnitType(unitType) {
+		add("type", "unknown type %q, expected %s", ship.Type, strings.Join(catalogUnitTypes(), ", "))
+	}
+	if !isSpeedType(models.SpeedType(ship.SpeedType)) {
+		add("speedType", "unknown speed type %q, expected %s", ship.SpeedType, strings.Join(catalogSpeedTypes(), ", "))
+	}
+
+	counts := map[string]int{
+		"maxFuel":                  ship.MaxFuel,
+		"baseEvasion":              ship.BaseEvasion,
+		"radarLevel":               ship.RadarLevel,
+		"hullBoxes":                ship.HullBoxes,
+		"basePrimaryArmamentBow":   ship.BasePrimaryArmamentBow,
+		"basePrimaryArmamentStern": ship.BasePrimaryArmamentStern,
+		"baseSecondaryArmament":    ship.BaseSecondaryArmament,
+		"maxTorpedos":              ship.MaxTorpedos,
+	}
+	for field, value := range counts {
+		if value < 0 {
+			add(field, "must not be negative, got %d", value)
+		}
+	}
+	if ship.RadarLevel > MaxRadarLevel {
+		add("radarLevel", "must be at most %d, got %d", MaxRadarLevel, ship.RadarLevel)
+	}
+
+	if ship.MaxTorpedos > 0 && isNavalUnitType(unitType) && !unitType.CarriesTorpedoes() {
+		add("maxTorpedos", "type %s cannot carry torpedoes, got %d", ship.Type, ship.MaxTorpedos)
+	}
+	if unitType == models.UnitTypeTanker {
+		armament := ship.BasePrimaryArmamentBow + ship.BasePrimaryArmamentStern + ship.BaseSecondaryArmament
+		if armament != 0 {
+			add("armament", "tanker must have no armament, got %d factors", armament)
+		}
+	}
+
+	rules := make(map[string]bool)
+	for _, rule := range ship.SpecialRules {
+		if _, ok := models.GetSpecialRuleHooks(models.SpecialRuleType(rule.Type)); !ok {
+			add("specialRules", "unknown special rule type %q", rule.Type)
+		}
+		if rules[rule.Type] {
+			add("specialRules", "duplicate special rule type %q", rule.Type)
+		}
+		rules[rule.Type] = true
+	}
+
+	return issues
+}
+
+// ShipCatalogSchema возвращает JSON Schema файла каталога кораблей
+func ShipCatalogSchema() map[string]interface{} {
+	zero, maxRadar := 0, MaxRadarLevel
+	ruleTypes := make([]string, 0)
+	for _, ruleType := range models.SpecialRuleTypes() {
+		ruleTypes = append(ruleTypes, string(ruleType))
+	}
+
+	fields := map[string]config.SchemaField{
+		"$schema":                        {Description: "Путь к JSON Schema каталога"},
+		"ships":                          {Description: "Корабли каталога"},
+		"ships.id":                       {Description: "Уникальный ID корабля"},
+		"ships.name":                     {Description: "Название корабля"},
+		"ships.type":                     {Description: "Тип корабля", Enum: catalogUnitTypes()},
+		"ships.side":                     {Description: "Сторона", Enum: catalogSides()},
+		"ships.maxFuel":                  {Description: "Максимальный запас топлива", Minimum: &zero},
+		"ships.baseEvasion":              {Description: "Базовое уклонение", Minimum: &zero},
+		"ships.radarLevel":               {Description: "Уровень радара: 0 - нет, 1 - RADAR I, 2 - RADAR II", Minimum: &zero, Maximum: &maxRadar},
+		"ships.hullBoxes":                {Description: "Количество корпусных отсеков", Minimum: &zero},
+		"ships.basePrimaryArmamentBow":   {Description: "Главный калибр, носовые башни", Minimum: &zero},
+		"ships.basePrimaryArmamentStern": {Description: "Главный калибр, кормовые башни", Minimum: &zero},
+		"ships.baseSecondaryArmament":    {Description: "Вспомогательный калибр", Minimum: &zero},
+		"ships.maxTorpedos":              {Description: "Торпеды, только для типов с торпедными аппаратами", Minimum: &zero},
+		"ships.speedType":                {Description: "Класс скорости", Enum: catalogSpeedTypes()},
+		"ships.specialRules.type":        {Description: "Тип специального правила", Enum: ruleTypes},
+	}
+
+	return config.GenerateSchema(config.ShipsConfig{}, "Каталог кораблей", fields)
+}
+
+// catalogShipID возвращает ID корабля для отчета или номер записи, если ID не задан
+func catalogShipID(ship *config.ShipConfig, index int) string {
+	if ship.ID == "" {
+		return fmt.Sprintf("#%d", index)
+	}
+	return ship.ID
+}
+
+// isNavalUnitType проверяет, является ли тип типом морского юнита
+func isNavalUnitType(unitType models.UnitType) bool {
+	for _, t := range models.NavalUnitTypes {
+		if t == unitType {
+			return true
+		}
+	}
+	return false
+}
+
+// isSpeedType проверяет класс скорости
+func isSpeedType(speedType models.SpeedType) bool {
+	for _, t := range models.SpeedTypes {
+		if t == speedType {
+			return true
+		}
+	}
+	return false
+}
+
+// catalogUnitTypes возвращает допустимые типы кораблей
+func catalogUnitTypes() []string {
+	types := make([]string, 0, len(models.NavalUnitTypes))
+	for _, t := range models.NavalUnitTypes {
+		types = append(types, string(t))
+	}
+	return types
+}
+
+// catalogSpeedTypes возвращает допустимые классы скорости
+func catalogSpeedTypes() []string {
+	types := make([]string, 0, len(models.SpeedTypes))
+	for _, t := range models.SpeedTypes {
+		types = append(types, string(t))
+	}
+	return types
+}
+
+// catalogSides возвращает допустимые стороны
+func catalogSides() []string {
+	return []string{string(models.PlayerSideGerman), string(models.PlayerSideAllied)}
+}
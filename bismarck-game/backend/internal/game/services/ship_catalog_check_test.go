@@ -0,0 +1,108 @@
+package services
+
+import (
+	"bismarck-game/backend/internal/config"
+	"strings"
+	"testing"
+)
+
+func newCatalogTestShip(id string) config.ShipConfig {
+	return config.ShipConfig{
+		ID:                     id,
+		Name:                   strings.ToUpper(id),
+		Type:                   "CA",
+		Side:                   "german",
+		MaxFuel:                12,
+		BaseEvasion:            25,
+		RadarLevel:             1,
+		HullBoxes:              6,
+		BasePrimaryArmamentBow: 4,
+		BaseSecondaryArmament:  2,
+		MaxTorpedos:            2,
+		SpeedType:              "F",
+	}
+}
+
+func TestCheckShipCatalog(t *testing.T) {
+	t.Run("Catalogue", func(t *testing.T) {
+		manager := config.NewShipConfigManager()
+		if err := manager.LoadConfig("../../../config/ships.json"); err != nil {
+			t.Fatalf("Ошибка загрузки конфигурации: %v", err)
+		}
+		ships, _ := manager.GetAllShips()
+		for _, issue := range CheckShipCatalog(ships) {
+			t.Errorf("Ошибка в каталоге кораблей: %s", issue)
+		}
+	})
+
+	tests := []struct {
+		name   string
+		modify func(ship *config.ShipConfig)
+		field  string
+	}{
+		{"UnknownType", func(ship *config.ShipConfig) { ship.Type = "SS" }, "type"},
+		{"UnknownSpeedType", func(ship *config.ShipConfig) { ship.SpeedType = "VF" }, "speedType"},
+		{"UnknownSide", func(ship *config.ShipConfig) { ship.Side = "italian" }, "side"},
+		{"RadarLevel", func(ship *config.ShipConfig) { ship.RadarLevel = 3 }, "radarLevel"},
+		{"NegativeHull", func(ship *config.ShipConfig) { ship.HullBoxes = -1 }, "hullBoxes"},
+		{"CarrierTorpedoes", func(ship *config.ShipConfig) { ship.Type = "CV" }, "maxTorpedos"},
+		{"ArmedTanker", func(ship *config.ShipConfig) { ship.Type, ship.MaxTorpedos = "TK", 0 }, "armament"},
+		{"UnknownRule", func(ship *config.ShipConfig) {
+			ship.SpecialRules = []config.SpecialRuleConfig{{Type: "invisible"}}
+		}, "specialRules"},
+		{"DuplicateRule", func(ship *config.ShipConfig) {
+			rule := config.SpecialRuleConfig{Type: "unreliable_main_armament"}
+			ship.SpecialRules = []config.SpecialRuleConfig{rule, rule}
+		}, "specialRules"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ship := newCatalogTestShip("hipper")
+			tt.modify(&ship)
+			issues := CheckShipCatalog([]config.ShipConfig{ship})
+			if len(issues) != 1 || issues[0].ShipID != "hipper" || issues[0].Field != tt.field {
+				t.Errorf("Ожидалась одна ошибка поля %s, получено %v", tt.field, issues)
+			}
+		})
+	}
+
+	t.Run("DuplicateID", func(t *testing.T) {
+		ships := []config.ShipConfig{newCatalogTestShip("hipper"), newCatalogTestShip("eugen"), newCatalogTestShip("hipper")}
+		issues := CheckShipCatalog(ships)
+		if len(issues) != 1 || issues[0].String() != "hipper: id: duplicate id, first defined at entry 0" {
+			t.Errorf("Ожидалась ошибка повторного ID, получено %v", issues)
+		}
+	})
+
+	t.Run("SortedReport", func(t *testing.T) {
+		first, second := newCatalogTestShip("tirpitz"), newCatalogTestShip("eugen")
+		first.Type, second.Name = "SS", ""
+		issues := CheckShipCatalog([]config.ShipConfig{first, second})
+		if len(issues) != 2 || issues[0].ShipID != "eugen" || issues[1].ShipID != "tirpitz" {
+			t.Errorf("Отчет должен быть отсортирован по ID корабля, получено %v", issues)
+		}
+	})
+}
+
+func TestShipCatalogSchema(t *testing.T) {
+	schema := ShipCatalogSchema()
+	ships := schema["properties"].(map[string]interface{})["ships"].(map[string]interface{})
+	ship := ships["items"].(map[string]interface{})
+	properties := ship["properties"].(map[string]interface{})
+
+	shipType := properties["type"].(map[string]interface{})
+	if enum, ok := shipType["enum"].([]string); !ok || len(enum) != len(catalogUnitTypes()) {
+		t.Errorf("Тип корабля должен быть перечислением, получено %v", shipType)
+	}
+	if _, ok := properties["notes"]; !ok {
+		t.Error("Необязательные поля должны быть в схеме")
+	}
+	for _, name := range ship["required"].([]string) {
+		if name == "notes" || name == "specialRules" {
+			t.Errorf("Поле %s не должно быть обязательным", name)
+		}
+	}
+	if ship["additionalProperties"] != false {
+		t.Error("Неизвестные поля корабля должны быть запрещены")
+	}
+}
@@ -0,0 +1,103 @@
+package services
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"bismarck-game/backend/internal/config"
+)
+
+// CurrentVersion возвращает версию каталога, с которой начинаются новые игры
+func (scs *ShipConfigService) CurrentVersion() string {
+	return scs.configManager.Version()
+}
+
+// ReloadConfig перечитывает каталог кораблей и, если содержимое изменилось и прошло проверку,
+// атомарно делает его текущим. Начатые игры продолжают использовать свою версию
+func (scs *ShipConfigService) ReloadConfig(configPath string) (string, bool, error) {
+	data, err := config.ReadShipsConfig(configPath)
+	if err != nil {
+		return "", false, err
+	}
+	version := config.ShipCatalogVersion(data)
+	if version == scs.configManager.Version() {
+		return version, false, nil
+	}
+
+	ships, err := config.ParseShipsConfig(data)
+	if err != nil {
+		return "", false, err
+	}
+	if issues := CheckShipCatalog(ships.Ships); len(issues) > 0 {
+		return "", false, &config.ConfigError{
+			Message: fmt.Sprintf("каталог кораблей содержит ошибок: %d, первая: %s", len(issues), issues[0]),
+		}
+	}
+
+	if _, err := scs.configManager.LoadData(data); err != nil {
+		return "", false, err
+	}
+
+	scs.logger.Info("Каталог кораблей перезагружен", "path", configPath, "version", version, "shipsCount", len(ships.Ships))
+	return version, true, nil
+}
+
+// WatchConfig периодически проверяет файл каталога и перезагружает его при изменении,
+// пока не закрыт канал stop
+func (scs *ShipConfigService) WatchConfig(configPath string, interval time.Duration, stop <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			if _, _, err := scs.ReloadConfig(configPath); err != nil {
+				scs.logger.Error("Ошибка перезагрузки каталога кораблей", "path", configPath, "error", err)
+			}
+		}
+	}
+}
+
+// SaveVersionTx сохраняет версию каталога, чтобы игра могла использовать ее после перезапуска сервера
+func (scs *ShipConfigService) SaveVersionTx(tx *sql.Tx, version string) error {
+	data, err := scs.configManager.VersionData(version)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO ship_catalogs (version, data)
+		VALUES ($1, $2)
+		ON CONFLICT (version) DO NOTHING`
+	if _, err := tx.Exec(query, version, string(data)); err != nil {
+		return fmt.Errorf("failed to save ship catalog version: %w", err)
+	}
+	return nil
+}
+
+// EnsureVersion загружает сохраненную версию каталога, если ее нет в памяти.
+// Пустая версия означает текущий каталог
+func (scs *ShipConfigService) EnsureVersion(q querier, version string) error {
+	if version == "" || scs.configManager.HasVersion(version) {
+		return nil
+	}
+
+	var data string
+	err := q.QueryRow(`SELECT data FROM ship_catalogs WHERE version = $1`, version).Scan(&data)
+	if errors.Is(err, sql.ErrNoRows) {
+		return config.ErrCatalogVersionNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get ship catalog version: %w", err)
+	}
+
+	if err := scs.configManager.AddVersion(version, []byte(data)); err != nil {
+		return fmt.Errorf("failed to parse ship catalog version %s: %w", version, err)
+	}
+	scs.logger.Info("Загружена сохраненная версия каталога кораблей", "version", version)
+	return nil
+}
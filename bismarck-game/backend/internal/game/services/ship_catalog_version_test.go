@@ -0,0 +1,83 @@
+package services
+
+import (
+	"bismarck-game/backend/internal/config"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeCatalogTestFile(t *testing.T, path string, ships ...config.ShipConfig) {
+	data, err := json.Marshal(config.ShipsConfig{Ships: ships})
+	if err != nil {
+		t.Fatalf("Ошибка сериализации каталога: %v", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatalf("Ошибка записи каталога: %v", err)
+	}
+}
+
+func TestShipCatalogVersions(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ships.json")
+	hipper := newCatalogTestShip("hipper")
+	writeCatalogTestFile(t, path, hipper)
+
+	service := NewShipConfigService(NewSpecialRulesService())
+	if err := service.LoadConfig(path); err != nil {
+		t.Fatalf("Ошибка загрузки конфигурации: %v", err)
+	}
+	started := service.CurrentVersion()
+	if started == "" {
+		t.Fatal("Загруженный каталог должен иметь версию")
+	}
+
+	if _, changed, err := service.ReloadConfig(path); err != nil || changed {
+		t.Errorf("Неизмененный каталог не перезагружается, получено %v, %v", changed, err)
+	}
+
+	hipper.HullBoxes = 9
+	writeCatalogTestFile(t, path, hipper)
+	version, changed, err := service.ReloadConfig(path)
+	if err != nil || !changed || version == started || service.CurrentVersion() != version {
+		t.Fatalf("Измененный каталог должен стать текущей версией, получено %s, %v, %v", version, changed, err)
+	}
+
+	t.Run("StartedGame", func(t *testing.T) {
+		unit, err := service.CreateNavalUnitFromVersion(started, "hipper", "game-1", "german", "P32")
+		if err != nil {
+			t.Fatalf("Ошибка создания юнита: %v", err)
+		}
+		if unit.HullBoxes != 6 {
+			t.Errorf("Начатая игра использует свою версию каталога, ожидалось 6 отсеков, получено %d", unit.HullBoxes)
+		}
+	})
+
+	t.Run("NewGame", func(t *testing.T) {
+		unit, err := service.CreateNavalUnitFromConfig("hipper", "game-2", "german", "P32")
+		if err != nil {
+			t.Fatalf("Ошибка создания юнита: %v", err)
+		}
+		if unit.HullBoxes != 9 {
+			t.Errorf("Новая игра использует текущий каталог, ожидалось 9 отсеков, получено %d", unit.HullBoxes)
+		}
+	})
+
+	t.Run("UnknownVersion", func(t *testing.T) {
+		if _, err := service.CreateNavalUnitFromVersion("missing", "hipper", "game-1", "german", "P32"); !errors.Is(err, config.ErrCatalogVersionNotFound) {
+			t.Errorf("Ожидалась ошибка ErrCatalogVersionNotFound, получено %v", err)
+		}
+	})
+
+	t.Run("InvalidCatalogue", func(t *testing.T) {
+		hipper.SpeedType = "VF"
+		writeCatalogTestFile(t, path, hipper)
+		if _, _, err := service.ReloadConfig(path); err == nil {
+			t.Error("Каталог с ошибками не должен быть загружен")
+		}
+		if service.CurrentVersion() != version {
+			t.Error("После неудачной перезагрузки должна остаться прежняя версия")
+		}
+	})
+}
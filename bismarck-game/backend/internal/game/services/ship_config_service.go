@@ -14,12 +14,13 @@ type ShipConfigService struct {
 	logger              *logger.Logger
 }
 
-// NewShipConfigService создает новый сервис конфигурации кораблей
-func NewShipConfigService() *ShipConfigService {
+// NewShipConfigService создает новый сервис конфигурации кораблей.
+// Правила кораблей привязываются к юнитам при создании и хранятся в specialRulesService
+func NewShipConfigService(specialRulesService *SpecialRulesService) *ShipConfigService {
 	log, _ := logger.New(logger.INFO, "ship-config-service", "stdout")
 	return &ShipConfigService{
 		configManager:       config.NewShipConfigManager(),
-		specialRulesService: NewSpecialRulesService(),
+		specialRulesService: specialRulesService,
 		logger:              log,
 	}
 }
@@ -33,27 +34,28 @@ func (scs *ShipConfigService) LoadConfig(configPath string) error {
 		return err
 	}
 
-	// Регистрируем специальные правила для всех кораблей
 	allShips, err := scs.configManager.GetAllShips()
 	if err != nil {
 		scs.logger.Error("Ошибка получения всех кораблей", "error", err)
 		return err
 	}
 
-	for _, ship := range allShips {
-		scs.specialRulesService.RegisterShipSpecialRules(&ship)
-	}
-
-	scs.logger.Info("Конфигурация кораблей и специальные правила успешно загружены",
-		"shipsCount", len(allShips))
+	scs.logger.Info("Конфигурация кораблей успешно загружена",
+		"shipsCount", len(allShips), "version", scs.configManager.Version())
 	return nil
 }
 
-// CreateNavalUnitFromConfig создает морской юнит из конфигурации
+// CreateNavalUnitFromConfig создает морской юнит из текущего каталога
 func (scs *ShipConfigService) CreateNavalUnitFromConfig(shipID, gameID, owner string, position string) (*models.NavalUnit, error) {
-	shipConfig, err := scs.configManager.GetShipConfig(shipID)
+	return scs.CreateNavalUnitFromVersion("", shipID, gameID, owner, position)
+}
+
+// CreateNavalUnitFromVersion создает морской юнит из указанной версии каталога,
+// чтобы изменения каталога не затрагивали уже начатые игры
+func (scs *ShipConfigService) CreateNavalUnitFromVersion(version, shipID, gameID, owner string, position string) (*models.NavalUnit, error) {
+	shipConfig, err := scs.configManager.GetVersionShipConfig(version, shipID)
 	if err != nil {
-		scs.logger.Error("Ошибка получения конфигурации корабля", "shipID", shipID, "error", err)
+		scs.logger.Error("Ошибка получения конфигурации корабля", "shipID", shipID, "version", version, "error", err)
 		return nil, err
 	}
 
@@ -70,6 +72,7 @@ func (scs *ShipConfigService) CreateNavalUnitFromConfig(shipID, gameID, owner st
 		Fuel:                     shipConfig.MaxFuel, // Начинаем с полным баком
 		BaseEvasion:              shipConfig.BaseEvasion,
 		Evasion:                  shipConfig.BaseEvasion,
+		SpeedRating:              models.SpeedType(shipConfig.SpeedType),
 		RadarLevel:               shipConfig.RadarLevel,
 		HullBoxes:                shipConfig.HullBoxes,
 		CurrentHull:              shipConfig.HullBoxes, // Начинаем без повреждений
@@ -83,6 +86,7 @@ func (scs *ShipConfigService) CreateNavalUnitFromConfig(shipID, gameID, owner st
 		Torpedoes:                shipConfig.MaxTorpedos,
 		Status:                   models.UnitStatusActive,
 		DetectionLevel:           models.DetectionLevelNone,
+		SpecialRules:             specialRulesFromConfig(shipConfig),
 		CreatedAt:                time.Now(),
 		UpdatedAt:                time.Now(),
 	}
@@ -95,6 +99,11 @@ func (scs *ShipConfigService) CreateNavalUnitFromConfig(shipID, gameID, owner st
 	return navalUnit, nil
 }
 
+// GetShipConfig возвращает конфигурацию корабля из текущего каталога
+func (scs *ShipConfigService) GetShipConfig(shipID string) (*config.ShipConfig, error) {
+	return scs.configManager.GetShipConfig(shipID)
+}
+
 // GetAvailableShips возвращает список доступных кораблей для стороны
 func (scs *ShipConfigService) GetAvailableShips(side string) ([]config.ShipConfig, error) {
 	var ships []config.ShipConfig
@@ -164,34 +173,10 @@ func (scs *ShipConfigService) GetConfigStats() (*config.ConfigStats, error) {
 	return stats, nil
 }
 
-// ValidateShipConfig проверяет корректность конфигурации корабля
+// ValidateShipConfig проверяет корректность конфигурации корабля теми же правилами, что и cmd/shipcheck
 func (scs *ShipConfigService) ValidateShipConfig(shipConfig *config.ShipConfig) error {
-	if shipConfig.ID == "" {
-		return &config.ConfigError{Message: "ID корабля не может быть пустым"}
-	}
-
-	if shipConfig.Name == "" {
-		return &config.ConfigError{Message: "название корабля не может быть пустым"}
-	}
-
-	if shipConfig.Type == "" {
-		return &config.ConfigError{Message: "тип корабля не может быть пустым"}
-	}
-
-	if shipConfig.Side == "" {
-		return &config.ConfigError{Message: "сторона корабля не может быть пустой"}
-	}
-
-	if shipConfig.MaxFuel < 0 {
-		return &config.ConfigError{Message: "максимальное топливо не может быть отрицательным"}
-	}
-
-	if shipConfig.BaseEvasion < 0 {
-		return &config.ConfigError{Message: "базовое уклонение не может быть отрицательным"}
-	}
-
-	if shipConfig.HullBoxes < 0 {
-		return &config.ConfigError{Message: "количество корпусных отсеков не может быть отрицательным"}
+	if issues := checkShipConfig(shipConfig, catalogShipID(shipConfig, 0)); len(issues) > 0 {
+		return &config.ConfigError{Message: issues[0].String()}
 	}
 
 	scs.logger.Debug("Конфигурация корабля валидна", "shipID", shipConfig.ID)
@@ -203,14 +188,19 @@ func generateUnitID() string {
 	return "unit_" + time.Now().Format("20060102150405") + "_" + randomString(6)
 }
 
+// GetConfigManager возвращает каталог кораблей
+func (scs *ShipConfigService) GetConfigManager() *config.ShipConfigManager {
+	return scs.configManager
+}
+
 // GetSpecialRulesService возвращает сервис специальных правил
 func (scs *ShipConfigService) GetSpecialRulesService() *SpecialRulesService {
 	return scs.specialRulesService
 }
 
 // ApplySpecialRulesToUnit применяет специальные правила к юниту
-func (scs *ShipConfigService) ApplySpecialRulesToUnit(unit *models.NavalUnit, context map[string]interface{}) {
-	scs.specialRulesService.ApplySpecialRulesToUnit(unit, context)
+func (scs *ShipConfigService) ApplySpecialRulesToUnit(unit *models.NavalUnit, ctx models.CombatContext) error {
+	return scs.specialRulesService.ApplySpecialRulesToUnit(unit, ctx)
 }
 
 // GetUnitSpecialRules возвращает специальные правила для юнита
@@ -8,7 +8,7 @@ import (
 
 func TestShipConfigService(t *testing.T) {
 	// Создаем сервис конфигурации кораблей
-	service := NewShipConfigService()
+	service := NewShipConfigService(NewSpecialRulesService())
 
 	// Загружаем конфигурацию
 	err := service.LoadConfig("../../../config/ships.json")
@@ -59,10 +59,9 @@ func TestShipConfigService(t *testing.T) {
 		t.Error("У Бисмарка должны быть специальные правила")
 	}
 
-	// Проверяем, что специальные правила зарегистрированы
-	specialRules := service.GetUnitSpecialRules("bismarck")
-	if specialRules == nil {
-		t.Error("Специальные правила для Бисмарка не зарегистрированы")
+	// Правила хранятся по ID юнита игры, а не по ID корабля в каталоге
+	if service.GetUnitSpecialRules("bismarck") != nil {
+		t.Error("Специальные правила не должны регистрироваться по ID корабля в каталоге")
 	}
 
 	// Проверяем корабли по стороне
@@ -119,7 +118,7 @@ func TestShipConfigValidation(t *testing.T) {
 	}
 
 	// Создаем сервис для тестирования валидации
-	service := NewShipConfigService()
+	service := NewShipConfigService(NewSpecialRulesService())
 	
 	// Валидация должна пройти успешно
 	err := service.ValidateShipConfig(validConfig)
@@ -147,7 +146,7 @@ func TestShipConfigValidation(t *testing.T) {
 }
 
 func TestCreateNavalUnitFromConfig(t *testing.T) {
-	service := NewShipConfigService()
+	service := NewShipConfigService(NewSpecialRulesService())
 	
 	// Загружаем конфигурацию
 	err := service.LoadConfig("../../../config/ships.json")
@@ -1,18 +1,26 @@
 package services
 
 import (
+	"encoding/json"
+	"fmt"
+	"time"
+
 	"bismarck-game/backend/internal/config"
 	"bismarck-game/backend/internal/game/models"
+	"bismarck-game/backend/pkg/database"
 	"bismarck-game/backend/pkg/logger"
 )
 
-// SpecialRulesService предоставляет методы для работы со специальными правилами
+// SpecialRulesService предоставляет методы для работы со специальными правилами юнитов игры.
+// Правила и их состояние хранятся в базе данных по ID юнита, если она задана;
+// менеджер правил служит кэшем загруженных игр
 type SpecialRulesService struct {
 	ruleManager *models.SpecialRuleManager
+	db          *database.Database
 	logger      *logger.Logger
 }
 
-// NewSpecialRulesService создает новый сервис специальных правил
+// NewSpecialRulesService создает новый сервис специальных правил без хранения состояния
 func NewSpecialRulesService() *SpecialRulesService {
 	log, _ := logger.New(logger.INFO, "special-rules-service", "stdout")
 	return &SpecialRulesService{
@@ -21,188 +29,196 @@ func NewSpecialRulesService() *SpecialRulesService {
 	}
 }
 
-// RegisterShipSpecialRules регистрирует специальные правила для корабля
-func (srs *SpecialRulesService) RegisterShipSpecialRules(shipConfig *config.ShipConfig) {
-	if len(shipConfig.SpecialRules) == 0 {
-		return
+// NewGameSpecialRulesService создает сервис специальных правил, сохраняющий состояние правил юнитов игры
+func NewGameSpecialRulesService(db *database.Database, logger *logger.Logger) *SpecialRulesService {
+	return &SpecialRulesService{
+		ruleManager: models.NewSpecialRuleManager(),
+		db:          db,
+		logger:      logger,
 	}
+}
 
-	// Преобразуем конфигурационные правила в модели
+// specialRulesFromConfig преобразует конфигурационные правила корабля в модели
+func specialRulesFromConfig(shipConfig *config.ShipConfig) []models.SpecialRule {
 	var rules []models.SpecialRule
 	for _, ruleConfig := range shipConfig.SpecialRules {
-		rule := models.SpecialRule{
+		rules = append(rules, models.SpecialRule{
 			Type:        models.SpecialRuleType(ruleConfig.Type),
 			Description: ruleConfig.Description,
 			IsActive:    ruleConfig.IsActive,
-		}
-		rules = append(rules, rule)
+		})
 	}
+	return rules
+}
 
-	// Регистрируем правила для корабля (используем ID корабля как ключ)
-	srs.ruleManager.RegisterUnitRules(shipConfig.ID, rules)
+// LoadGameRules загружает сохраненные правила и их состояние для всех юнитов игры
+func (srs *SpecialRulesService) LoadGameRules(gameID string) error {
+	query := `
+		SELECT game_id, unit_id, rules, rule_states, updated_at
+		FROM naval_unit_special_rules
+		WHERE game_id = $1`
 
-	srs.logger.Info("Зарегистрированы специальные правила для корабля",
-		"shipID", shipConfig.ID,
-		"shipName", shipConfig.Name,
-		"rulesCount", len(rules))
+	return srs.loadRules(query, gameID)
 }
 
-// ApplySpecialRulesToUnit применяет специальные правила к юниту
-func (srs *SpecialRulesService) ApplySpecialRulesToUnit(unit *models.NavalUnit, context map[string]interface{}) {
-	unitRules := srs.ruleManager.GetUnitRules(unit.ID)
-	if unitRules == nil {
-		return
-	}
-
-	for _, rule := range unitRules.Rules {
-		if !rule.IsActive {
-			continue
-		}
+// LoadActiveGameRules загружает правила юнитов всех активных игр после запуска сервера
+func (srs *SpecialRulesService) LoadActiveGameRules() error {
+	query := `
+		SELECT r.game_id, r.unit_id, r.rules, r.rule_states, r.updated_at
+		FROM naval_unit_special_rules r
+		JOIN games g ON g.id = r.game_id
+		WHERE g.status = $1`
 
-		// Проверяем условия для активации правила
-		if srs.ruleManager.CheckRuleConditions(unit.ID, rule.Type, context) {
-			// Применяем эффекты правила
-			srs.ruleManager.ApplyRuleEffects(unit, rule.Type, context)
+	return srs.loadRules(query, models.GameStatusActive)
+}
 
-			// Отмечаем правило как активированное
-			srs.ruleManager.TriggerRule(unit.ID, rule.Type, context)
+// loadRules загружает правила юнитов, выбранные запросом, в менеджер правил
+func (srs *SpecialRulesService) loadRules(query string, args ...interface{}) error {
+	if srs.db == nil {
+		return nil
+	}
 
-			srs.logger.Debug("Применено специальное правило",
-				"unitID", unit.ID,
-				"ruleType", rule.Type,
-				"context", context)
+	rows, err := srs.db.Query(query, args...)
+	if err != nil {
+		srs.logger.Error("Failed to load special rules", "error", err)
+		return fmt.Errorf("failed to load special rules: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var gameID, unitID string
+		var rulesJSON, statesJSON []byte
+		var updatedAt time.Time
+		if err := rows.Scan(&gameID, &unitID, &rulesJSON, &statesJSON, &updatedAt); err != nil {
+			return fmt.Errorf("failed to scan special rules: %w", err)
 		}
+		unitRules, err := decodeUnitSpecialRules(gameID, unitID, rulesJSON, statesJSON, updatedAt)
+		if err != nil {
+			return err
+		}
+		srs.ruleManager.SetUnitRules(unitRules)
 	}
+
+	return rows.Err()
 }
 
-// CheckUnreliableMainArmament проверяет ненадежное главное вооружение
-func (srs *SpecialRulesService) CheckUnreliableMainArmament(unit *models.NavalUnit, context map[string]interface{}) bool {
+// ApplySpecialRulesToUnit применяет специальные правила к юниту в обстановке боя.
+// Раунды до текущего считаются завершенными, поэтому правила, активируемые после раунда,
+// проверяются здесь же; постоянные эффекты активированных правил применяются всегда
+func (srs *SpecialRulesService) ApplySpecialRulesToUnit(unit *models.NavalUnit, ctx models.CombatContext) error {
 	unitRules := srs.ruleManager.GetUnitRules(unit.ID)
 	if unitRules == nil {
-		return false
+		return nil
 	}
 
-	rule := unitRules.GetSpecialRule(models.SpecialRuleUnreliableMainArmament)
-	if rule == nil || !rule.IsActive {
-		return false
+	if ctx.Round > 1 {
+		if err := srs.applyAfterRound(unitRules, unit, previousRound(ctx)); err != nil {
+			return err
+		}
+	} else {
+		unitRules.ApplyTriggered(unit)
 	}
-
-	// Логика для ненадежного вооружения
-	// В реальной игре это может включать:
-	// - Снижение точности стрельбы
-	// - Возможность заклинивания орудий
-	// - Увеличение времени перезарядки
-
-	srs.logger.Debug("Проверка ненадежного главного вооружения",
-		"unitID", unit.ID,
-		"unitName", unit.Name)
-
-	return true
+	unitRules.ApplyArmament(unit, ctx)
+	return nil
 }
 
-// CheckSternGunsInitialPhaseOnly проверяет кормовые орудия только в начальной фазе
-func (srs *SpecialRulesService) CheckSternGunsInitialPhaseOnly(unit *models.NavalUnit, context map[string]interface{}) bool {
+// PreFire применяет специальные правила юнита к стрельбе перед броском
+func (srs *SpecialRulesService) PreFire(unit *models.NavalUnit, ctx models.CombatContext, roll *models.FireRoll) {
 	unitRules := srs.ruleManager.GetUnitRules(unit.ID)
 	if unitRules == nil {
-		return false
+		return
 	}
+	unitRules.ApplyPreFire(unit, ctx, roll)
+}
 
-	rule := unitRules.GetSpecialRule(models.SpecialRuleSternGunsInitialPhaseOnly)
-	if rule == nil || !rule.IsActive {
-		return false
+// EndRound проверяет специальные правила юнитов после раунда боя
+func (srs *SpecialRulesService) EndRound(units []*models.NavalUnit, ctx models.CombatContext) error {
+	for _, unit := range units {
+		unitRules := srs.ruleManager.GetUnitRules(unit.ID)
+		if unitRules == nil {
+			continue
+		}
+		if err := srs.applyAfterRound(unitRules, unit, ctx); err != nil {
+			return err
+		}
 	}
+	return nil
+}
 
-	phase, ok := context["battle_phase"].(string)
-	if !ok {
-		return false
+// applyAfterRound активирует правила юнита после раунда и сохраняет их состояние
+func (srs *SpecialRulesService) applyAfterRound(unitRules *models.NavalUnitSpecialRules, unit *models.NavalUnit, ctx models.CombatContext) error {
+	triggered := unitRules.ApplyAfterRound(unit, ctx)
+	if len(triggered) == 0 {
+		return nil
 	}
 
-	// Если не начальная фаза, отключаем кормовые орудия
-	if phase != "initial" {
-		unit.PrimaryArmamentStern = 0
-		srs.logger.Debug("Кормовые орудия отключены (не начальная фаза)",
-			"unitID", unit.ID,
-			"phase", phase)
-		return true
+	if err := srs.saveRuleStates(unitRules); err != nil {
+		srs.logger.Error("Failed to save special rule state", "unit_id", unit.ID, "error", err)
+		return err
 	}
 
-	// В начальной фазе восстанавливаем кормовые орудия
-	unit.PrimaryArmamentStern = unit.BasePrimaryArmamentStern
-	srs.logger.Debug("Кормовые орудия активны (начальная фаза)",
+	srs.logger.Debug("Применены специальные правила после раунда",
 		"unitID", unit.ID,
-		"phase", phase)
-
-	return true
+		"rules", triggered,
+		"round", ctx.Round)
+	return nil
 }
 
-// CheckNoMainGunsExtremeRange проверяет отсутствие главного калибра на экстремальной дистанции
-func (srs *SpecialRulesService) CheckNoMainGunsExtremeRange(unit *models.NavalUnit, context map[string]interface{}) bool {
+// applyRule применяет к юниту эффекты одного правила без сохранения состояния.
+// Возвращает false, если у юнита нет активного правила
+func (srs *SpecialRulesService) applyRule(unit *models.NavalUnit, ruleType models.SpecialRuleType, ctx models.CombatContext) bool {
 	unitRules := srs.ruleManager.GetUnitRules(unit.ID)
 	if unitRules == nil {
 		return false
 	}
 
-	rule := unitRules.GetSpecialRule(models.SpecialRuleNoMainGunsExtremeRange)
+	rule := unitRules.GetSpecialRule(ruleType)
 	if rule == nil || !rule.IsActive {
 		return false
 	}
 
-	rangeType, ok := context["range"].(string)
-	if !ok {
-		return false
-	}
-
-	// Если экстремальная дистанция, отключаем главный калибр
-	if rangeType == "extreme" {
-		unit.PrimaryArmamentBow = 0
-		unit.PrimaryArmamentStern = 0
-		srs.logger.Debug("Главный калибр отключен (экстремальная дистанция)",
-			"unitID", unit.ID,
-			"range", rangeType)
-		return true
+	single := &models.NavalUnitSpecialRules{UnitID: unit.ID, Rules: []models.SpecialRule{*rule}}
+	single.ApplyArmament(unit, ctx)
+	if ctx.Round > 1 {
+		single.ApplyAfterRound(unit, previousRound(ctx))
 	}
 
-	// На других дистанциях восстанавливаем главный калибр
-	unit.PrimaryArmamentBow = unit.BasePrimaryArmamentBow
-	unit.PrimaryArmamentStern = unit.BasePrimaryArmamentStern
-	srs.logger.Debug("Главный калибр активен",
+	srs.logger.Debug("Проверка специального правила",
 		"unitID", unit.ID,
-		"range", rangeType)
+		"ruleType", ruleType,
+		"context", ctx)
 
 	return true
 }
 
-// CheckRadarLossAfterFirstRound проверяет потерю радара после первого раунда
-func (srs *SpecialRulesService) CheckRadarLossAfterFirstRound(unit *models.NavalUnit, context map[string]interface{}) bool {
-	unitRules := srs.ruleManager.GetUnitRules(unit.ID)
-	if unitRules == nil {
-		return false
-	}
+// ApplyUnreliableMainArmament применяет к юниту правило ненадежного главного вооружения.
+// Эффект правила применяется к стрельбе в PreFire
+func (srs *SpecialRulesService) ApplyUnreliableMainArmament(unit *models.NavalUnit, ctx models.CombatContext) bool {
+	return srs.applyRule(unit, models.SpecialRuleUnreliableMainArmament, ctx)
+}
 
-	rule := unitRules.GetSpecialRule(models.SpecialRuleRadarLossAfterFirstRound)
-	if rule == nil || !rule.IsActive {
+// ApplySternGunsInitialPhaseOnly отключает кормовой главный калибр юнита вне начальной фазы боя
+func (srs *SpecialRulesService) ApplySternGunsInitialPhaseOnly(unit *models.NavalUnit, ctx models.CombatContext) bool {
+	if ctx.Phase == "" {
 		return false
 	}
+	return srs.applyRule(unit, models.SpecialRuleSternGunsInitialPhaseOnly, ctx)
+}
 
-	round, ok := context["battle_round"].(int)
-	if !ok {
+// ApplyNoMainGunsExtremeRange отключает главный калибр юнита на экстремальной дистанции
+func (srs *SpecialRulesService) ApplyNoMainGunsExtremeRange(unit *models.NavalUnit, ctx models.CombatContext) bool {
+	if ctx.Range == "" {
 		return false
 	}
+	return srs.applyRule(unit, models.SpecialRuleNoMainGunsExtremeRange, ctx)
+}
 
-	// Если раунд больше первого, отключаем радар
-	if round > 1 {
-		unit.RadarLevel = 0
-		srs.logger.Debug("Радар отключен (после первого раунда)",
-			"unitID", unit.ID,
-			"round", round)
-		return true
+// ApplyRadarLossAfterFirstRound отключает радар юнита после первого раунда боя
+func (srs *SpecialRulesService) ApplyRadarLossAfterFirstRound(unit *models.NavalUnit, ctx models.CombatContext) bool {
+	if ctx.Round == 0 {
+		return false
 	}
-
-	srs.logger.Debug("Радар активен (первый раунд)",
-		"unitID", unit.ID,
-		"round", round)
-
-	return true
+	return srs.applyRule(unit, models.SpecialRuleRadarLossAfterFirstRound, ctx)
 }
 
 // GetUnitSpecialRules возвращает специальные правила для юнита
@@ -237,33 +253,104 @@ func (srs *SpecialRulesService) GetRuleDescription(unitID string, ruleType model
 }
 
 // ProcessBattlePhase обрабатывает специальные правила для фазы боя
-func (srs *SpecialRulesService) ProcessBattlePhase(units []*models.NavalUnit, phase string, round int) {
-	context := map[string]interface{}{
-		"battle_phase": phase,
-		"battle_round": round,
-	}
+func (srs *SpecialRulesService) ProcessBattlePhase(units []*models.NavalUnit, phase string, round int) error {
+	ctx := models.CombatContext{Phase: models.CombatPhase(phase), Round: round}
 
 	for _, unit := range units {
-		srs.ApplySpecialRulesToUnit(unit, context)
+		if err := srs.ApplySpecialRulesToUnit(unit, ctx); err != nil {
+			return err
+		}
 	}
 
 	srs.logger.Info("Обработаны специальные правила для фазы боя",
 		"phase", phase,
 		"round", round,
 		"unitsCount", len(units))
+	return nil
 }
 
 // ProcessRangeChange обрабатывает специальные правила при изменении дистанции
-func (srs *SpecialRulesService) ProcessRangeChange(units []*models.NavalUnit, rangeType string) {
-	context := map[string]interface{}{
-		"range": rangeType,
-	}
+func (srs *SpecialRulesService) ProcessRangeChange(units []*models.NavalUnit, rangeType string) error {
+	ctx := models.CombatContext{Range: models.CombatRange(rangeType)}
 
 	for _, unit := range units {
-		srs.ApplySpecialRulesToUnit(unit, context)
+		if err := srs.ApplySpecialRulesToUnit(unit, ctx); err != nil {
+			return err
+		}
 	}
 
 	srs.logger.Info("Обработаны специальные правила для дистанции",
 		"range", rangeType,
 		"unitsCount", len(units))
+	return nil
+}
+
+// previousRound возвращает обстановку завершенного предыдущего раунда боя
+func previousRound(ctx models.CombatContext) models.CombatContext {
+	ctx.Round--
+	return ctx
+}
+
+// saveRuleStates сохраняет состояние правил юнита
+func (srs *SpecialRulesService) saveRuleStates(unitRules *models.NavalUnitSpecialRules) error {
+	if srs.db == nil {
+		return nil
+	}
+
+	_, statesJSON, err := encodeUnitSpecialRules(unitRules)
+	if err != nil {
+		return err
+	}
+	query := `UPDATE naval_unit_special_rules SET rule_states = $2, updated_at = $3 WHERE unit_id = $1`
+	if _, err := srs.db.Exec(query, unitRules.UnitID, statesJSON, unitRules.UpdatedAt); err != nil {
+		return fmt.Errorf("failed to save special rule state: %w", err)
+	}
+	return nil
+}
+
+// insertUnitSpecialRules сохраняет правила, привязанные к новому юниту игры
+func insertUnitSpecialRules(q querier, unit *models.NavalUnit) error {
+	if len(unit.SpecialRules) == 0 {
+		return nil
+	}
+
+	unitRules := models.NewNavalUnitSpecialRules(unit)
+	rulesJSON, statesJSON, err := encodeUnitSpecialRules(unitRules)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO naval_unit_special_rules (unit_id, game_id, rules, rule_states, updated_at)
+		VALUES ($1, $2, $3, $4, $5)`
+
+	if _, err := q.Exec(query, unitRules.UnitID, unitRules.GameID, rulesJSON, statesJSON, unitRules.UpdatedAt); err != nil {
+		return fmt.Errorf("failed to save unit special rules: %w", err)
+	}
+	return nil
+}
+
+// encodeUnitSpecialRules преобразует правила юнита и их состояние в JSON для хранения
+func encodeUnitSpecialRules(unitRules *models.NavalUnitSpecialRules) ([]byte, []byte, error) {
+	rulesJSON, err := json.Marshal(unitRules.Rules)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to marshal special rules: %w", err)
+	}
+	statesJSON, err := json.Marshal(unitRules.RuleStates)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to marshal special rule states: %w", err)
+	}
+	return rulesJSON, statesJSON, nil
+}
+
+// decodeUnitSpecialRules восстанавливает сохраненные правила юнита и их состояние
+func decodeUnitSpecialRules(gameID, unitID string, rulesJSON, statesJSON []byte, updatedAt time.Time) (*models.NavalUnitSpecialRules, error) {
+	unitRules := &models.NavalUnitSpecialRules{GameID: gameID, UnitID: unitID, UpdatedAt: updatedAt}
+	if err := json.Unmarshal(rulesJSON, &unitRules.Rules); err != nil {
+		return nil, fmt.Errorf("failed to parse special rules: %w", err)
+	}
+	if err := json.Unmarshal(statesJSON, &unitRules.RuleStates); err != nil {
+		return nil, fmt.Errorf("failed to parse special rule states: %w", err)
+	}
+	return unitRules, nil
 }
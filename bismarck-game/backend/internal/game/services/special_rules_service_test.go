@@ -3,6 +3,7 @@ package services
 import (
 	"bismarck-game/backend/internal/game/models"
 	"testing"
+	"time"
 )
 
 func TestSpecialRulesService(t *testing.T) {
@@ -45,8 +46,7 @@ func TestSpecialRulesService(t *testing.T) {
 
 	// Тест 1: Проверка ненадежного главного вооружения
 	t.Run("UnreliableMainArmament", func(t *testing.T) {
-		context := map[string]interface{}{}
-		result := service.CheckUnreliableMainArmament(unit, context)
+		result := service.ApplyUnreliableMainArmament(unit, models.CombatContext{})
 		if !result {
 			t.Error("Ненадежное главное вооружение должно быть активно")
 		}
@@ -54,10 +54,8 @@ func TestSpecialRulesService(t *testing.T) {
 
 	// Тест 2: Проверка кормовых орудий в начальной фазе
 	t.Run("SternGunsInitialPhase", func(t *testing.T) {
-		context := map[string]interface{}{
-			"battle_phase": "initial",
-		}
-		result := service.CheckSternGunsInitialPhaseOnly(unit, context)
+		ctx := models.CombatContext{Phase: models.CombatPhaseInitial}
+		result := service.ApplySternGunsInitialPhaseOnly(unit, ctx)
 		if !result {
 			t.Error("Кормовые орудия должны быть активны в начальной фазе")
 		}
@@ -68,10 +66,8 @@ func TestSpecialRulesService(t *testing.T) {
 
 	// Тест 3: Проверка кормовых орудий не в начальной фазе
 	t.Run("SternGunsNotInitialPhase", func(t *testing.T) {
-		context := map[string]interface{}{
-			"battle_phase": "main",
-		}
-		result := service.CheckSternGunsInitialPhaseOnly(unit, context)
+		ctx := models.CombatContext{Phase: models.CombatPhaseMain}
+		result := service.ApplySternGunsInitialPhaseOnly(unit, ctx)
 		if !result {
 			t.Error("Правило должно быть активно")
 		}
@@ -85,10 +81,8 @@ func TestSpecialRulesService(t *testing.T) {
 		// Восстанавливаем радар для теста
 		unit.RadarLevel = 2
 
-		context := map[string]interface{}{
-			"battle_round": 2,
-		}
-		result := service.CheckRadarLossAfterFirstRound(unit, context)
+		ctx := models.CombatContext{Round: 2}
+		result := service.ApplyRadarLossAfterFirstRound(unit, ctx)
 		if !result {
 			t.Error("Правило потери радара должно быть активно")
 		}
@@ -102,10 +96,8 @@ func TestSpecialRulesService(t *testing.T) {
 		// Восстанавливаем радар для теста
 		unit.RadarLevel = 2
 
-		context := map[string]interface{}{
-			"battle_round": 1,
-		}
-		result := service.CheckRadarLossAfterFirstRound(unit, context)
+		ctx := models.CombatContext{Round: 1}
+		result := service.ApplyRadarLossAfterFirstRound(unit, ctx)
 		if !result {
 			t.Error("Правило должно быть активно")
 		}
@@ -143,10 +135,8 @@ func TestSpecialRulesService_NoMainGunsExtremeRange(t *testing.T) {
 
 	// Тест 1: Экстремальная дистанция
 	t.Run("ExtremeRange", func(t *testing.T) {
-		context := map[string]interface{}{
-			"range": "extreme",
-		}
-		result := service.CheckNoMainGunsExtremeRange(unit, context)
+		ctx := models.CombatContext{Range: models.CombatRangeExtreme}
+		result := service.ApplyNoMainGunsExtremeRange(unit, ctx)
 		if !result {
 			t.Error("Правило должно быть активно")
 		}
@@ -157,10 +147,8 @@ func TestSpecialRulesService_NoMainGunsExtremeRange(t *testing.T) {
 
 	// Тест 2: Обычная дистанция
 	t.Run("NormalRange", func(t *testing.T) {
-		context := map[string]interface{}{
-			"range": "long",
-		}
-		result := service.CheckNoMainGunsExtremeRange(unit, context)
+		ctx := models.CombatContext{Range: models.CombatRangeLong}
+		result := service.ApplyNoMainGunsExtremeRange(unit, ctx)
 		if !result {
 			t.Error("Правило должно быть активно")
 		}
@@ -221,7 +209,9 @@ func TestSpecialRulesService_ProcessBattlePhase(t *testing.T) {
 		units[1].RadarLevel = 0
 
 		// Обрабатываем начальную фазу, раунд 1
-		service.ProcessBattlePhase(units, "initial", 1)
+		if err := service.ProcessBattlePhase(units, "initial", 1); err != nil {
+			t.Fatalf("Ошибка применения специальных правил: %v", err)
+		}
 
 		// Проверяем, что кормовые орудия Rodney активны
 		if units[0].PrimaryArmamentStern != 5 {
@@ -229,7 +219,9 @@ func TestSpecialRulesService_ProcessBattlePhase(t *testing.T) {
 		}
 
 		// Обрабатываем основную фазу, раунд 2
-		service.ProcessBattlePhase(units, "main", 2)
+		if err := service.ProcessBattlePhase(units, "main", 2); err != nil {
+			t.Fatalf("Ошибка применения специальных правил: %v", err)
+		}
 
 		// Проверяем, что кормовые орудия Rodney отключены
 		if units[0].PrimaryArmamentStern != 0 {
@@ -237,3 +229,133 @@ func TestSpecialRulesService_ProcessBattlePhase(t *testing.T) {
 		}
 	})
 }
+
+func TestRestoredSpecialRuleState(t *testing.T) {
+	service := NewSpecialRulesService()
+
+	unit := &models.NavalUnit{
+		ID:         "prince_of_wales",
+		GameID:     "game",
+		RadarLevel: 2,
+		SpecialRules: []models.SpecialRule{
+			{Type: models.SpecialRuleRadarLossAfterFirstRound, IsActive: true},
+		},
+	}
+
+	// Правило, активированное до перезапуска сервера
+	unitRules := models.NewNavalUnitSpecialRules(unit)
+	unitRules.SetRuleState(models.SpecialRuleRadarLossAfterFirstRound, true, nil)
+	service.ruleManager.SetUnitRules(unitRules)
+
+	if err := service.ApplySpecialRulesToUnit(unit, models.CombatContext{Phase: models.CombatPhaseInitial, Round: 1}); err != nil {
+		t.Fatalf("Ошибка применения специальных правил: %v", err)
+	}
+	if unit.RadarLevel != 0 {
+		t.Errorf("Потерянный радар не должен восстанавливаться, уровень %d", unit.RadarLevel)
+	}
+
+	if unitRules.GameID != "game" || len(unitRules.Rules) != 1 {
+		t.Error("Правила корабля должны быть привязаны к юниту игры")
+	}
+}
+
+func TestSpecialRuleStateRoundTrip(t *testing.T) {
+	service := NewSpecialRulesService()
+
+	pow := &models.NavalUnit{
+		ID:         "unit-pow",
+		GameID:     "game",
+		RadarLevel: 2,
+		SpecialRules: []models.SpecialRule{
+			{Type: models.SpecialRuleRadarLossAfterFirstRound, IsActive: true},
+		},
+	}
+	service.ruleManager.SetUnitRules(models.NewNavalUnitSpecialRules(pow))
+	if err := service.EndRound([]*models.NavalUnit{pow}, models.CombatContext{Round: 1}); err != nil {
+		t.Fatalf("Ошибка применения специальных правил: %v", err)
+	}
+
+	// Состояние сохраняется и загружается после перезапуска сервера в том же виде
+	rulesJSON, statesJSON, err := encodeUnitSpecialRules(service.GetUnitSpecialRules(pow.ID))
+	if err != nil {
+		t.Fatalf("Ошибка сохранения правил: %v", err)
+	}
+	unitRules, err := decodeUnitSpecialRules(pow.GameID, pow.ID, rulesJSON, statesJSON, time.Now())
+	if err != nil {
+		t.Fatalf("Ошибка загрузки правил: %v", err)
+	}
+
+	reloaded := NewSpecialRulesService()
+	reloaded.ruleManager.SetUnitRules(unitRules)
+	if !reloaded.IsRuleActive(pow.ID, models.SpecialRuleRadarLossAfterFirstRound) {
+		t.Error("Правило должно загружаться по ID юнита")
+	}
+
+	restored := &models.NavalUnit{ID: pow.ID, GameID: pow.GameID, RadarLevel: 2}
+	if err := reloaded.ApplySpecialRulesToUnit(restored, models.CombatContext{Phase: models.CombatPhaseInitial, Round: 1}); err != nil {
+		t.Fatalf("Ошибка применения специальных правил: %v", err)
+	}
+	if restored.RadarLevel != 0 {
+		t.Errorf("Потерянный радар не должен восстанавливаться после загрузки, уровень %d", restored.RadarLevel)
+	}
+}
+
+func TestSpecialRuleHooks(t *testing.T) {
+	service := NewSpecialRulesService()
+
+	pow := &models.NavalUnit{ID: "prince_of_wales", BasePrimaryArmamentBow: 6, PrimaryArmamentBow: 6}
+	bismarck := &models.NavalUnit{ID: "bismarck", RadarLevel: 2}
+	service.ruleManager.RegisterUnitRules(pow.ID, []models.SpecialRule{
+		{Type: models.SpecialRuleUnreliableMainArmament, IsActive: true},
+	})
+	service.ruleManager.RegisterUnitRules(bismarck.ID, []models.SpecialRule{
+		{Type: models.SpecialRuleRadarLossAfterFirstRound, IsActive: true},
+	})
+
+	t.Run("PreFire", func(t *testing.T) {
+		ctx := models.CombatContext{Phase: models.CombatPhaseInitial, Round: 1, Range: models.CombatRangeLong}
+
+		jammed := &models.FireRoll{MainArmament: true, Factor: 6, PreFireRoll: 0}
+		service.PreFire(pow, ctx, jammed)
+		if jammed.Factor != 5 {
+			t.Errorf("Ненадежный главный калибр должен потерять фактор, получено %d", jammed.Factor)
+		}
+
+		secondary := &models.FireRoll{Factor: 2, PreFireRoll: 0}
+		service.PreFire(pow, ctx, secondary)
+		if secondary.Factor != 2 {
+			t.Error("Правило не действует на вспомогательный калибр")
+		}
+	})
+
+	t.Run("EndRound", func(t *testing.T) {
+		if err := service.EndRound([]*models.NavalUnit{bismarck}, models.CombatContext{Round: 1}); err != nil {
+			t.Fatalf("Ошибка применения специальных правил: %v", err)
+		}
+		if bismarck.RadarLevel != 0 || !service.GetUnitSpecialRules(bismarck.ID).IsRuleTriggered(models.SpecialRuleRadarLossAfterFirstRound) {
+			t.Error("Радар должен быть потерян после первого раунда")
+		}
+	})
+
+	t.Run("RegisteredRule", func(t *testing.T) {
+		custom := models.SpecialRuleType("no_stern_guns_at_short_range")
+		models.RegisterSpecialRuleHooks(custom, models.SpecialRuleHooks{
+			SternGunsAvailable: func(unit *models.NavalUnit, ctx models.CombatContext) bool {
+				return ctx.Range != models.CombatRangeShort
+			},
+		})
+		t.Cleanup(func() {
+			models.UnregisterSpecialRuleHooks(custom)
+		})
+
+		unit := &models.NavalUnit{ID: "custom", BasePrimaryArmamentBow: 4, BasePrimaryArmamentStern: 2, PrimaryArmamentStern: 2}
+		service.ruleManager.RegisterUnitRules(unit.ID, []models.SpecialRule{{Type: custom, IsActive: true}})
+
+		if err := service.ProcessRangeChange([]*models.NavalUnit{unit}, string(models.CombatRangeShort)); err != nil {
+			t.Fatalf("Ошибка применения специальных правил: %v", err)
+		}
+		if unit.PrimaryArmamentStern != 0 || unit.PrimaryArmamentBow != 4 {
+			t.Error("Зарегистрированное правило должно применяться без изменения логики боя")
+		}
+	})
+}
@@ -2,6 +2,7 @@ package services
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 
 	"bismarck-game/backend/internal/game/models"
@@ -25,10 +26,28 @@ func NewTaskForceService(db *database.Database, logger *logger.Logger, unitServi
 	}
 }
 
-// CreateTaskForce создает новое оперативное соединение
-func (s *TaskForceService) CreateTaskForce(taskForce *models.TaskForce) error {
-	// Проверяем, что все юниты принадлежат одному игроку
-	units, err := s.unitService.GetNavalUnitsByGameID(taskForce.GameID)
+// Ошибки формирования и движения ТФ
+var (
+	ErrTaskForceMixedHex      = errors.New("all task force units must be in the same hex")
+	ErrTaskForceMixedSide     = errors.New("all task force units must belong to the same side")
+	ErrTaskForceEmpty         = errors.New("task force has no units")
+	ErrTaskForceNotInGame     = errors.New("task force does not belong to this game")
+	ErrUnitInTaskForce        = errors.New("unit is already in a task force")
+	ErrUnitNotInTaskForce     = errors.New("unit is not in this task force")
+	ErrUnitMovesWithTaskForce = errors.New("unit in a task force must move with its task force")
+	ErrTaskForceTooFast       = errors.New("speed exceeds the slowest unit of the task force")
+	ErrTaskForceCannotMove    = errors.New("task force unit cannot move")
+	ErrTaskForceNoFuel        = errors.New("task force unit has insufficient fuel")
+)
+
+// CreateTaskForce формирует оперативное соединение в Фазе движения (7.2).
+// Все корабли должны находиться в одном гексе и принадлежать одной стороне
+func (s *TaskForceService) CreateTaskForce(game *models.Game, taskForce *models.TaskForce) error {
+	if game.CurrentPhase != models.PhaseMovement {
+		return ErrNotMovementPhase
+	}
+
+	units, err := s.unitService.GetNavalUnitsByGameID(game.ID)
 	if err != nil {
 		return fmt.Errorf("failed to get units: %w", err)
 	}
@@ -39,22 +58,54 @@ func (s *TaskForceService) CreateTaskForce(taskForce *models.TaskForce) error {
 	}
 
 	// Проверяем юниты
+	members := make([]models.NavalUnit, 0, len(taskForce.Units))
 	for _, unitID := range taskForce.Units {
 		unit, exists := unitMap[unitID]
 		if !exists {
 			return fmt.Errorf("unit %s not found", unitID)
 		}
-		if unit.Owner != taskForce.Owner {
-			return fmt.Errorf("unit %s does not belong to player %s", unitID, taskForce.Owner)
-		}
 		if unit.TaskForceID != nil {
-			return fmt.Errorf("unit %s is already in a task force", unitID)
+			return fmt.Errorf("%w: %s", ErrUnitInTaskForce, unit.Name)
 		}
+		members = append(members, unit)
+	}
+	if err := validateTaskForceMembers(game, members); err != nil {
+		return err
 	}
 
+	taskForce.GameID = game.ID
+	taskForce.Owner = members[0].Owner
+	taskForce.Position = members[0].Position
 	// Вычисляем скорость соединения (по самому медленному кораблю)
-	taskForce.Speed = s.calculateTaskForceSpeed(taskForce.Units, unitMap)
+	taskForce.Speed = taskForceSpeed(members)
+	shareDetection(members)
+
+	tx, err := s.db.BeginTx()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := s.insertTaskForce(tx, taskForce); err != nil {
+		return err
+	}
+	// Обновляем юниты, добавляя их в Task Force
+	for i := range members {
+		members[i].TaskForceID = &taskForce.ID
+		if err := s.unitService.UpdateNavalUnitTx(tx, &members[i]); err != nil {
+			return fmt.Errorf("failed to update unit: %w", err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit task force: %w", err)
+	}
 
+	s.logger.Info("Created task force", "task_force_id", taskForce.ID, "name", taskForce.Name)
+	return nil
+}
+
+// insertTaskForce сохраняет новое соединение
+func (s *TaskForceService) insertTaskForce(q querier, taskForce *models.TaskForce) error {
 	query := `
 		INSERT INTO task_forces (
 			game_id, name, owner, position, speed, units, is_visible
@@ -64,7 +115,7 @@ func (s *TaskForceService) CreateTaskForce(taskForce *models.TaskForce) error {
 
 	unitsJSON, _ := json.Marshal(taskForce.Units)
 
-	err = s.db.QueryRow(query,
+	err := q.QueryRow(query,
 		taskForce.GameID, taskForce.Name, taskForce.Owner, taskForce.Position,
 		taskForce.Speed, unitsJSON, taskForce.IsVisible,
 	).Scan(&taskForce.ID, &taskForce.CreatedAt, &taskForce.UpdatedAt)
@@ -74,16 +125,6 @@ func (s *TaskForceService) CreateTaskForce(taskForce *models.TaskForce) error {
 		return fmt.Errorf("failed to create task force: %w", err)
 	}
 
-	// Обновляем юниты, добавляя их в Task Force
-	for _, unitID := range taskForce.Units {
-		unit, _ := s.unitService.GetNavalUnitByID(unitID)
-		if unit != nil {
-			unit.TaskForceID = &taskForce.ID
-			s.unitService.UpdateNavalUnit(unit)
-		}
-	}
-
-	s.logger.Info("Created task force", "task_force_id", taskForce.ID, "name", taskForce.Name)
 	return nil
 }
 
@@ -148,12 +189,11 @@ func (s *TaskForceService) GetTaskForceByID(taskForceID string) (*models.TaskFor
 	return &taskForce, nil
 }
 
-// AddUnitToTaskForce добавляет юнит в Task Force
-func (s *TaskForceService) AddUnitToTaskForce(taskForceID string, unitID string) error {
-	// Получаем Task Force
-	taskForce, err := s.GetTaskForceByID(taskForceID)
+// AddUnitToTaskForce добавляет юнит в Task Force в Фазе движения
+func (s *TaskForceService) AddUnitToTaskForce(game *models.Game, taskForceID string, unitID string) error {
+	taskForce, members, err := s.getMovementPhaseTaskForce(game, taskForceID)
 	if err != nil {
-		return fmt.Errorf("failed to get task force: %w", err)
+		return err
 	}
 
 	// Получаем юнит
@@ -161,167 +201,268 @@ func (s *TaskForceService) AddUnitToTaskForce(taskForceID string, unitID string)
 	if err != nil {
 		return fmt.Errorf("failed to get unit: %w", err)
 	}
-
-	// Проверяем, что юнит принадлежит тому же игроку
-	if unit.Owner != taskForce.Owner {
-		return fmt.Errorf("unit does not belong to task force owner")
+	if unit.GameID != game.ID {
+		return ErrUnitNotInGame
 	}
 
 	// Проверяем, что юнит не в другом Task Force
 	if unit.TaskForceID != nil {
-		return fmt.Errorf("unit is already in a task force")
+		return ErrUnitInTaskForce
 	}
 
-	// Проверяем, что юнит в той же позиции
-	if unit.Position != taskForce.Position {
-		return fmt.Errorf("unit is not in the same position as task force")
+	members = append(members, *unit)
+	if err := validateTaskForceMembers(game, members); err != nil {
+		return err
 	}
 
-	// Добавляем юнит в Task Force
 	taskForce.AddUnit(unitID)
+	taskForce.Speed = taskForceSpeed(members)
+	shareDetection(members)
 
-	// Обновляем Task Force в базе данных
-	err = s.updateTaskForce(taskForce)
+	tx, err := s.db.BeginTx()
 	if err != nil {
-		return fmt.Errorf("failed to update task force: %w", err)
+		return fmt.Errorf("failed to begin transaction: %w", err)
 	}
+	defer tx.Rollback()
 
-	// Обновляем юнит
-	unit.TaskForceID = &taskForceID
-	err = s.unitService.UpdateNavalUnit(unit)
-	if err != nil {
-		return fmt.Errorf("failed to update unit: %w", err)
+	if err := s.updateTaskForce(tx, taskForce); err != nil {
+		return fmt.Errorf("failed to update task force: %w", err)
+	}
+	for i := range members {
+		members[i].TaskForceID = &taskForce.ID
+		if err := s.unitService.UpdateNavalUnitTx(tx, &members[i]); err != nil {
+			return fmt.Errorf("failed to update unit: %w", err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit task force: %w", err)
 	}
 
 	s.logger.Info("Added unit to task force", "task_force_id", taskForceID, "unit_id", unitID)
 	return nil
 }
 
-// RemoveUnitFromTaskForce удаляет юнит из Task Force
-func (s *TaskForceService) RemoveUnitFromTaskForce(taskForceID string, unitID string) error {
-	// Получаем Task Force
-	taskForce, err := s.GetTaskForceByID(taskForceID)
+// RemoveUnitFromTaskForce выводит юнит из Task Force в Фазе движения
+func (s *TaskForceService) RemoveUnitFromTaskForce(game *models.Game, taskForceID string, unitID string) error {
+	_, err := s.SplitTaskForce(game, taskForceID, []string{unitID}, "")
+	return err
+}
+
+// SplitTaskForce разделяет Task Force в Фазе движения. Выделенные корабли образуют новое
+// соединение (если их несколько) или продолжают действовать самостоятельно. Обе части
+// сохраняют статус "Обнаружено" или "Преследуется" исходного соединения
+func (s *TaskForceService) SplitTaskForce(game *models.Game, taskForceID string, unitIDs []string, name string) (*models.TaskForce, error) {
+	taskForce, members, err := s.getMovementPhaseTaskForce(game, taskForceID)
 	if err != nil {
-		return fmt.Errorf("failed to get task force: %w", err)
+		return nil, err
+	}
+	for _, unitID := range unitIDs {
+		if !taskForce.HasUnit(unitID) {
+			return nil, ErrUnitNotInTaskForce
+		}
 	}
 
-	// Удаляем юнит из Task Force
-	taskForce.RemoveUnit(unitID)
+	detached, remaining := splitTaskForceUnits(members, unitIDs)
+	for _, unitID := range unitIDs {
+		taskForce.RemoveUnit(unitID)
+	}
+
+	tx, err := s.db.BeginTx()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var split *models.TaskForce
+	if len(detached) > 1 {
+		if name == "" {
+			name = taskForce.Name + " (2)"
+		}
+		split = &models.TaskForce{
+			GameID:    game.ID,
+			Name:      name,
+			Owner:     taskForce.Owner,
+			Position:  taskForce.Position,
+			Speed:     taskForceSpeed(detached),
+			Units:     unitIDs,
+			IsVisible: taskForce.IsVisible,
+		}
+		if err := s.insertTaskForce(tx, split); err != nil {
+			return nil, err
+		}
+		for i := range detached {
+			detached[i].TaskForceID = &split.ID
+		}
+	}
 
 	// Если Task Force пустой, удаляем его
 	if taskForce.IsEmpty() {
-		err = s.DeleteTaskForce(taskForceID)
-		if err != nil {
-			return fmt.Errorf("failed to delete empty task force: %w", err)
+		if err := s.deleteTaskForce(tx, taskForce.ID); err != nil {
+			return nil, err
 		}
 	} else {
-		// Обновляем Task Force в базе данных
-		err = s.updateTaskForce(taskForce)
-		if err != nil {
-			return fmt.Errorf("failed to update task force: %w", err)
+		taskForce.Speed = taskForceSpeed(remaining)
+		if err := s.updateTaskForce(tx, taskForce); err != nil {
+			return nil, fmt.Errorf("failed to update task force: %w", err)
 		}
 	}
 
-	// Обновляем юнит
-	unit, err := s.unitService.GetNavalUnitByID(unitID)
-	if err != nil {
-		return fmt.Errorf("failed to get unit: %w", err)
+	for _, units := range [][]models.NavalUnit{detached, remaining} {
+		for i := range units {
+			if err := s.unitService.UpdateNavalUnitTx(tx, &units[i]); err != nil {
+				return nil, fmt.Errorf("failed to update unit: %w", err)
+			}
+		}
 	}
-
-	unit.TaskForceID = nil
-	err = s.unitService.UpdateNavalUnit(unit)
-	if err != nil {
-		return fmt.Errorf("failed to update unit: %w", err)
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit task force split: %w", err)
 	}
 
-	s.logger.Info("Removed unit from task force", "task_force_id", taskForceID, "unit_id", unitID)
-	return nil
+	s.logger.Info("Split task force", "task_force_id", taskForceID, "units", len(unitIDs))
+	return split, nil
 }
 
-// MoveTaskForce перемещает Task Force
-func (s *TaskForceService) MoveTaskForce(taskForceID string, to string, speed int) error {
-	// Получаем Task Force
-	taskForce, err := s.GetTaskForceByID(taskForceID)
+// MoveTaskForce перемещает Task Force как единый юнит (7.2): соединение двигается с классом
+// скорости самого медленного корабля, топливо расходует каждый корабль
+func (s *TaskForceService) MoveTaskForce(game *models.Game, taskForceID string, to string, speed int, path []string) error {
+	taskForce, members, err := s.getMovementPhaseTaskForce(game, taskForceID)
 	if err != nil {
-		return fmt.Errorf("failed to get task force: %w", err)
+		return err
+	}
+	if err := validateTaskForceMembers(game, members); err != nil {
+		return err
+	}
+	if speed > taskForceSpeed(members) {
+		return ErrTaskForceTooFast
+	}
+
+	for _, unit := range members {
+		moved, err := s.unitService.HasMovedInTurn(unit.ID, game.CurrentTurn)
+		if err != nil {
+			return err
+		}
+		if moved {
+			return fmt.Errorf("%w: %s", ErrUnitAlreadyMoved, unit.Name)
+		}
+	}
+
+	from := taskForce.Position
+	if len(path) == 0 {
+		path = []string{from, to}
 	}
 
-	// Получаем все юниты в Task Force
-	units, err := s.unitService.GetNavalUnitsByGameID(taskForce.GameID)
+	// Вычисляем расход топлива (упрощенно)
+	fuelCost := speed // 1 топливо за 1 скорость
+	if err := moveTaskForceUnits(members, to, fuelCost, game.CurrentTurn); err != nil {
+		return err
+	}
+
+	tx, err := s.db.BeginTx()
 	if err != nil {
-		return fmt.Errorf("failed to get units: %w", err)
+		return fmt.Errorf("failed to begin transaction: %w", err)
 	}
+	defer tx.Rollback()
 
-	// Перемещаем все юниты в Task Force
-	for _, unitID := range taskForce.Units {
-		for _, unit := range units {
-			if unit.ID == unitID {
-				// Проверяем, может ли юнит двигаться
-				if !unit.CanMove() {
-					return fmt.Errorf("unit %s cannot move", unitID)
-				}
-
-				// Вычисляем расход топлива (упрощенно)
-				fuelCost := speed // 1 топливо за 1 скорость
-				if unit.Fuel < fuelCost {
-					return fmt.Errorf("unit %s has insufficient fuel", unitID)
-				}
-
-				// Перемещаем юнит
-				err = s.unitService.MoveUnit(unitID, to, speed, fuelCost, []string{unit.Position, to}, 1, models.PhaseMovement)
-				if err != nil {
-					return fmt.Errorf("failed to move unit %s: %w", unitID, err)
-				}
-				break
-			}
+	for i := range members {
+		if err := s.unitService.UpdateNavalUnitTx(tx, &members[i]); err != nil {
+			return fmt.Errorf("failed to move unit %s: %w", members[i].ID, err)
+		}
+		movement := models.UnitMovement{
+			GameID:   game.ID,
+			UnitID:   members[i].ID,
+			From:     from,
+			To:       to,
+			Path:     path,
+			Speed:    speed,
+			FuelCost: fuelCost,
+			Turn:     game.CurrentTurn,
+			Phase:    game.CurrentPhase,
+		}
+		if err := s.unitService.RecordMovementTx(tx, &movement); err != nil {
+			return fmt.Errorf("failed to record movement: %w", err)
 		}
 	}
 
 	// Обновляем позицию Task Force
 	taskForce.Position = to
-	taskForce.Speed = speed
-
-	err = s.updateTaskForce(taskForce)
-	if err != nil {
+	taskForce.Speed = taskForceSpeed(members)
+	if err := s.updateTaskForce(tx, taskForce); err != nil {
 		return fmt.Errorf("failed to update task force: %w", err)
 	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit task force movement: %w", err)
+	}
 
-	s.logger.Info("Moved task force", "task_force_id", taskForceID, "to", to, "speed", speed)
+	s.logger.Info("Moved task force", "task_force_id", taskForceID, "from", from, "to", to, "speed", speed)
 	return nil
 }
 
-// DeleteTaskForce удаляет Task Force
-func (s *TaskForceService) DeleteTaskForce(taskForceID string) error {
-	// Получаем Task Force
-	taskForce, err := s.GetTaskForceByID(taskForceID)
+// DeleteTaskForce расформировывает Task Force в Фазе движения
+func (s *TaskForceService) DeleteTaskForce(game *models.Game, taskForceID string) error {
+	taskForce, members, err := s.getMovementPhaseTaskForce(game, taskForceID)
+	if err != nil {
+		return err
+	}
+	shareDetection(members)
+
+	tx, err := s.db.BeginTx()
 	if err != nil {
-		return fmt.Errorf("failed to get task force: %w", err)
+		return fmt.Errorf("failed to begin transaction: %w", err)
 	}
+	defer tx.Rollback()
 
 	// Удаляем связь с юнитами
-	for _, unitID := range taskForce.Units {
-		unit, err := s.unitService.GetNavalUnitByID(unitID)
-		if err != nil {
-			continue
+	for i := range members {
+		members[i].TaskForceID = nil
+		if err := s.unitService.UpdateNavalUnitTx(tx, &members[i]); err != nil {
+			return fmt.Errorf("failed to update unit: %w", err)
 		}
-		unit.TaskForceID = nil
-		s.unitService.UpdateNavalUnit(unit)
+	}
+	if err := s.deleteTaskForce(tx, taskForce.ID); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit task force deletion: %w", err)
 	}
 
-	// Удаляем Task Force из базы данных
-	query := `DELETE FROM task_forces WHERE id = $1`
-	_, err = s.db.Exec(query, taskForceID)
+	s.logger.Info("Deleted task force", "task_force_id", taskForceID)
+	return nil
+}
+
+// getMovementPhaseTaskForce возвращает соединение игры и его корабли, проверяя, что идет Фаза движения
+func (s *TaskForceService) getMovementPhaseTaskForce(game *models.Game, taskForceID string) (*models.TaskForce, []models.NavalUnit, error) {
+	if game.CurrentPhase != models.PhaseMovement {
+		return nil, nil, ErrNotMovementPhase
+	}
+
+	taskForce, err := s.GetTaskForceByID(taskForceID)
 	if err != nil {
+		return nil, nil, err
+	}
+	if taskForce.GameID != game.ID {
+		return nil, nil, ErrTaskForceNotInGame
+	}
+
+	members, err := s.GetTaskForceUnits(taskForceID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return taskForce, members, nil
+}
+
+// deleteTaskForce удаляет Task Force из базы данных
+func (s *TaskForceService) deleteTaskForce(db execer, taskForceID string) error {
+	query := `DELETE FROM task_forces WHERE id = $1`
+	if _, err := db.Exec(query, taskForceID); err != nil {
 		s.logger.Error("Failed to delete task force", "task_force_id", taskForceID, "error", err)
 		return fmt.Errorf("failed to delete task force: %w", err)
 	}
-
-	s.logger.Info("Deleted task force", "task_force_id", taskForceID)
 	return nil
 }
 
 // updateTaskForce обновляет Task Force в базе данных
-func (s *TaskForceService) updateTaskForce(taskForce *models.TaskForce) error {
+func (s *TaskForceService) updateTaskForce(db execer, taskForce *models.TaskForce) error {
 	query := `
 		UPDATE task_forces SET
 			position = $2, speed = $3, units = $4,
@@ -330,7 +471,7 @@ func (s *TaskForceService) updateTaskForce(taskForce *models.TaskForce) error {
 
 	unitsJSON, _ := json.Marshal(taskForce.Units)
 
-	_, err := s.db.Exec(query,
+	_, err := db.Exec(query,
 		taskForce.ID, taskForce.Position, taskForce.Speed,
 		unitsJSON, taskForce.IsVisible,
 	)
@@ -342,26 +483,101 @@ func (s *TaskForceService) updateTaskForce(taskForce *models.TaskForce) error {
 	return nil
 }
 
-// calculateTaskForceSpeed вычисляет скорость Task Force (по самому медленному кораблю)
-func (s *TaskForceService) calculateTaskForceSpeed(unitIDs []string, unitMap map[string]models.NavalUnit) int {
-	if len(unitIDs) == 0 {
+// taskForceSpeed возвращает скорость соединения по самому медленному кораблю
+func taskForceSpeed(units []models.NavalUnit) int {
+	if len(units) == 0 {
 		return 0
 	}
 
 	minSpeed := 6 // максимальная скорость
-	for _, unitID := range unitIDs {
-		unit, exists := unitMap[unitID]
-		if exists {
-			effectiveSpeed := unit.GetEffectiveSpeed()
-			if effectiveSpeed < minSpeed {
-				minSpeed = effectiveSpeed
-			}
+	for _, unit := range units {
+		if effectiveSpeed := unit.GetEffectiveSpeed(); effectiveSpeed < minSpeed {
+			minSpeed = effectiveSpeed
 		}
 	}
 
 	return minSpeed
 }
 
+// validateTaskForceMembers проверяет правило 7.2: корабли ТФ находятся на карте
+// в одном гексе и принадлежат одной стороне
+func validateTaskForceMembers(game *models.Game, units []models.NavalUnit) error {
+	if len(units) == 0 {
+		return ErrTaskForceEmpty
+	}
+
+	position, side := units[0].Position, unitSide(game, &units[0])
+	for i := range units {
+		unit := &units[i]
+		if !unit.IsOnMap() {
+			return fmt.Errorf("unit %s is not on the map", unit.Name)
+		}
+		if unit.Position != position {
+			return fmt.Errorf("%w: %s", ErrTaskForceMixedHex, unit.Name)
+		}
+		if unitSide(game, unit) != side {
+			return fmt.Errorf("%w: %s", ErrTaskForceMixedSide, unit.Name)
+		}
+	}
+
+	return nil
+}
+
+// shareDetection распространяет на все корабли уровень обнаружения соединения
+func shareDetection(units []models.NavalUnit) {
+	level := models.TaskForceDetectionLevel(units)
+	for i := range units {
+		units[i].InheritDetection(level)
+	}
+}
+
+// splitTaskForceUnits делит корабли соединения на выделяемые и остающиеся.
+// Обе части сохраняют уровень обнаружения исходного соединения
+func splitTaskForceUnits(units []models.NavalUnit, unitIDs []string) ([]models.NavalUnit, []models.NavalUnit) {
+	shareDetection(units)
+
+	leaving := make(map[string]bool, len(unitIDs))
+	for _, unitID := range unitIDs {
+		leaving[unitID] = true
+	}
+
+	detached, remaining := []models.NavalUnit{}, []models.NavalUnit{}
+	for _, unit := range units {
+		if leaving[unit.ID] {
+			unit.TaskForceID = nil
+			detached = append(detached, unit)
+		} else {
+			remaining = append(remaining, unit)
+		}
+	}
+
+	return detached, remaining
+}
+
+// moveTaskForceUnits перемещает корабли соединения: каждый корабль расходует топливо
+// и получает маркер "Нет движения" по классу скорости самого медленного корабля
+func moveTaskForceUnits(units []models.NavalUnit, to string, fuelCost int, turn int) error {
+	for i := range units {
+		if !units[i].CanMove() {
+			return fmt.Errorf("%w: %s", ErrTaskForceCannotMove, units[i].Name)
+		}
+		if units[i].Fuel < fuelCost {
+			return fmt.Errorf("%w: %s", ErrTaskForceNoFuel, units[i].Name)
+		}
+	}
+
+	rating := models.SlowestSpeedRating(units)
+	for i := range units {
+		unit := &units[i]
+		unit.Position = to
+		unit.Fuel -= fuelCost
+		unit.StartEmergencyFuel(turn)
+		unit.MarkNoMovementForSpeed(rating)
+	}
+
+	return nil
+}
+
 // GetTaskForceUnits возвращает все юниты в Task Force
 func (s *TaskForceService) GetTaskForceUnits(taskForceID string) ([]models.NavalUnit, error) {
 	taskForce, err := s.GetTaskForceByID(taskForceID)
@@ -388,19 +604,7 @@ func (s *TaskForceService) GetTaskForceEffectiveSpeed(taskForceID string) (int,
 		return 0, fmt.Errorf("failed to get task force units: %w", err)
 	}
 
-	if len(units) == 0 {
-		return 0, nil
-	}
-
-	minSpeed := 6 // максимальная скорость
-	for _, unit := range units {
-		effectiveSpeed := unit.GetEffectiveSpeed()
-		if effectiveSpeed < minSpeed {
-			minSpeed = effectiveSpeed
-		}
-	}
-
-	return minSpeed, nil
+	return taskForceSpeed(units), nil
 }
 
 // GetTaskForceTotalSearchFactors возвращает общие факторы поиска Task Force
@@ -0,0 +1,106 @@
+package services
+
+import (
+	"bismarck-game/backend/internal/game/models"
+	"errors"
+	"testing"
+)
+
+func newTaskForceTestUnits() []models.NavalUnit {
+	tf := "force-h"
+	return []models.NavalUnit{
+		{ID: "renown", Name: "RENOWN", Owner: "allied", Position: "AB20", SpeedRating: models.SpeedTypeFast,
+			Evasion: 5, Fuel: 10, HullBoxes: 8, CurrentHull: 8, Status: models.UnitStatusActive, TaskForceID: &tf,
+			DetectionLevel: models.DetectionLevelShadowed},
+		{ID: "ark-royal", Name: "ARK ROYAL", Owner: "allied", Position: "AB20", SpeedRating: models.SpeedTypeMedium,
+			Evasion: 4, Fuel: 10, HullBoxes: 6, CurrentHull: 6, Status: models.UnitStatusActive, TaskForceID: &tf,
+			DetectionLevel: models.DetectionLevelNone},
+		{ID: "convoy", Name: "CONVOY", Owner: "allied", Position: "AB20", SpeedRating: models.SpeedTypeSlow,
+			Evasion: 2, Fuel: 10, HullBoxes: 2, CurrentHull: 2, Status: models.UnitStatusActive, TaskForceID: &tf,
+			DetectionLevel: models.DetectionLevelSighted},
+	}
+}
+
+func TestValidateTaskForceMembers(t *testing.T) {
+	game := newVictoryTestGame()
+
+	if err := validateTaskForceMembers(game, newTaskForceTestUnits()); err != nil {
+		t.Errorf("Корабли в одном гексе одной стороны образуют ТФ, получена ошибка %v", err)
+	}
+
+	t.Run("DifferentHex", func(t *testing.T) {
+		units := newTaskForceTestUnits()
+		units[1].Position = "AB21"
+		if err := validateTaskForceMembers(game, units); !errors.Is(err, ErrTaskForceMixedHex) {
+			t.Errorf("Ожидалась ошибка разных гексов, получено %v", err)
+		}
+	})
+
+	t.Run("DifferentSide", func(t *testing.T) {
+		units := newTaskForceTestUnits()
+		units[2].Owner = "german"
+		if err := validateTaskForceMembers(game, units); !errors.Is(err, ErrTaskForceMixedSide) {
+			t.Errorf("Ожидалась ошибка разных сторон, получено %v", err)
+		}
+	})
+
+	t.Run("Empty", func(t *testing.T) {
+		if err := validateTaskForceMembers(game, nil); !errors.Is(err, ErrTaskForceEmpty) {
+			t.Errorf("Ожидалась ошибка пустой ТФ, получено %v", err)
+		}
+	})
+}
+
+func TestMoveTaskForceUnits(t *testing.T) {
+	units := newTaskForceTestUnits()
+
+	if rating := models.SlowestSpeedRating(units); rating != models.SpeedTypeSlow {
+		t.Errorf("ТФ двигается с классом скорости самого медленного корабля, получено %s", rating)
+	}
+	if speed := taskForceSpeed(units); speed != 2 {
+		t.Errorf("Ожидалась скорость ТФ 2, получено %d", speed)
+	}
+
+	if err := moveTaskForceUnits(units, "AC20", 2, 5); err != nil {
+		t.Fatalf("Неожиданная ошибка: %v", err)
+	}
+	for _, unit := range units {
+		if unit.Position != "AC20" || unit.Fuel != 8 {
+			t.Errorf("%s должен переместиться и потратить топливо, получено %s/%d", unit.Name, unit.Position, unit.Fuel)
+		}
+		if unit.NoMovement != models.NoMovementSlow {
+			t.Errorf("%s должен получить маркер \"Нет движения\" медленного корабля, получено %d", unit.Name, unit.NoMovement)
+		}
+	}
+
+	t.Run("NotEnoughFuel", func(t *testing.T) {
+		units := newTaskForceTestUnits()
+		units[1].Fuel = 1
+		if err := moveTaskForceUnits(units, "AC20", 2, 5); !errors.Is(err, ErrTaskForceNoFuel) {
+			t.Errorf("Ожидалась ошибка нехватки топлива, получено %v", err)
+		}
+		if units[0].Position != "AB20" {
+			t.Error("Корабли не должны перемещаться при ошибке")
+		}
+	})
+}
+
+func TestSplitTaskForceUnits(t *testing.T) {
+	units := newTaskForceTestUnits()
+
+	detached, remaining := splitTaskForceUnits(units, []string{"ark-royal"})
+	if len(detached) != 1 || len(remaining) != 2 {
+		t.Fatalf("Ожидалось 1/2 корабля, получено %d/%d", len(detached), len(remaining))
+	}
+	if detached[0].TaskForceID != nil {
+		t.Error("Выделенный корабль должен покинуть ТФ")
+	}
+	for _, unit := range append(detached, remaining...) {
+		if unit.DetectionLevel != models.DetectionLevelShadowed {
+			t.Errorf("%s должен сохранить статус \"Преследуется\", получено %s", unit.Name, unit.DetectionLevel)
+		}
+	}
+	if detached[0].LastKnownPos == nil || *detached[0].LastKnownPos != "AB20" {
+		t.Error("Для выделенного корабля должна быть известна позиция")
+	}
+}
@@ -0,0 +1,212 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"bismarck-game/backend/internal/game/models"
+)
+
+// GetUnitHistory возвращает страницу журнала действий корабля, видимого пользователю.
+// О своем корабле видны движения и поиски, о корабле противника - только донесения о контактах с ним
+func (s *UnitService) GetUnitHistory(access *GameAccess, unitID string, filter models.HistoryFilter) ([]models.UnitHistoryEntry, int, error) {
+	unit, full, err := s.getHistoryUnit(access, unitID)
+	if err != nil {
+		return nil, 0, err
+	}
+	if !full {
+		contacts, err := s.getObservedContacts(access.Game, unit, filter)
+		if err != nil {
+			return nil, 0, err
+		}
+		entries, total := models.BuildUnitHistory(nil, nil, contacts, filter)
+		return entries, total, nil
+	}
+
+	movements, err := s.getUnitMovements(unitID, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+	searches, err := s.getUnitSearches(unitID, models.HistoryFilter{Turn: filter.Turn, Phase: filter.Phase})
+	if err != nil {
+		return nil, 0, err
+	}
+	entries, total := models.BuildUnitHistory(movements, searches, nil, filter)
+	return entries, total, nil
+}
+
+// GetUnitMovements возвращает страницу истории движений корабля. Движения корабля
+// противника неизвестны, вместо них возвращаются донесения о его позициях
+func (s *UnitService) GetUnitMovements(access *GameAccess, unitID string, filter models.HistoryFilter) ([]models.UnitHistoryEntry, int, error) {
+	unit, full, err := s.getHistoryUnit(access, unitID)
+	if err != nil {
+		return nil, 0, err
+	}
+	filter.Type = ""
+	if !full {
+		contacts, err := s.getObservedContacts(access.Game, unit, filter)
+		if err != nil {
+			return nil, 0, err
+		}
+		entries, total := models.BuildUnitHistory(nil, nil, contacts, filter)
+		return entries, total, nil
+	}
+
+	movements, err := s.getUnitMovements(unitID, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+	entries, total := models.BuildUnitHistory(movements, nil, nil, filter)
+	return entries, total, nil
+}
+
+// GetUnitSearches возвращает страницу истории поисков корабля, filter.Type - тип поиска.
+// Поиски корабля противника неизвестны
+func (s *UnitService) GetUnitSearches(access *GameAccess, unitID string, filter models.HistoryFilter) ([]models.UnitHistoryEntry, int, error) {
+	_, full, err := s.getHistoryUnit(access, unitID)
+	if err != nil {
+		return nil, 0, err
+	}
+	if !full {
+		return []models.UnitHistoryEntry{}, 0, nil
+	}
+
+	searches, err := s.getUnitSearches(unitID, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+	filter.Type = ""
+	entries, total := models.BuildUnitHistory(nil, searches, nil, filter)
+	return entries, total, nil
+}
+
+// getHistoryUnit загружает корабль игры и определяет, видна ли пользователю его полная история:
+// своя сторона видит ее всегда, остальные - после окончания игры
+func (s *UnitService) getHistoryUnit(access *GameAccess, unitID string) (*models.NavalUnit, bool, error) {
+	unit, err := s.GetNavalUnitByID(unitID)
+	if err != nil {
+		return nil, false, err
+	}
+	if unit.GameID != access.Game.ID {
+		return nil, false, ErrUnitNotInGame
+	}
+	own := !access.IsSpectator() && unitSide(access.Game, unit) == access.Side
+	return unit, own || access.Game.IsCompleted(), nil
+}
+
+// historyConditions добавляет к запросу условия фильтра по ходу и фазе
+func historyConditions(query string, args []interface{}, filter models.HistoryFilter) (string, []interface{}) {
+	if filter.Turn != nil {
+		args = append(args, *filter.Turn)
+		query += " AND turn = $" + strconv.Itoa(len(args))
+	}
+	if filter.Phase != "" {
+		args = append(args, filter.Phase)
+		query += " AND phase = $" + strconv.Itoa(len(args))
+	}
+	return query, args
+}
+
+// getUnitMovements возвращает движения корабля, подходящие под фильтр
+func (s *UnitService) getUnitMovements(unitID string, filter models.HistoryFilter) ([]models.UnitMovement, error) {
+	query, args := historyConditions(`
+		SELECT id, game_id, unit_id, from_pos, to_pos, path, speed, fuel_cost, is_shadowed, turn, phase, created_at
+		FROM unit_movements
+		WHERE unit_id = $1`, []interface{}{unitID}, filter)
+
+	rows, err := s.db.Query(query+" ORDER BY turn, created_at", args...)
+	if err != nil {
+		s.logger.Error("Failed to get unit movements", "unit_id", unitID, "error", err)
+		return nil, fmt.Errorf("failed to get unit movements: %w", err)
+	}
+	defer rows.Close()
+
+	movements := []models.UnitMovement{}
+	for rows.Next() {
+		var movement models.UnitMovement
+		var pathJSON []byte
+		err := rows.Scan(
+			&movement.ID, &movement.GameID, &movement.UnitID, &movement.From, &movement.To, &pathJSON,
+			&movement.Speed, &movement.FuelCost, &movement.IsShadowed, &movement.Turn, &movement.Phase, &movement.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan unit movement: %w", err)
+		}
+		if err := json.Unmarshal(pathJSON, &movement.Path); err != nil {
+			return nil, fmt.Errorf("failed to parse movement path: %w", err)
+		}
+		movements = append(movements, movement)
+	}
+
+	return movements, rows.Err()
+}
+
+// getUnitSearches возвращает поиски корабля, подходящие под фильтр; filter.Type - тип поиска
+func (s *UnitService) getUnitSearches(unitID string, filter models.HistoryFilter) ([]models.UnitSearch, error) {
+	query, args := historyConditions(`
+		SELECT id, game_id, unit_id, target_hex, search_type, search_factors, patrol_factors,
+		       result, units_found, turn, phase, created_at
+		FROM unit_searches
+		WHERE unit_id = $1`, []interface{}{unitID}, filter)
+	if filter.Type != "" {
+		args = append(args, filter.Type)
+		query += " AND search_type = $" + strconv.Itoa(len(args))
+	}
+
+	rows, err := s.db.Query(query+" ORDER BY turn, created_at", args...)
+	if err != nil {
+		s.logger.Error("Failed to get unit searches", "unit_id", unitID, "error", err)
+		return nil, fmt.Errorf("failed to get unit searches: %w", err)
+	}
+	defer rows.Close()
+
+	searches := []models.UnitSearch{}
+	for rows.Next() {
+		var search models.UnitSearch
+		var unitsFoundJSON []byte
+		err := rows.Scan(
+			&search.ID, &search.GameID, &search.UnitID, &search.TargetHex, &search.SearchType,
+			&search.SearchFactors, &search.PatrolFactors, &search.Result, &unitsFoundJSON,
+			&search.Turn, &search.Phase, &search.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan unit search: %w", err)
+		}
+		if err := json.Unmarshal(unitsFoundJSON, &search.UnitsFound); err != nil {
+			return nil, fmt.Errorf("failed to parse units found: %w", err)
+		}
+		searches = append(searches, search)
+	}
+
+	return searches, rows.Err()
+}
+
+// getObservedContacts возвращает донесения противника корабля о нем.
+// Игрок видит только донесения своей стороны, зритель - донесения стороны, наблюдавшей корабль
+func (s *UnitService) getObservedContacts(game *models.Game, unit *models.NavalUnit, filter models.HistoryFilter) ([]models.ContactReport, error) {
+	observer := unitSide(game, unit).Opponent()
+	query, args := historyConditions(`
+		SELECT `+contactReportColumns+`
+		FROM contact_reports
+		WHERE game_id = $1 AND side = $2 AND unit_id = $3`,
+		[]interface{}{game.ID, observer, unit.ID}, filter)
+
+	rows, err := s.db.Query(query+" ORDER BY turn, created_at", args...)
+	if err != nil {
+		s.logger.Error("Failed to get contact reports", "unit_id", unit.ID, "error", err)
+		return nil, fmt.Errorf("failed to get contact reports: %w", err)
+	}
+	defer rows.Close()
+
+	reports := []models.ContactReport{}
+	for rows.Next() {
+		report, err := scanContactReport(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan contact report: %w", err)
+		}
+		reports = append(reports, *report)
+	}
+
+	return reports, rows.Err()
+}
@@ -0,0 +1,54 @@
+package services
+
+import (
+	"bismarck-game/backend/internal/game/models"
+	"testing"
+	"time"
+)
+
+func TestBuildUnitHistory(t *testing.T) {
+	start := time.Date(1941, 5, 22, 0, 0, 0, 0, time.UTC)
+	movements := []models.UnitMovement{
+		{ID: "move-1", From: "AB20", To: "AB18", Turn: 1, Phase: models.PhaseMovement, CreatedAt: start},
+		{ID: "move-2", From: "AB18", To: "AC16", Turn: 2, Phase: models.PhaseMovement, CreatedAt: start.Add(2 * time.Hour)},
+	}
+	searches := []models.UnitSearch{
+		{ID: "search-1", TargetHex: "AC15", SearchType: "naval", Turn: 1, Phase: models.PhaseSearch, CreatedAt: start.Add(time.Hour)},
+	}
+
+	t.Run("Chronological", func(t *testing.T) {
+		entries, total := models.BuildUnitHistory(movements, searches, nil, models.HistoryFilter{})
+		if total != 3 || len(entries) != 3 {
+			t.Fatalf("Ожидалось 3 записи, получено %d из %d", len(entries), total)
+		}
+		if entries[0].Movement.ID != "move-1" || entries[1].Search.ID != "search-1" || entries[2].Movement.ID != "move-2" {
+			t.Errorf("Записи должны идти по ходам и времени, получено %v", entries)
+		}
+	})
+
+	t.Run("TypeFilter", func(t *testing.T) {
+		entries, total := models.BuildUnitHistory(movements, searches, nil, models.HistoryFilter{Type: models.HistoryEntrySearch})
+		if total != 1 || entries[0].Type != models.HistoryEntrySearch {
+			t.Errorf("Ожидался только поиск, получено %v", entries)
+		}
+	})
+
+	t.Run("Pages", func(t *testing.T) {
+		entries, total := models.BuildUnitHistory(movements, searches, nil, models.HistoryFilter{Page: 2, PerPage: 2})
+		if total != 3 || len(entries) != 1 || entries[0].Movement.ID != "move-2" {
+			t.Errorf("Вторая страница должна содержать последнюю запись, получено %v из %d", entries, total)
+		}
+		entries, total = models.BuildUnitHistory(movements, searches, nil, models.HistoryFilter{Page: 5, PerPage: 2})
+		if total != 3 || entries == nil || len(entries) != 0 {
+			t.Errorf("Страница за концом журнала должна быть пустой, получено %v", entries)
+		}
+	})
+
+	t.Run("DefaultPage", func(t *testing.T) {
+		filter := models.HistoryFilter{PerPage: models.MaxHistoryPerPage + 1}
+		filter.Normalize()
+		if filter.Page != 1 || filter.PerPage != models.DefaultHistoryPerPage {
+			t.Errorf("Ожидалась первая страница размера по умолчанию, получено %d/%d", filter.Page, filter.PerPage)
+		}
+	})
+}
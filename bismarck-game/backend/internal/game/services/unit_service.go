@@ -3,6 +3,7 @@ package services
 import (
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"time"
 
@@ -11,22 +12,50 @@ import (
 	"bismarck-game/backend/pkg/logger"
 )
 
+// ErrUnitNotFound возвращается, если морского юнита нет
+var ErrUnitNotFound = errors.New("naval unit not found")
+
 // UnitService предоставляет методы для работы с юнитами
 type UnitService struct {
-	db     *database.Database
-	logger *logger.Logger
+	db            *database.Database
+	logger        *logger.Logger
+	markerService *MarkerService
 }
 
 // NewUnitService создает новый сервис юнитов
-func NewUnitService(db *database.Database, logger *logger.Logger) *UnitService {
+func NewUnitService(db *database.Database, logger *logger.Logger, markerService *MarkerService) *UnitService {
 	return &UnitService{
-		db:     db,
-		logger: logger,
+		db:            db,
+		logger:        logger,
+		markerService: markerService,
 	}
 }
 
 // CreateNavalUnit создает новый морской юнит
 func (s *UnitService) CreateNavalUnit(unit *models.NavalUnit) error {
+	tx, err := s.db.BeginTx()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := s.createNavalUnit(tx, unit); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit naval unit: %w", err)
+	}
+	return nil
+}
+
+// CreateNavalUnitTx создает новый морской юнит в рамках транзакции
+func (s *UnitService) CreateNavalUnitTx(tx *sql.Tx, unit *models.NavalUnit) error {
+	return s.createNavalUnit(tx, unit)
+}
+
+// createNavalUnit сохраняет новый морской юнит
+func (s *UnitService) createNavalUnit(q querier, unit *models.NavalUnit) error {
 	query := `
 		INSERT INTO naval_units (
 			game_id, name, type, class, owner, nationality, position,
@@ -34,22 +63,22 @@ func (s *UnitService) CreateNavalUnit(unit *models.NavalUnit) error {
 			hull_boxes, current_hull, primary_armament_bow, primary_armament_stern,
 			secondary_armament, base_primary_armament_bow, base_primary_armament_stern,
 			base_secondary_armament, torpedoes, max_torpedoes, radar_level,
-			status, detection_level, damage
+			status, detection_level, damage, arrival_turn
 		) VALUES (
 			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12,
 			$13, $14, $15, $16, $17, $18, $19, $20, $21, $22,
-			$23, $24, $25
+			$23, $24, $25, $26, $27
 		) RETURNING id, created_at, updated_at`
 
 	damageJSON, _ := json.Marshal(unit.Damage)
 
-	err := s.db.QueryRow(query,
+	err := q.QueryRow(query,
 		unit.GameID, unit.Name, unit.Type, unit.Class, unit.Owner, unit.Nationality, unit.Position,
 		unit.Evasion, unit.BaseEvasion, unit.SpeedRating, unit.Fuel, unit.MaxFuel,
 		unit.HullBoxes, unit.CurrentHull, unit.PrimaryArmamentBow, unit.PrimaryArmamentStern,
 		unit.SecondaryArmament, unit.BasePrimaryArmamentBow, unit.BasePrimaryArmamentStern,
 		unit.BaseSecondaryArmament, unit.Torpedoes, unit.MaxTorpedoes, unit.RadarLevel,
-		unit.Status, unit.DetectionLevel, damageJSON,
+		unit.Status, unit.DetectionLevel, damageJSON, unit.ArrivalTurn,
 	).Scan(&unit.ID, &unit.CreatedAt, &unit.UpdatedAt)
 
 	if err != nil {
@@ -57,12 +86,28 @@ func (s *UnitService) CreateNavalUnit(unit *models.NavalUnit) error {
 		return fmt.Errorf("failed to create naval unit: %w", err)
 	}
 
+	// Специальные правила юнита хранятся вместе с ним
+	if err := insertUnitSpecialRules(q, unit); err != nil {
+		s.logger.Error("Failed to create unit special rules", "unit_id", unit.ID, "error", err)
+		return err
+	}
+
 	s.logger.Info("Created naval unit", "unit_id", unit.ID, "name", unit.Name)
 	return nil
 }
 
 // CreateAirUnit создает новый воздушный юнит
 func (s *UnitService) CreateAirUnit(unit *models.AirUnit) error {
+	return s.createAirUnit(s.db, unit)
+}
+
+// CreateAirUnitTx создает новый воздушный юнит в рамках транзакции
+func (s *UnitService) CreateAirUnitTx(tx *sql.Tx, unit *models.AirUnit) error {
+	return s.createAirUnit(tx, unit)
+}
+
+// createAirUnit сохраняет новый воздушный юнит
+func (s *UnitService) createAirUnit(q querier, unit *models.AirUnit) error {
 	query := `
 		INSERT INTO air_units (
 			game_id, type, owner, position, base_position,
@@ -71,7 +116,7 @@ func (s *UnitService) CreateAirUnit(unit *models.AirUnit) error {
 			$1, $2, $3, $4, $5, $6, $7, $8
 		) RETURNING id, created_at, updated_at`
 
-	err := s.db.QueryRow(query,
+	err := q.QueryRow(query,
 		unit.GameID, unit.Type, unit.Owner, unit.Position, unit.BasePosition,
 		unit.MaxSpeed, unit.Endurance, unit.Status,
 	).Scan(&unit.ID, &unit.CreatedAt, &unit.UpdatedAt)
@@ -87,18 +132,25 @@ func (s *UnitService) CreateAirUnit(unit *models.AirUnit) error {
 
 // GetNavalUnitsByGameID возвращает все морские юниты игры
 func (s *UnitService) GetNavalUnitsByGameID(gameID string) ([]models.NavalUnit, error) {
+	return s.getNavalUnits(s.db, gameID, "")
+}
+
+// GetNavalUnitsByGameIDTx возвращает все морские юниты игры в рамках транзакции,
+// блокируя их строки до ее завершения
+func (s *UnitService) GetNavalUnitsByGameIDTx(tx *sql.Tx, gameID string) ([]models.NavalUnit, error) {
+	return s.getNavalUnits(tx, gameID, "FOR UPDATE")
+}
+
+// getNavalUnits читает морские юниты игры
+func (s *UnitService) getNavalUnits(q querier, gameID, lock string) ([]models.NavalUnit, error) {
 	query := `
-		SELECT id, game_id, name, type, class, owner, nationality, position,
-			   evasion, base_evasion, speed_rating, fuel, max_fuel,
-			   hull_boxes, current_hull, guns, torpedoes, max_torpedoes,
-			   search_factors, radar_level, status, detection_level,
-			   is_visible, last_known_pos, task_force_id, markers, damage,
-			   created_at, updated_at
+		SELECT ` + navalUnitColumns + `
 		FROM naval_units
 		WHERE game_id = $1
-		ORDER BY created_at`
+		ORDER BY created_at
+		` + lock
 
-	rows, err := s.db.Query(query, gameID)
+	rows, err := q.Query(query, gameID)
 	if err != nil {
 		s.logger.Error("Failed to get naval units", "game_id", gameID, "error", err)
 		return nil, fmt.Errorf("failed to get naval units: %w", err)
@@ -107,35 +159,13 @@ func (s *UnitService) GetNavalUnitsByGameID(gameID string) ([]models.NavalUnit,
 
 	var units []models.NavalUnit
 	for rows.Next() {
-		var unit models.NavalUnit
-		var damageJSON []byte
-		var lastKnownPos, taskForceID sql.NullString
-
-		err := rows.Scan(
-			&unit.ID, &unit.GameID, &unit.Name, &unit.Type, &unit.Class, &unit.Owner, &unit.Nationality, &unit.Position,
-			&unit.Evasion, &unit.BaseEvasion, &unit.SpeedRating, &unit.Fuel, &unit.MaxFuel,
-			&unit.HullBoxes, &unit.CurrentHull, &unit.PrimaryArmamentBow, &unit.PrimaryArmamentStern,
-			&unit.SecondaryArmament, &unit.BasePrimaryArmamentBow, &unit.BasePrimaryArmamentStern,
-			&unit.BaseSecondaryArmament, &unit.Torpedoes, &unit.MaxTorpedoes, &unit.RadarLevel,
-			&unit.Status, &unit.DetectionLevel, &lastKnownPos, &taskForceID, &damageJSON,
-			&unit.CreatedAt, &unit.UpdatedAt,
-		)
+		unit, err := scanNavalUnit(rows)
 		if err != nil {
 			s.logger.Error("Failed to scan naval unit", "error", err)
 			continue
 		}
 
-		// Парсим JSON поля
-		json.Unmarshal(damageJSON, &unit.Damage)
-
-		if lastKnownPos.Valid {
-			unit.LastKnownPos = &lastKnownPos.String
-		}
-		if taskForceID.Valid {
-			unit.TaskForceID = &taskForceID.String
-		}
-
-		units = append(units, unit)
+		units = append(units, *unit)
 	}
 
 	return units, rows.Err()
@@ -143,16 +173,25 @@ func (s *UnitService) GetNavalUnitsByGameID(gameID string) ([]models.NavalUnit,
 
 // GetAirUnitsByGameID возвращает все воздушные юниты игры
 func (s *UnitService) GetAirUnitsByGameID(gameID string) ([]models.AirUnit, error) {
+	return s.getAirUnits(s.db, gameID, "")
+}
+
+// GetAirUnitsByGameIDTx возвращает все воздушные юниты игры в рамках транзакции,
+// блокируя их строки до ее завершения
+func (s *UnitService) GetAirUnitsByGameIDTx(tx *sql.Tx, gameID string) ([]models.AirUnit, error) {
+	return s.getAirUnits(tx, gameID, "FOR UPDATE")
+}
+
+// getAirUnits читает воздушные юниты игры
+func (s *UnitService) getAirUnits(q querier, gameID, lock string) ([]models.AirUnit, error) {
 	query := `
-		SELECT id, game_id, name, type, owner, position, base_position,
-			   max_speed, endurance, current_fuel, search_factors,
-			   status, detection_level, is_visible, last_known_pos,
-			   markers, created_at, updated_at
+		SELECT ` + airUnitColumns + `
 		FROM air_units
 		WHERE game_id = $1
-		ORDER BY created_at`
+		ORDER BY created_at
+		` + lock
 
-	rows, err := s.db.Query(query, gameID)
+	rows, err := q.Query(query, gameID)
 	if err != nil {
 		s.logger.Error("Failed to get air units", "game_id", gameID, "error", err)
 		return nil, fmt.Errorf("failed to get air units: %w", err)
@@ -181,67 +220,61 @@ func (s *UnitService) GetAirUnitsByGameID(gameID string) ([]models.AirUnit, erro
 // GetNavalUnitByID возвращает морской юнит по ID
 func (s *UnitService) GetNavalUnitByID(unitID string) (*models.NavalUnit, error) {
 	query := `
-		SELECT id, game_id, name, type, class, owner, nationality, position,
-			   evasion, base_evasion, speed_rating, fuel, max_fuel,
-			   hull_boxes, current_hull, guns, torpedoes, max_torpedoes,
-			   search_factors, radar_level, status, detection_level,
-			   is_visible, last_known_pos, task_force_id, markers, damage,
-			   created_at, updated_at
+		SELECT ` + navalUnitColumns + `
 		FROM naval_units
 		WHERE id = $1`
 
-	var unit models.NavalUnit
-	var damageJSON []byte
-	var lastKnownPos, taskForceID sql.NullString
-
-	err := s.db.QueryRow(query, unitID).Scan(
-		&unit.ID, &unit.GameID, &unit.Name, &unit.Type, &unit.Class, &unit.Owner, &unit.Nationality, &unit.Position,
-		&unit.Evasion, &unit.BaseEvasion, &unit.SpeedRating, &unit.Fuel, &unit.MaxFuel,
-		&unit.HullBoxes, &unit.CurrentHull, &unit.PrimaryArmamentBow, &unit.PrimaryArmamentStern,
-		&unit.SecondaryArmament, &unit.BasePrimaryArmamentBow, &unit.BasePrimaryArmamentStern,
-		&unit.BaseSecondaryArmament, &unit.Torpedoes, &unit.MaxTorpedoes, &unit.RadarLevel,
-		&unit.Status, &unit.DetectionLevel, &lastKnownPos, &taskForceID, &damageJSON,
-		&unit.CreatedAt, &unit.UpdatedAt,
-	)
+	unit, err := scanNavalUnit(s.db.QueryRow(query, unitID))
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return nil, fmt.Errorf("naval unit not found")
+			return nil, ErrUnitNotFound
 		}
 		s.logger.Error("Failed to get naval unit", "unit_id", unitID, "error", err)
 		return nil, fmt.Errorf("failed to get naval unit: %w", err)
 	}
 
-	// Парсим JSON поля
-	json.Unmarshal(damageJSON, &unit.Damage)
-
-	if lastKnownPos.Valid {
-		unit.LastKnownPos = &lastKnownPos.String
-	}
-	if taskForceID.Valid {
-		unit.TaskForceID = &taskForceID.String
-	}
-
-	return &unit, nil
+	return unit, nil
 }
 
 // UpdateNavalUnit обновляет морской юнит
 func (s *UnitService) UpdateNavalUnit(unit *models.NavalUnit) error {
+	return s.updateNavalUnit(s.db, unit)
+}
+
+// UpdateNavalUnitTx обновляет морской юнит в рамках транзакции
+func (s *UnitService) UpdateNavalUnitTx(tx *sql.Tx, unit *models.NavalUnit) error {
+	return s.updateNavalUnit(tx, unit)
+}
+
+// execer выполняет запрос без возврата строк
+type execer interface {
+	Exec(query string, args ...interface{}) (sql.Result, error)
+}
+
+// updateNavalUnit сохраняет изменяемые поля морского юнита
+func (s *UnitService) updateNavalUnit(db execer, unit *models.NavalUnit) error {
 	query := `
 		UPDATE naval_units SET
 			position = $2, evasion = $3, fuel = $4,
 			current_hull = $5, torpedoes = $6, status = $7,
 			detection_level = $8, last_known_pos = $9,
-			task_force_id = $10, damage = $11,
-			updated_at = CURRENT_TIMESTAMP
+			task_force_id = $10, damage = $11, sunk_by = $12,
+			emergency_fuel_turn = $13, evasion_effects = $14, no_movement = $15,
+			tactical_position = $16, tactical_facing = $17, tactical_speed = $18,
+			crew_exhausted = $19, has_fired = $20, updated_at = CURRENT_TIMESTAMP
 		WHERE id = $1`
 
 	damageJSON, _ := json.Marshal(unit.Damage)
+	evasionEffectsJSON, _ := json.Marshal(unit.EvasionEffects)
 
-	_, err := s.db.Exec(query,
+	_, err := db.Exec(query,
 		unit.ID, unit.Position, unit.Evasion, unit.Fuel,
 		unit.CurrentHull, unit.Torpedoes, unit.Status,
 		unit.DetectionLevel, unit.LastKnownPos,
-		unit.TaskForceID, damageJSON,
+		unit.TaskForceID, damageJSON, unit.SunkBy,
+		unit.EmergencyFuelTurn, evasionEffectsJSON, unit.NoMovement,
+		unit.TacticalPosition, unit.TacticalFacing, unit.TacticalSpeed,
+		unit.CrewExhausted, unit.HasFired,
 	)
 	if err != nil {
 		s.logger.Error("Failed to update naval unit", "unit_id", unit.ID, "error", err)
@@ -254,12 +287,22 @@ func (s *UnitService) UpdateNavalUnit(unit *models.NavalUnit) error {
 
 // UpdateAirUnit обновляет воздушный юнит
 func (s *UnitService) UpdateAirUnit(unit *models.AirUnit) error {
+	return s.updateAirUnit(s.db, unit)
+}
+
+// UpdateAirUnitTx обновляет воздушный юнит в рамках транзакции
+func (s *UnitService) UpdateAirUnitTx(tx *sql.Tx, unit *models.AirUnit) error {
+	return s.updateAirUnit(tx, unit)
+}
+
+// updateAirUnit сохраняет изменяемые поля воздушного юнита
+func (s *UnitService) updateAirUnit(db execer, unit *models.AirUnit) error {
 	query := `
 		UPDATE air_units SET
 			position = $2, status = $3, updated_at = CURRENT_TIMESTAMP
 		WHERE id = $1`
 
-	_, err := s.db.Exec(query,
+	_, err := db.Exec(query,
 		unit.ID, unit.Position, unit.Status,
 	)
 	if err != nil {
@@ -284,20 +327,41 @@ func (s *UnitService) MoveUnit(unitID string, to string, speed int, fuelCost int
 		return fmt.Errorf("unit cannot move")
 	}
 
+	// Корабль в составе ТФ двигается только вместе с ней
+	if unit.TaskForceID != nil {
+		return ErrUnitMovesWithTaskForce
+	}
+
 	// Проверяем топливо
 	if unit.Fuel < fuelCost {
 		return fmt.Errorf("insufficient fuel")
 	}
 
-	// Обновляем позицию и топливо
-	unit.Position = to
-	unit.Fuel -= fuelCost
+	// Обновляем позицию и топливо и сохраняем движение в историю
+	movement := applyUnitMove(unit, to, speed, fuelCost, path, turn, phase)
+
+	err = s.RecordMovement(&movement)
+	if err != nil {
+		return fmt.Errorf("failed to record movement: %w", err)
+	}
 
-	// Сохраняем движение в историю
+	// Обновляем юнит
+	err = s.UpdateNavalUnit(unit)
+	if err != nil {
+		return fmt.Errorf("failed to update unit: %w", err)
+	}
+
+	s.logger.Info("Moved unit", "unit_id", unitID, "from", movement.From, "to", to, "fuel_cost", fuelCost)
+	return nil
+}
+
+// applyUnitMove перемещает корабль в гекс to, списывает топливо и возвращает запись движения
+// из исходной позиции корабля
+func applyUnitMove(unit *models.NavalUnit, to string, speed int, fuelCost int, path []string, turn int, phase models.GamePhase) models.UnitMovement {
 	movement := models.UnitMovement{
 		ID:        "", // будет сгенерирован базой данных
 		GameID:    unit.GameID,
-		UnitID:    unitID,
+		UnitID:    unit.ID,
 		From:      unit.Position,
 		To:        to,
 		Path:      path,
@@ -308,23 +372,26 @@ func (s *UnitService) MoveUnit(unitID string, to string, speed int, fuelCost int
 		CreatedAt: time.Now(),
 	}
 
-	err = s.RecordMovement(&movement)
-	if err != nil {
-		return fmt.Errorf("failed to record movement: %w", err)
-	}
-
-	// Обновляем юнит
-	err = s.UpdateNavalUnit(unit)
-	if err != nil {
-		return fmt.Errorf("failed to update unit: %w", err)
-	}
+	unit.Position = to
+	unit.Fuel -= fuelCost
+	unit.StartEmergencyFuel(turn)
+	unit.MarkNoMovementAfterMove()
 
-	s.logger.Info("Moved unit", "unit_id", unitID, "from", unit.Position, "to", to, "fuel_cost", fuelCost)
-	return nil
+	return movement
 }
 
 // RecordMovement записывает движение юнита в историю
 func (s *UnitService) RecordMovement(movement *models.UnitMovement) error {
+	return s.recordMovement(s.db, movement)
+}
+
+// RecordMovementTx записывает движение юнита в историю в рамках транзакции
+func (s *UnitService) RecordMovementTx(tx *sql.Tx, movement *models.UnitMovement) error {
+	return s.recordMovement(tx, movement)
+}
+
+// recordMovement сохраняет запись о движении
+func (s *UnitService) recordMovement(q querier, movement *models.UnitMovement) error {
 	query := `
 		INSERT INTO unit_movements (
 			game_id, unit_id, from_pos, to_pos, path, speed, fuel_cost,
@@ -335,7 +402,7 @@ func (s *UnitService) RecordMovement(movement *models.UnitMovement) error {
 
 	pathJSON, _ := json.Marshal(movement.Path)
 
-	err := s.db.QueryRow(query,
+	err := q.QueryRow(query,
 		movement.GameID, movement.UnitID, movement.From, movement.To, pathJSON,
 		movement.Speed, movement.FuelCost, movement.IsShadowed,
 		movement.Turn, movement.Phase,
@@ -349,8 +416,44 @@ func (s *UnitService) RecordMovement(movement *models.UnitMovement) error {
 	return nil
 }
 
+// HasMovedInTurn проверяет, перемещался ли юнит в указанном ходу
+func (s *UnitService) HasMovedInTurn(unitID string, turn int) (bool, error) {
+	query := `SELECT EXISTS(SELECT 1 FROM unit_movements WHERE unit_id = $1 AND turn = $2)`
+
+	var moved bool
+	if err := s.db.QueryRow(query, unitID, turn).Scan(&moved); err != nil {
+		s.logger.Error("Failed to check unit movements", "unit_id", unitID, "error", err)
+		return false, fmt.Errorf("failed to check unit movements: %w", err)
+	}
+
+	return moved, nil
+}
+
+// GetMovedUnitIDs возвращает множество юнитов игры, перемещавшихся в указанном ходу
+func (s *UnitService) GetMovedUnitIDs(gameID string, turn int) (map[string]bool, error) {
+	query := `SELECT DISTINCT unit_id FROM unit_movements WHERE game_id = $1 AND turn = $2`
+
+	rows, err := s.db.Query(query, gameID, turn)
+	if err != nil {
+		s.logger.Error("Failed to get unit movements", "game_id", gameID, "error", err)
+		return nil, fmt.Errorf("failed to get unit movements: %w", err)
+	}
+	defer rows.Close()
+
+	moved := make(map[string]bool)
+	for rows.Next() {
+		var unitID string
+		if err := rows.Scan(&unitID); err != nil {
+			return nil, fmt.Errorf("failed to scan unit movement: %w", err)
+		}
+		moved[unitID] = true
+	}
+
+	return moved, rows.Err()
+}
+
 // SearchUnit выполняет поиск юнитом
-func (s *UnitService) SearchUnit(unitID string, targetHex string, searchType string, turn int, phase models.GamePhase) (*models.UnitSearch, error) {
+func (s *UnitService) SearchUnit(game *models.Game, unitID string, targetHex string, searchType string) (*models.UnitSearch, error) {
 	// Получаем юнит
 	unit, err := s.GetNavalUnitByID(unitID)
 	if err != nil {
@@ -362,6 +465,12 @@ func (s *UnitService) SearchUnit(unitID string, targetHex string, searchType str
 		return nil, fmt.Errorf("unit cannot search")
 	}
 
+	patrolFactors, err := s.getPatrolFactors(game, unit, targetHex)
+	if err != nil {
+		return nil, err
+	}
+	turn, phase := game.CurrentTurn, game.CurrentPhase
+
 	// Создаем запись поиска
 	search := &models.UnitSearch{
 		ID:            "", // будет сгенерирован базой данных
@@ -369,7 +478,8 @@ func (s *UnitService) SearchUnit(unitID string, targetHex string, searchType str
 		UnitID:        unitID,
 		TargetHex:     targetHex,
 		SearchType:    searchType,
-		SearchFactors: 1,            // Все корабли дают 1 фактор поиска
+		SearchFactors: 1 + patrolFactors, // Все корабли дают 1 фактор поиска
+		PatrolFactors: patrolFactors,
 		Result:        "no_contact", // по умолчанию
 		UnitsFound:    []string{},
 		Turn:          turn,
@@ -389,21 +499,74 @@ func (s *UnitService) SearchUnit(unitID string, targetHex string, searchType str
 	return search, nil
 }
 
+// GetMarkers возвращает маркеры игры, подходящие под фильтр: размещенные маркеры
+// и маркеры состояния кораблей игры units, построенные из их полей
+func (s *UnitService) GetMarkers(game *models.Game, units []models.NavalUnit, filter models.MarkerFilter) ([]models.Marker, error) {
+	markers, err := s.markerService.GetMarkers(game.ID, filter)
+	if err != nil {
+		return nil, err
+	}
+	for _, marker := range models.UnitMarkers(game, units) {
+		if filter.Matches(&marker) {
+			markers = append(markers, marker)
+		}
+	}
+	return markers, nil
+}
+
+// getPatrolFactors возвращает факторы поиска от маркеров Морского патруля стороны в целевом гексе.
+// Они добавляются один раз за поиск гекса, а не за каждый искавший корабль
+func (s *UnitService) getPatrolFactors(game *models.Game, unit *models.NavalUnit, targetHex string) (int, error) {
+	units, err := s.GetNavalUnitsByGameID(game.ID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get naval units: %w", err)
+	}
+	markers, err := s.GetMarkers(game, units, models.MarkerFilter{Type: models.MarkerPatrol, Hex: targetHex})
+	if err != nil {
+		return 0, err
+	}
+
+	query := `
+		SELECT COUNT(*)
+		FROM unit_searches s
+		JOIN naval_units u ON u.id = s.unit_id
+		WHERE s.game_id = $1 AND s.target_hex = $2 AND s.turn = $3 AND s.phase = $4
+		  AND u.owner = $5 AND s.patrol_factors > 0`
+
+	var counted int
+	err = s.db.QueryRow(query, game.ID, targetHex, game.CurrentTurn, game.CurrentPhase, unit.Owner).Scan(&counted)
+	if err != nil {
+		s.logger.Error("Failed to get hex searches", "game_id", game.ID, "hex", targetHex, "error", err)
+		return 0, fmt.Errorf("failed to get hex searches: %w", err)
+	}
+
+	return patrolSearchFactors(markers, unitSide(game, unit), targetHex, counted > 0), nil
+}
+
+// patrolSearchFactors возвращает факторы поиска от маркеров Морского патруля стороны в гексе.
+// Если они уже учтены в поиске этого гекса на текущем ходу, факторов нет
+func patrolSearchFactors(markers []models.Marker, side models.PlayerSide, hex string, counted bool) int {
+	if counted {
+		return 0
+	}
+	return models.CountPatrolMarkers(markers, side, hex) * models.PatrolSearchFactors
+}
+
 // RecordSearch записывает поиск юнита в историю
 func (s *UnitService) RecordSearch(search *models.UnitSearch) error {
 	query := `
 		INSERT INTO unit_searches (
 			game_id, unit_id, target_hex, search_type, search_factors,
-			result, units_found, turn, phase
+			patrol_factors, result, units_found, turn, phase
 		) VALUES (
-			$1, $2, $3, $4, $5, $6, $7, $8, $9
+			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10
 		) RETURNING id, created_at`
 
 	unitsFoundJSON, _ := json.Marshal(search.UnitsFound)
 
 	err := s.db.QueryRow(query,
 		search.GameID, search.UnitID, search.TargetHex, search.SearchType, search.SearchFactors,
-		search.Result, unitsFoundJSON, search.Turn, search.Phase,
+		search.PatrolFactors, search.Result, unitsFoundJSON, search.Turn, search.Phase,
 	).Scan(&search.ID, &search.CreatedAt)
 
 	if err != nil {
@@ -418,12 +581,7 @@ func (s *UnitService) RecordSearch(search *models.UnitSearch) error {
 func (s *UnitService) GetUnitsByPosition(gameID string, position string) ([]models.NavalUnit, []models.AirUnit, error) {
 	// Получаем морские юниты
 	navalQuery := `
-		SELECT id, game_id, name, type, class, owner, nationality, position,
-			   evasion, base_evasion, speed_rating, fuel, max_fuel,
-			   hull_boxes, current_hull, guns, torpedoes, max_torpedoes,
-			   search_factors, radar_level, status, detection_level,
-			   is_visible, last_known_pos, task_force_id, markers, damage,
-			   created_at, updated_at
+		SELECT ` + navalUnitColumns + `
 		FROM naval_units
 		WHERE game_id = $1 AND position = $2`
 
@@ -435,41 +593,17 @@ func (s *UnitService) GetUnitsByPosition(gameID string, position string) ([]mode
 
 	var navalUnits []models.NavalUnit
 	for navalRows.Next() {
-		var unit models.NavalUnit
-		var damageJSON []byte
-		var lastKnownPos, taskForceID sql.NullString
-
-		err := navalRows.Scan(
-			&unit.ID, &unit.GameID, &unit.Name, &unit.Type, &unit.Class, &unit.Owner, &unit.Nationality, &unit.Position,
-			&unit.Evasion, &unit.BaseEvasion, &unit.SpeedRating, &unit.Fuel, &unit.MaxFuel,
-			&unit.HullBoxes, &unit.CurrentHull, &unit.PrimaryArmamentBow, &unit.PrimaryArmamentStern,
-			&unit.SecondaryArmament, &unit.BasePrimaryArmamentBow, &unit.BasePrimaryArmamentStern,
-			&unit.BaseSecondaryArmament, &unit.Torpedoes, &unit.MaxTorpedoes, &unit.RadarLevel,
-			&unit.Status, &unit.DetectionLevel, &lastKnownPos, &taskForceID, &damageJSON,
-			&unit.CreatedAt, &unit.UpdatedAt,
-		)
+		unit, err := scanNavalUnit(navalRows)
 		if err != nil {
 			continue
 		}
 
-		json.Unmarshal(damageJSON, &unit.Damage)
-
-		if lastKnownPos.Valid {
-			unit.LastKnownPos = &lastKnownPos.String
-		}
-		if taskForceID.Valid {
-			unit.TaskForceID = &taskForceID.String
-		}
-
-		navalUnits = append(navalUnits, unit)
+		navalUnits = append(navalUnits, *unit)
 	}
 
 	// Получаем воздушные юниты
 	airQuery := `
-		SELECT id, game_id, name, type, owner, position, base_position,
-			   max_speed, endurance, current_fuel, search_factors,
-			   status, detection_level, is_visible, last_known_pos,
-			   markers, created_at, updated_at
+		SELECT ` + airUnitColumns + `
 		FROM air_units
 		WHERE game_id = $1 AND position = $2`
 
@@ -496,3 +630,85 @@ func (s *UnitService) GetUnitsByPosition(gameID string, position string) ([]mode
 
 	return navalUnits, airUnits, nil
 }
+
+// navalUnitColumns список колонок морского юнита в порядке scanNavalUnit
+const navalUnitColumns = `id, game_id, name, type, class, owner, nationality, position,
+			   evasion, base_evasion, speed_rating, fuel, max_fuel,
+			   hull_boxes, current_hull, primary_armament_bow, primary_armament_stern,
+			   secondary_armament, base_primary_armament_bow, base_primary_armament_stern,
+			   base_secondary_armament, torpedoes, max_torpedoes, radar_level,
+			   status, detection_level, last_known_pos, task_force_id, damage, sunk_by,
+			   emergency_fuel_turn, evasion_effects, no_movement, arrival_turn,
+			   tactical_position, tactical_facing, tactical_speed, crew_exhausted, has_fired,
+			   created_at, updated_at`
+
+// airUnitColumns список колонок воздушного юнита в порядке сканирования
+const airUnitColumns = `id, game_id, type, owner, position, base_position,
+			   max_speed, endurance, status, created_at, updated_at`
+
+// rowScanner позволяет сканировать как *sql.Row, так и *sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanNavalUnit сканирует морской юнит из строки результата
+func scanNavalUnit(row rowScanner) (*models.NavalUnit, error) {
+	var unit models.NavalUnit
+	var damageJSON, evasionEffectsJSON []byte
+	var lastKnownPos, taskForceID, sunkBy sql.NullString
+	var emergencyFuelTurn, arrivalTurn sql.NullInt64
+	var tacticalPosition, tacticalFacing sql.NullString
+	var tacticalSpeed sql.NullInt64
+	var crewExhausted, hasFired sql.NullBool
+
+	err := row.Scan(
+		&unit.ID, &unit.GameID, &unit.Name, &unit.Type, &unit.Class, &unit.Owner, &unit.Nationality, &unit.Position,
+		&unit.Evasion, &unit.BaseEvasion, &unit.SpeedRating, &unit.Fuel, &unit.MaxFuel,
+		&unit.HullBoxes, &unit.CurrentHull, &unit.PrimaryArmamentBow, &unit.PrimaryArmamentStern,
+		&unit.SecondaryArmament, &unit.BasePrimaryArmamentBow, &unit.BasePrimaryArmamentStern,
+		&unit.BaseSecondaryArmament, &unit.Torpedoes, &unit.MaxTorpedoes, &unit.RadarLevel,
+		&unit.Status, &unit.DetectionLevel, &lastKnownPos, &taskForceID, &damageJSON, &sunkBy,
+		&emergencyFuelTurn, &evasionEffectsJSON, &unit.NoMovement, &arrivalTurn,
+		&tacticalPosition, &tacticalFacing, &tacticalSpeed, &crewExhausted, &hasFired,
+		&unit.CreatedAt, &unit.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	// Парсим JSON поля
+	json.Unmarshal(damageJSON, &unit.Damage)
+	json.Unmarshal(evasionEffectsJSON, &unit.EvasionEffects)
+
+	if lastKnownPos.Valid {
+		unit.LastKnownPos = &lastKnownPos.String
+	}
+	if taskForceID.Valid {
+		unit.TaskForceID = &taskForceID.String
+	}
+	if sunkBy.Valid {
+		unit.SunkBy = &sunkBy.String
+	}
+	if emergencyFuelTurn.Valid {
+		deadline := int(emergencyFuelTurn.Int64)
+		unit.EmergencyFuelTurn = &deadline
+	}
+	if arrivalTurn.Valid {
+		turn := int(arrivalTurn.Int64)
+		unit.ArrivalTurn = &turn
+	}
+	if tacticalPosition.Valid {
+		unit.TacticalPosition = &tacticalPosition.String
+	}
+	if tacticalFacing.Valid {
+		unit.TacticalFacing = &tacticalFacing.String
+	}
+	if tacticalSpeed.Valid {
+		speed := int(tacticalSpeed.Int64)
+		unit.TacticalSpeed = &speed
+	}
+	unit.CrewExhausted = crewExhausted.Bool
+	unit.HasFired = hasFired.Bool
+
+	return &unit, nil
+}
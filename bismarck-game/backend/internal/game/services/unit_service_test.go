@@ -0,0 +1,21 @@
+package services
+
+import (
+	"bismarck-game/backend/internal/game/models"
+	"testing"
+)
+
+func TestApplyUnitMove(t *testing.T) {
+	unit := &models.NavalUnit{ID: "norfolk", GameID: "game", Position: "I28", HullBoxes: 6, CurrentHull: 6, MaxFuel: 20, Fuel: 20}
+
+	movement := applyUnitMove(unit, "J27", 2, 2, []string{"I28", "J27"}, 3, models.PhaseMovement)
+	if movement.From != "I28" || movement.To != "J27" {
+		t.Errorf("Движение должно идти из исходной позиции I28 в J27, получено %s -> %s", movement.From, movement.To)
+	}
+	if movement.UnitID != unit.ID || movement.Turn != 3 || movement.Phase != models.PhaseMovement {
+		t.Errorf("Неверная запись движения: %+v", movement)
+	}
+	if unit.Position != "J27" || unit.Fuel != 18 {
+		t.Errorf("Корабль должен быть в J27 с 18 топлива, получено %s и %d", unit.Position, unit.Fuel)
+	}
+}
@@ -0,0 +1,293 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"bismarck-game/backend/internal/game/models"
+	"bismarck-game/backend/pkg/database"
+	"bismarck-game/backend/pkg/logger"
+)
+
+// VictoryService подсчитывает очки победы и определяет итог игры
+type VictoryService struct {
+	db              *database.Database
+	logger          *logger.Logger
+	unitService     *UnitService
+	convoyVPService *ConvoyVPService
+}
+
+// NewVictoryService создает новый сервис очков победы
+func NewVictoryService(db *database.Database, logger *logger.Logger, unitService *UnitService, convoyVPService *ConvoyVPService) *VictoryService {
+	return &VictoryService{
+		db:              db,
+		logger:          logger,
+		unitService:     unitService,
+		convoyVPService: convoyVPService,
+	}
+}
+
+// AdjudicateGame подсчитывает очки победы, заполняет Winner и VictoryType игры
+// и возвращает полный итоговый подсчет
+func (s *VictoryService) AdjudicateGame(game *models.Game, reason models.GameEndReason) (*models.VictoryBreakdown, error) {
+	units, err := s.unitService.GetNavalUnitsByGameID(game.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get units: %w", err)
+	}
+
+	box, err := s.convoyVPService.RevealBox(game)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reveal convoy VP box: %w", err)
+	}
+
+	purchases, err := getOptionalUnitPurchases(s.db, game.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	breakdown := calculateVictory(game, units, box, purchases, reason)
+	applyVictoryResult(game, breakdown)
+
+	s.logger.Info("Game adjudicated",
+		"game_id", game.ID,
+		"end_reason", reason,
+		"total_vp", breakdown.TotalVP,
+		"winner_side", breakdown.WinnerSide,
+		"victory_type", breakdown.VictoryType)
+
+	return breakdown, nil
+}
+
+// calculateVictory выполняет подсчет очков победы с точки зрения немецкого игрока
+func calculateVictory(game *models.Game, units []models.NavalUnit, box *models.ConvoyVPBox, purchases []models.OptionalUnitPurchase, reason models.GameEndReason) *models.VictoryBreakdown {
+	cfg := effectiveVictoryConfig(game.Settings.VictoryConditions)
+
+	breakdown := &models.VictoryBreakdown{
+		GameID:                  game.ID,
+		EndReason:               reason,
+		Lines:                   []models.VictoryLine{},
+		BritishCapitalShipsSunk: []string{},
+		CalculatedAt:            time.Now(),
+	}
+
+	// Очки за потопленные и поврежденные корабли
+	var bismarck *models.NavalUnit
+	for i := range units {
+		unit := &units[i]
+		if unit.IsBismarck() {
+			bismarck = unit
+		}
+
+		// Потери союзников приносят VP немецкому игроку, потери немцев - отнимают
+		sign := 1.0
+		switch unitSide(game, unit) {
+		case models.PlayerSideAllied:
+		case models.PlayerSideGerman:
+			sign = -1.0
+		default:
+			continue
+		}
+
+		shipVP := shipVPConfigFor(cfg, unit.Type)
+		if !unit.IsAlive() {
+			if unit.IsBismarck() {
+				continue // Потопление "Бисмарка" учитывается как условие окончания игры
+			}
+			breakdown.AddLine(models.VictoryLine{
+				Category:    models.VictoryLineShipSunk,
+				Description: fmt.Sprintf("%s sunk", unit.Name),
+				UnitID:      unit.ID,
+				VP:          sign * resolveShipVP(shipVP.Sunk, unit),
+			})
+			continue
+		}
+
+		if unit.GetHullHits() > 0 {
+			vp := resolveShipVP(shipVP.Damaged, unit)
+			if vp != 0 {
+				breakdown.AddLine(models.VictoryLine{
+					Category:    models.VictoryLineShipDamaged,
+					Description: fmt.Sprintf("%s damaged (%d hits)", unit.Name, unit.GetHullHits()),
+					UnitID:      unit.ID,
+					VP:          sign * vp,
+				})
+			}
+		}
+	}
+
+	// Очки за конвои и одиночные торговые суда (раскрываются только здесь)
+	for _, marker := range box.Markers {
+		breakdown.AddLine(models.VictoryLine{
+			Category:    models.VictoryLineConvoy,
+			Description: fmt.Sprintf("Convoy %s VP marker", marker.ConvoyID),
+			VP:          marker.Value,
+		})
+	}
+	if box.MerchantsSunk > 0 {
+		breakdown.AddLine(models.VictoryLine{
+			Category:    models.VictoryLineMerchant,
+			Description: fmt.Sprintf("%d single merchants sunk", box.MerchantsSunk),
+			VP:          box.MerchantVP(),
+		})
+	}
+	summary := box.Summary()
+	breakdown.ConvoyBox = &summary
+
+	// Стоимость купленных гипотетических юнитов (13.1)
+	for _, purchase := range purchases {
+		breakdown.AddLine(models.VictoryLine{
+			Category:    models.VictoryLineOptionalUnit,
+			Description: fmt.Sprintf("Optional unit %s purchased by %s", purchase.ShipID, purchase.Side),
+			UnitID:      purchase.UnitID,
+			VP:          float64(purchase.VP),
+		})
+	}
+
+	// Модификатор условия окончания игры
+	endVP, endDescription := endConditionVP(cfg, reason, bismarck)
+	breakdown.AddLine(models.VictoryLine{
+		Category:    models.VictoryLineEndCondition,
+		Description: endDescription,
+		VP:          float64(endVP),
+	})
+
+	// Потопленные британские капитальные корабли
+	for i := range units {
+		unit := &units[i]
+		if !unit.IsAlive() && unit.IsCapitalShip() && isBritishUnit(game, unit) {
+			breakdown.BritishCapitalShipsSunk = append(breakdown.BritishCapitalShipsSunk, unit.Name)
+		}
+	}
+
+	// Определяем итог
+	switch {
+	case reason == models.GameEndBismarckSunk:
+		breakdown.WinnerSide = models.PlayerSideAllied
+		breakdown.VictoryType = models.VictoryTypeOperational
+	case isStrategicVictory(breakdown.BritishCapitalShipsSunk):
+		breakdown.WinnerSide = models.PlayerSideGerman
+		breakdown.VictoryType = models.VictoryTypeStrategic
+	case breakdown.TotalVP >= 1:
+		breakdown.WinnerSide = models.PlayerSideGerman
+		breakdown.VictoryType = models.VictoryTypeOperational
+	default:
+		breakdown.WinnerSide = models.PlayerSideAllied
+		breakdown.VictoryType = models.VictoryTypeOperational
+	}
+
+	return breakdown
+}
+
+// applyVictoryResult заполняет Winner и VictoryType игры
+func applyVictoryResult(game *models.Game, breakdown *models.VictoryBreakdown) {
+	winner := game.Player2ID
+	if breakdown.WinnerSide == models.PlayerSideGerman {
+		winner = game.Player1ID
+	}
+	game.Winner = &winner
+	game.VictoryType = breakdown.VictoryType
+}
+
+// endConditionVP возвращает модификатор VP за условие окончания игры
+func endConditionVP(cfg models.VictoryConfig, reason models.GameEndReason, bismarck *models.NavalUnit) (int, string) {
+	switch reason {
+	case models.GameEndBismarckSunk:
+		return *cfg.BismarckSunkVP, "Bismarck sunk"
+	case models.GameEndBismarckFrance:
+		return *cfg.BismarckFranceVP, "Bismarck in port in France"
+	case models.GameEndBismarckNorway:
+		return *cfg.BismarckNorwayVP, "Bismarck in port in Norway"
+	case models.GameEndBismarckNoFuel:
+		return *cfg.BismarckNoFuelVP, "Bismarck ran out of emergency fuel"
+	default:
+		if bismarck != nil && bismarck.IsUndamaged() && bismarck.Fuel >= 10 {
+			return *cfg.BismarckUndamagedAtSeaVP, "Bismarck undamaged at sea with at least 10 FP"
+		}
+		return *cfg.BismarckEndGameVP, "Game ended on the turn track"
+	}
+}
+
+// isStrategicVictory проверяет условие Стратегической победы Германии:
+// потоплены два британских капитальных корабля, включая King George V
+func isStrategicVictory(capitalsSunk []string) bool {
+	if len(capitalsSunk) < 2 {
+		return false
+	}
+	for _, name := range capitalsSunk {
+		if strings.EqualFold(name, models.StrategicVictoryTarget) {
+			return true
+		}
+	}
+	return false
+}
+
+// resolveShipVP вычисляет VP по значению из ShipVPConfig
+func resolveShipVP(value interface{}, unit *models.NavalUnit) float64 {
+	switch v := value.(type) {
+	case string:
+		switch v {
+		case models.ShipVPHullBoxes:
+			return float64(unit.HullBoxes)
+		case models.ShipVPHalfHits:
+			return float64(unit.GetHullHits() / 2)
+		}
+	case int:
+		return float64(v)
+	case float64:
+		return v
+	}
+	return 0
+}
+
+// shipVPConfigFor возвращает конфигурацию очков для типа корабля
+func shipVPConfigFor(cfg models.VictoryConfig, unitType models.UnitType) models.ShipVPConfig {
+	if shipVP, ok := cfg.ShipVPValues[string(unitType)]; ok {
+		return shipVP
+	}
+	return cfg.ShipVPValues[models.ShipVPOthers]
+}
+
+// effectiveVictoryConfig дополняет незаданные значения конфигурации значениями по умолчанию
+func effectiveVictoryConfig(cfg models.VictoryConfig) models.VictoryConfig {
+	defaults := models.GetDefaultGameSettings().VictoryConditions
+
+	values := []struct {
+		value    **int
+		fallback *int
+	}{
+		{&cfg.BismarckSunkVP, defaults.BismarckSunkVP},
+		{&cfg.BismarckUndamagedAtSeaVP, defaults.BismarckUndamagedAtSeaVP},
+		{&cfg.BismarckFranceVP, defaults.BismarckFranceVP},
+		{&cfg.BismarckNorwayVP, defaults.BismarckNorwayVP},
+		{&cfg.BismarckEndGameVP, defaults.BismarckEndGameVP},
+		{&cfg.BismarckNoFuelVP, defaults.BismarckNoFuelVP},
+	}
+	for _, v := range values {
+		if *v.value == nil {
+			*v.value = v.fallback
+		}
+	}
+	if len(cfg.ShipVPValues) == 0 {
+		cfg.ShipVPValues = defaults.ShipVPValues
+	}
+
+	return cfg
+}
+
+// unitSide возвращает сторону, которой принадлежит юнит
+func unitSide(game *models.Game, unit *models.NavalUnit) models.PlayerSide {
+	return game.GetOwnerSide(unit.Owner)
+}
+
+// isBritishUnit проверяет, является ли юнит британским кораблем
+func isBritishUnit(game *models.Game, unit *models.NavalUnit) bool {
+	if unitSide(game, unit) != models.PlayerSideAllied {
+		return false
+	}
+	switch strings.ToLower(unit.Nationality) {
+	case "us", "usa", "american":
+		return false
+	}
+	return true
+}
@@ -0,0 +1,167 @@
+package services
+
+import (
+	"bismarck-game/backend/internal/game/models"
+	"testing"
+)
+
+func newVictoryTestGame() *models.Game {
+	return &models.Game{
+		ID:        "game",
+		Player1ID: "german",
+		Player2ID: "allied",
+		Status:    models.GameStatusActive,
+		Settings:  models.GetDefaultGameSettings(),
+	}
+}
+
+func TestCalculateVictory(t *testing.T) {
+	bismarck := models.NavalUnit{
+		ID: "bismarck", Name: "BISMARCK", Type: models.UnitTypeBattleship, Owner: "german",
+		HullBoxes: 12, CurrentHull: 12, Fuel: 12, Status: models.UnitStatusActive,
+	}
+
+	t.Run("ShipScoring", func(t *testing.T) {
+		game := newVictoryTestGame()
+		units := []models.NavalUnit{
+			bismarck,
+			// Потопленный линкор: VP равно отсекам корпуса
+			{ID: "hood", Name: "HOOD", Type: models.UnitTypeBattlecruiser, Owner: "allied",
+				HullBoxes: 9, CurrentHull: 0, Status: models.UnitStatusSunk},
+			// Поврежденный линкор: 1 VP за каждые два попадания
+			{ID: "kgv", Name: "KING GEORGE V", Type: models.UnitTypeBattleship, Owner: "allied",
+				HullBoxes: 12, CurrentHull: 7, Status: models.UnitStatusActive},
+			// Потопленный эсминец: 1 VP
+			{ID: "dd", Name: "4TH DF", Type: models.UnitTypeDestroyer, Owner: "allied",
+				HullBoxes: 2, CurrentHull: 0, Status: models.UnitStatusSunk},
+			// Поврежденный немецкий крейсер отнимает VP
+			{ID: "pe", Name: "PRINZ EUGEN", Type: models.UnitTypeHeavyCruiser, Owner: "german",
+				HullBoxes: 6, CurrentHull: 2, Status: models.UnitStatusDamaged},
+		}
+		box := &models.ConvoyVPBox{MerchantValue: 0.5}
+
+		breakdown := calculateVictory(game, units, box, nil, models.GameEndTurnTrack)
+
+		// 9 + 2 + 1 - 2 = 10
+		if breakdown.ShipVP != 10 {
+			t.Errorf("Ожидалось 10 VP за корабли, получено %.1f", breakdown.ShipVP)
+		}
+		// Бисмарк не поврежден в море с 12 FP: -4
+		if breakdown.EndConditionVP != -4 {
+			t.Errorf("Ожидалось -4 VP за условие окончания, получено %.1f", breakdown.EndConditionVP)
+		}
+		if breakdown.TotalVP != 6 {
+			t.Errorf("Ожидалось 6 VP итого, получено %.1f", breakdown.TotalVP)
+		}
+		if breakdown.WinnerSide != models.PlayerSideGerman || breakdown.VictoryType != models.VictoryTypeOperational {
+			t.Errorf("Ожидалась оперативная победа Германии, получено %s/%s", breakdown.WinnerSide, breakdown.VictoryType)
+		}
+	})
+
+	t.Run("ConvoysRevealedInBreakdown", func(t *testing.T) {
+		game := newVictoryTestGame()
+		box := &models.ConvoyVPBox{
+			Markers: []models.ConvoyVPMarker{
+				{Value: 2, ConvoyID: "HX 127"},
+				{Value: 2, ConvoyID: "SL 74"},
+				{Value: 1.5, ConvoyID: "OB 318"},
+				{Value: 1.5, ConvoyID: "HX 126"},
+			},
+			MerchantsSunk: 2,
+			MerchantValue: 0.5,
+		}
+		damaged := bismarck
+		damaged.CurrentHull = 10
+
+		breakdown := calculateVictory(game, []models.NavalUnit{damaged}, box, nil, models.GameEndBismarckFrance)
+
+		if breakdown.ConvoyVP != 7 || breakdown.MerchantVP != 1 {
+			t.Errorf("Неверные VP за конвои: %.1f/%.1f", breakdown.ConvoyVP, breakdown.MerchantVP)
+		}
+		// 7 + 1 - 1 (повреждения Бисмарка) - 5 (Франция) = 2
+		if breakdown.TotalVP != 2 {
+			t.Errorf("Ожидалось 2 VP итого, получено %.1f", breakdown.TotalVP)
+		}
+		if breakdown.ConvoyBox == nil || len(breakdown.ConvoyBox.Markers) != 4 {
+			t.Error("Коробка конвоя должна быть раскрыта в итоговом подсчете")
+		}
+	})
+
+	t.Run("BismarckSunk", func(t *testing.T) {
+		game := newVictoryTestGame()
+		sunk := bismarck
+		sunk.CurrentHull = 0
+		sunk.Status = models.UnitStatusSunk
+		box := &models.ConvoyVPBox{
+			Markers: []models.ConvoyVPMarker{{Value: 2}},
+		}
+
+		breakdown := calculateVictory(game, []models.NavalUnit{sunk}, box, nil, models.GameEndBismarckSunk)
+		applyVictoryResult(game, breakdown)
+
+		if breakdown.WinnerSide != models.PlayerSideAllied || breakdown.VictoryType != models.VictoryTypeOperational {
+			t.Errorf("Потопление Бисмарка - оперативная победа союзников, получено %s/%s", breakdown.WinnerSide, breakdown.VictoryType)
+		}
+		if game.Winner == nil || *game.Winner != "allied" {
+			t.Error("Победителем должен быть игрок союзников")
+		}
+	})
+
+	t.Run("StrategicVictory", func(t *testing.T) {
+		game := newVictoryTestGame()
+		pow := models.NavalUnit{ID: "pow", Name: "P. OF WALES", Type: models.UnitTypeBattleship, Owner: "allied",
+			HullBoxes: 12, CurrentHull: 0, Status: models.UnitStatusSunk}
+		renown := models.NavalUnit{ID: "renown", Name: "RENOWN", Type: models.UnitTypeBattlecruiser, Owner: "allied",
+			HullBoxes: 8, CurrentHull: 0, Status: models.UnitStatusSunk}
+		kgv := models.NavalUnit{ID: "kgv", Name: "KING GEORGE V", Type: models.UnitTypeBattleship, Owner: "allied",
+			HullBoxes: 12, CurrentHull: 0, Status: models.UnitStatusSunk}
+
+		breakdown := calculateVictory(game, []models.NavalUnit{bismarck, pow, kgv}, &models.ConvoyVPBox{}, nil, models.GameEndTurnTrack)
+		if breakdown.VictoryType != models.VictoryTypeStrategic || breakdown.WinnerSide != models.PlayerSideGerman {
+			t.Errorf("Ожидалась стратегическая победа Германии, получено %s/%s", breakdown.WinnerSide, breakdown.VictoryType)
+		}
+
+		// Без King George V стратегической победы нет
+		breakdown = calculateVictory(game, []models.NavalUnit{bismarck, pow, renown}, &models.ConvoyVPBox{}, nil, models.GameEndBismarckNorway)
+		if breakdown.VictoryType == models.VictoryTypeStrategic {
+			t.Error("Стратегическая победа требует потопления King George V")
+		}
+
+		// Одного King George V недостаточно
+		breakdown = calculateVictory(game, []models.NavalUnit{bismarck, kgv}, &models.ConvoyVPBox{}, nil, models.GameEndBismarckFrance)
+		if breakdown.VictoryType == models.VictoryTypeStrategic {
+			t.Error("Стратегическая победа требует потопления двух капитальных кораблей")
+		}
+	})
+
+	t.Run("ZeroVPOverride", func(t *testing.T) {
+		game := newVictoryTestGame()
+		zero := 0
+		game.Settings.VictoryConditions.BismarckFranceVP = &zero
+
+		breakdown := calculateVictory(game, []models.NavalUnit{bismarck}, &models.ConvoyVPBox{}, nil, models.GameEndBismarckFrance)
+		if breakdown.EndConditionVP != 0 {
+			t.Errorf("Модификатор 0 VP должен применяться как заданный, получено %.1f", breakdown.EndConditionVP)
+		}
+
+		game.Settings.VictoryConditions.BismarckFranceVP = nil
+		breakdown = calculateVictory(game, []models.NavalUnit{bismarck}, &models.ConvoyVPBox{}, nil, models.GameEndBismarckFrance)
+		if breakdown.EndConditionVP != -5 {
+			t.Errorf("Незаданный модификатор берется по умолчанию, получено %.1f", breakdown.EndConditionVP)
+		}
+	})
+
+	t.Run("AlliedVictoryOnLowVP", func(t *testing.T) {
+		game := newVictoryTestGame()
+		lowFuel := bismarck
+		lowFuel.Fuel = 3
+
+		breakdown := calculateVictory(game, []models.NavalUnit{lowFuel}, &models.ConvoyVPBox{}, nil, models.GameEndTurnTrack)
+		if breakdown.EndConditionVP != -10 {
+			t.Errorf("Ожидалось -10 VP за конец игры по Треку ходов, получено %.1f", breakdown.EndConditionVP)
+		}
+		if breakdown.WinnerSide != models.PlayerSideAllied {
+			t.Error("При итоге меньше 1 VP побеждают союзники")
+		}
+	})
+}
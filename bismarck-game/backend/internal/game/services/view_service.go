@@ -0,0 +1,89 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+
+	"bismarck-game/backend/internal/game/models"
+	"bismarck-game/backend/pkg/database"
+	"bismarck-game/backend/pkg/logger"
+)
+
+// ViewService строит состояние игры для каждой стороны с учетом тумана войны.
+// Все ответы с состоянием игры, REST и websocket, должны проходить через него
+type ViewService struct {
+	db               *database.Database
+	logger           *logger.Logger
+	unitService      *UnitService
+	taskForceService *TaskForceService
+	convoyService    *ConvoyVPService
+	notifier         GameNotifier
+}
+
+// NewViewService создает новый сервис представлений игроков
+func NewViewService(db *database.Database, logger *logger.Logger, unitService *UnitService, taskForceService *TaskForceService, convoyService *ConvoyVPService, notifier GameNotifier) *ViewService {
+	return &ViewService{
+		db:               db,
+		logger:           logger,
+		unitService:      unitService,
+		taskForceService: taskForceService,
+		convoyService:    convoyService,
+		notifier:         notifier,
+	}
+}
+
+// GetPlayerView возвращает состояние игры, видимое игроку
+func (s *ViewService) GetPlayerView(game *models.Game, userID string) (*models.PlayerView, error) {
+	side := game.GetPlayerRole(userID)
+	if side == "" {
+		return nil, ErrNotGamePlayer
+	}
+
+	navalUnits, err := s.unitService.GetNavalUnitsByGameID(game.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get naval units: %w", err)
+	}
+	airUnits, err := s.unitService.GetAirUnitsByGameID(game.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get air units: %w", err)
+	}
+	taskForces, err := s.taskForceService.GetTaskForcesByGameID(game.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get task forces: %w", err)
+	}
+	markers, err := s.unitService.GetMarkers(game, navalUnits, models.MarkerFilter{VisibleTo: side})
+	if err != nil {
+		return nil, err
+	}
+
+	convoyVP, err := s.convoyService.GetBoxForPlayer(game, userID)
+	if err != nil && !errors.Is(err, ErrConvoyBoxHidden) && !errors.Is(err, ErrConvoyBoxNotFound) {
+		return nil, err
+	}
+
+	return models.BuildPlayerView(game, side, navalUnits, airUnits, taskForces, markers, convoyVP), nil
+}
+
+// PushPlayerViews отправляет каждому игроку его представление игры.
+// Общая рассылка комнаты игры для состояния не используется: она раскрыла бы позиции противника
+func (s *ViewService) PushPlayerViews(game *models.Game) {
+	if s.notifier == nil {
+		return
+	}
+
+	for _, playerID := range []string{game.Player1ID, game.Player2ID} {
+		if playerID == "" {
+			continue
+		}
+		view, err := s.GetPlayerView(game, playerID)
+		if err != nil {
+			s.logger.Error("Failed to build player view", "game_id", game.ID, "player_id", playerID, "error", err)
+			continue
+		}
+		s.notifier.SendNotification(playerID, map[string]interface{}{
+			"type":    "game_state",
+			"game_id": game.ID,
+			"data":    view,
+		})
+	}
+}
@@ -0,0 +1,85 @@
+package services
+
+import (
+	"bismarck-game/backend/internal/game/models"
+	"testing"
+)
+
+func TestBuildPlayerView(t *testing.T) {
+	game := newVictoryTestGame()
+	game.CurrentTurn = 5
+
+	sightedAt := "AB18"
+	lostAt := "AC10"
+	arrival := 9
+	navalUnits := []models.NavalUnit{
+		{ID: "bismarck", Name: "BISMARCK", Owner: "german", Position: "AB20", HullBoxes: 12, CurrentHull: 12,
+			Status: models.UnitStatusActive, DetectionLevel: models.DetectionLevelSighted, LastKnownPos: &sightedAt},
+		{ID: "prinz-eugen", Name: "PRINZ EUGEN", Owner: "german", Position: "AD12", HullBoxes: 8, CurrentHull: 8,
+			Status: models.UnitStatusActive, DetectionLevel: models.DetectionLevelNone},
+		{ID: "hood", Name: "HOOD", Owner: "allied", Position: "AC11", HullBoxes: 10, CurrentHull: 10,
+			Status: models.UnitStatusActive, DetectionLevel: models.DetectionLevelLost, LastKnownPos: &lostAt},
+		{ID: "ramillies", Name: "RAMILLIES", Owner: "allied", HullBoxes: 10, CurrentHull: 10,
+			Status: models.UnitStatusReinforcement, ArrivalTurn: &arrival},
+	}
+	airUnits := []models.AirUnit{
+		{ID: "catalina", Owner: "allied", Position: "AB19"},
+	}
+	taskForces := []models.TaskForce{
+		{ID: "force-h", Owner: "allied", Position: "AC11", Units: []string{"hood"}},
+	}
+	markers := []models.Marker{
+		*models.NewMarker(game.ID, models.MarkerSighted, models.PlayerSideAllied, 5, models.PhaseSearch),
+		*models.NewMarker(game.ID, models.MarkerPatrol, models.PlayerSideGerman, 5, models.PhaseMovement),
+	}
+	convoyVP := &models.ConvoyVPSummary{ConvoyVP: 2}
+
+	t.Run("Allied", func(t *testing.T) {
+		view := models.BuildPlayerView(game, models.PlayerSideAllied, navalUnits, airUnits, taskForces, markers, convoyVP)
+
+		if len(view.NavalUnits) != 2 || len(view.AirUnits) != 1 || len(view.TaskForces) != 1 {
+			t.Errorf("Союзный игрок должен видеть свои юниты полностью, получено %d/%d/%d",
+				len(view.NavalUnits), len(view.AirUnits), len(view.TaskForces))
+		}
+		if len(view.Contacts) != 1 || view.Contacts[0].UnitID != "bismarck" {
+			t.Fatalf("Ожидался один контакт с BISMARCK, получено %v", view.Contacts)
+		}
+		if view.Contacts[0].LastKnownPos != "AB18" {
+			t.Errorf("Контакт должен показывать последнюю известную позицию, а не %s", view.Contacts[0].LastKnownPos)
+		}
+		if len(view.Markers) != 1 || view.Markers[0].Type != models.MarkerSighted {
+			t.Error("Маркер Патруля немецкого игрока не должен быть виден союзному игроку")
+		}
+		if view.ConvoyVP != nil {
+			t.Error("Коробка VP конвоя скрыта от союзного игрока до окончания игры")
+		}
+	})
+
+	t.Run("German", func(t *testing.T) {
+		view := models.BuildPlayerView(game, models.PlayerSideGerman, navalUnits, airUnits, taskForces, markers, convoyVP)
+
+		if len(view.NavalUnits) != 2 || len(view.AirUnits) != 0 || len(view.TaskForces) != 0 {
+			t.Error("Немецкий игрок не должен видеть юниты и Task Forces союзников")
+		}
+		// HOOD потерян на последней известной позиции, подкрепление вне карты не видно
+		if len(view.Contacts) != 1 || view.Contacts[0].UnitID != "hood" || view.Contacts[0].LastKnownPos != "AC10" {
+			t.Errorf("Ожидался контакт с HOOD в AC10, получено %v", view.Contacts)
+		}
+		if view.ConvoyVP == nil {
+			t.Error("Немецкий игрок должен видеть коробку VP конвоя")
+		}
+		if len(view.ContactsAt("AC11")) != 0 {
+			t.Error("Текущая позиция корабля противника не должна раскрываться")
+		}
+	})
+
+	t.Run("Completed", func(t *testing.T) {
+		completed := *game
+		completed.Status = models.GameStatusCompleted
+
+		view := models.BuildPlayerView(&completed, models.PlayerSideAllied, navalUnits, airUnits, taskForces, markers, convoyVP)
+		if view.ConvoyVP == nil {
+			t.Error("После окончания игры коробка VP конвоя раскрывается")
+		}
+	})
+}
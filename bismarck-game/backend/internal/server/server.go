@@ -2,6 +2,7 @@ package server
 
 import (
 	"context"
+	"fmt"
 	"log"
 	"net/http"
 	"os"
@@ -13,6 +14,7 @@ import (
 	"bismarck-game/backend/internal/api/middleware"
 	"bismarck-game/backend/internal/auth"
 	"bismarck-game/backend/internal/config"
+	"bismarck-game/backend/internal/game/services"
 	"bismarck-game/backend/internal/websocket"
 	"bismarck-game/backend/pkg/database"
 	"bismarck-game/backend/pkg/logger"
@@ -31,9 +33,17 @@ type Server struct {
 	redis       *redis.Client
 	authService *auth.AuthService
 	wsHub       *websocket.Hub
+	setup       *services.GameSetupService
+	ships       *services.ShipConfigService
+	gameRoutes  []routeRegistrar
 	startTime   time.Time
 }
 
+// routeRegistrar регистрирует маршруты обработчика
+type routeRegistrar interface {
+	RegisterRoutes(router *mux.Router, jwtSecret string)
+}
+
 func New(cfg *config.Config) *Server {
 	s := &Server{
 		config:    cfg,
@@ -87,10 +97,91 @@ func (s *Server) initializeComponents() error {
 	s.wsHub = websocket.NewHub()
 	go s.wsHub.Run()
 
+	// Создаем игровые сервисы и их обработчики
+	if err := s.initializeGameServices(); err != nil {
+		return err
+	}
+
 	logger.Info("All components initialized successfully")
 	return nil
 }
 
+// initializeGameServices загружает каталог кораблей и сценарии, создает игровые сервисы
+// и обработчики их API
+func (s *Server) initializeGameServices() error {
+	gameLogger := logger.DefaultLogger
+
+	specialRulesService := services.NewGameSpecialRulesService(s.db, gameLogger)
+	if err := specialRulesService.LoadActiveGameRules(); err != nil {
+		return err
+	}
+	shipConfigService := services.NewShipConfigService(specialRulesService)
+	if err := shipConfigService.LoadConfig(s.config.Game.ShipsConfig); err != nil {
+		return err
+	}
+	s.ships = shipConfigService
+
+	dice := services.NewRandomDice()
+	markerService := services.NewMarkerService(s.db, gameLogger)
+	unitService := services.NewUnitService(s.db, gameLogger, markerService)
+	taskForceService := services.NewTaskForceService(s.db, gameLogger, unitService)
+	convoyService := services.NewConvoyVPService(s.db, gameLogger, dice)
+	viewService := services.NewViewService(s.db, gameLogger, unitService, taskForceService, convoyService, s.wsHub)
+	victoryService := services.NewVictoryService(s.db, gameLogger, unitService, convoyService)
+	gameService := services.NewGameService(s.db, gameLogger, unitService, victoryService, s.wsHub)
+	intelligenceService := services.NewIntelligenceService(s.db, gameLogger, unitService, s.wsHub)
+	movementService := services.NewMovementPhaseService(s.db, gameLogger, unitService, intelligenceService, dice, s.wsHub)
+	orderService := services.NewOrderService(s.db, gameLogger, unitService, taskForceService, movementService, s.wsHub)
+	deploymentService := services.NewDeploymentService(s.db, gameLogger, unitService, s.wsHub)
+	optionalUnitService := services.NewOptionalUnitService(s.db, gameLogger, unitService, shipConfigService, dice, s.wsHub)
+	exhaustionService := services.NewCrewExhaustionService(s.db, gameLogger)
+	combatService := services.NewCombatService(s.db, gameLogger, unitService, exhaustionService, convoyService, specialRulesService, dice)
+	adminPhaseService := services.NewAdminPhaseService(s.db, gameLogger, unitService, markerService, intelligenceService, exhaustionService)
+	phaseService := services.NewPhaseService(s.db, gameLogger, gameService, adminPhaseService, markerService, movementService, orderService, deploymentService, viewService)
+
+	scenarioService := services.NewScenarioService(s.db, gameLogger, unitService, taskForceService, shipConfigService)
+	if err := scenarioService.LoadScenarios(s.config.Game.ScenariosDir); err != nil {
+		return err
+	}
+
+	gameAccess := gameAccessResolver{games: gameService}
+	s.setup = services.NewGameSetupService(s.db, gameLogger, scenarioService, convoyService, specialRulesService, viewService, s.wsHub)
+	s.gameRoutes = []routeRegistrar{
+		handlers.NewShipConfigHandler(shipConfigService),
+		handlers.NewUnitHandler(unitService, taskForceService, gameService, gameAccess, movementService, viewService, gameLogger),
+		handlers.NewOrderHandler(orderService, gameAccess, gameLogger),
+		handlers.NewDeploymentHandler(deploymentService, gameAccess, gameLogger),
+		handlers.NewOptionalUnitHandler(optionalUnitService, gameAccess, gameLogger),
+		handlers.NewIntelligenceHandler(intelligenceService, gameAccess, gameLogger),
+		handlers.NewPhaseHandler(phaseService, gameAccess, gameLogger),
+		handlers.NewCombatHandler(combatService, gameAccess, gameLogger),
+	}
+	return nil
+}
+
+// gameAccessResolver определяет доступ пользователя к игре для GameAccessMiddleware
+type gameAccessResolver struct {
+	games *services.GameService
+}
+
+// ResolveGameAccess загружает игру и определяет сторону пользователя в ней.
+// Изменять игру могут только ее игроки
+func (r gameAccessResolver) ResolveGameAccess(gameID, userID string, write bool) (interface{}, error) {
+	game, err := r.games.GetGameByID(gameID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", middleware.ErrGameNotFound, err)
+	}
+
+	access, err := services.ResolveGameAccess(game, userID)
+	if err == nil && write {
+		err = access.RequirePlayer()
+	}
+	if err != nil {
+		return nil, err
+	}
+	return access, nil
+}
+
 func (s *Server) setupRoutes() {
 	// Подключаем middleware
 	s.router.Use(middleware.RecoveryMiddleware())
@@ -105,11 +196,14 @@ func (s *Server) setupRoutes() {
 
 	// Создаем обработчики
 	authHandler := handlers.NewAuthHandler(s.authService)
-	gameHandler := handlers.NewGameHandler(s.db)
+	gameHandler := handlers.NewGameHandler(s.db, s.setup)
 
 	// Регистрируем маршруты
 	authHandler.RegisterRoutes(s.router, s.config.JWT.Secret)
 	gameHandler.RegisterRoutes(s.router, s.config.JWT.Secret)
+	for _, handler := range s.gameRoutes {
+		handler.RegisterRoutes(s.router, s.config.JWT.Secret)
+	}
 
 	// WebSocket маршрут
 	s.router.HandleFunc("/ws", s.handleWebSocket)
@@ -137,9 +231,16 @@ func (s *Server) Start() error {
 		IdleTimeout:  s.config.Server.IdleTimeout.ToDuration(),
 	}
 
+	// Следим за изменениями каталога кораблей
+	stopWatch := make(chan struct{})
+	defer close(stopWatch)
+	if interval := s.config.Game.ShipsReload.ToDuration(); interval > 0 {
+		go s.ships.WatchConfig(s.config.Game.ShipsConfig, interval, stopWatch)
+	}
+
 	// Канал для получения сигналов ОС
 	sigChan := make(chan os.Signal, 1)
-	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
+	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM, syscall.SIGHUP)
 
 	// Запуск сервера в горутине
 	go func() {
@@ -149,8 +250,13 @@ func (s *Server) Start() error {
 		}
 	}()
 
-	// Ожидание сигнала завершения
-	<-sigChan
+	// Ожидание сигнала завершения; SIGHUP перезагружает каталог кораблей
+	for sig := range sigChan {
+		if sig != syscall.SIGHUP {
+			break
+		}
+		s.reloadShipCatalog()
+	}
 	log.Printf("🛑 Shutting down server...")
 
 	// Graceful shutdown с таймаутом
@@ -160,6 +266,20 @@ func (s *Server) Start() error {
 	return s.server.Shutdown(ctx)
 }
 
+// reloadShipCatalog перезагружает каталог кораблей для новых игр
+func (s *Server) reloadShipCatalog() {
+	version, changed, err := s.ships.ReloadConfig(s.config.Game.ShipsConfig)
+	if err != nil {
+		log.Printf("❌ Failed to reload ship catalog: %v", err)
+		return
+	}
+	if !changed {
+		log.Printf("🚢 Ship catalog unchanged (version %s)", version)
+		return
+	}
+	log.Printf("🚢 Ship catalog reloaded (version %s)", version)
+}
+
 // Middleware для логирования запросов
 func (s *Server) loggingMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -275,6 +395,11 @@ func (s *Server) handleRoot(w http.ResponseWriter, r *http.Request) {
                 <li><code>POST /api/games</code> - Создание игры</li>
                 <li><code>GET /api/games/{id}</code> - Информация об игре</li>
                 <li><code>POST /api/games/{id}/join</code> - Присоединение к игре</li>
+                <li><code>GET /api/ships</code> - Каталог кораблей</li>
+                <li><code>GET /api/games/{id}/units</code> - Юниты игры</li>
+                <li><code>GET /api/games/{id}/units/{unitId}/history</code> - Журнал действий юнита</li>
+                <li><code>GET /api/games/{id}/taskforces</code> - Оперативные соединения</li>
+                <li><code>POST /api/games/{id}/orders</code> - Приказы на движение</li>
                 <li><code>GET /ws</code> - WebSocket соединение</li>
             </ul>
         </div>
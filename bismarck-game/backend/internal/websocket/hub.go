@@ -338,22 +338,6 @@ func (h *Hub) cleanupInactiveConnections() {
 	}
 }
 
-// BroadcastGameUpdate рассылает обновление состояния игры
-func (h *Hub) BroadcastGameUpdate(gameID string, update interface{}) {
-	message, err := json.Marshal(map[string]interface{}{
-		"type":      "game_update",
-		"game_id":   gameID,
-		"data":      update,
-		"timestamp": time.Now().Unix(),
-	})
-	if err != nil {
-		logger.Error("Failed to marshal game update", "error", err)
-		return
-	}
-
-	h.BroadcastToRoom(gameID, message)
-}
-
 // BroadcastGameEvent рассылает событие игры
 func (h *Hub) BroadcastGameEvent(gameID string, eventType string, data interface{}) {
 	message, err := json.Marshal(map[string]interface{}{